@@ -0,0 +1,263 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/google/subcommands"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/logging"
+	svc "github.com/jacobbrewer1/puppet-reporter/pkg/services/api"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// alertRulesFormatRules outputs a plain Prometheus rules file.
+	alertRulesFormatRules = "rules"
+
+	// alertRulesFormatOperator outputs a PrometheusRule resource for the Prometheus Operator.
+	alertRulesFormatOperator = "operator"
+)
+
+const (
+	alertHostFailing           = "PuppetHostFailing"
+	alertHostUnresponsive      = "PuppetHostUnresponsive"
+	alertHostRuntimeRegression = "PuppetHostRuntimeRegression"
+	alertIngestionErrors       = "PuppetReportIngestionErrors"
+)
+
+type alertRulesCmd struct {
+	// format is the output format, either rules or operator
+	format string
+
+	// name is the name of the rule group and of the PrometheusRule resource
+	name string
+
+	// namespace is the namespace of the PrometheusRule resource
+	namespace string
+
+	// failures is the number of failed runs in a row before a host is alerted on
+	failures int
+
+	// unresponsive is how long a host can go without reporting before it is alerted on
+	unresponsive time.Duration
+
+	// runtimeFactor is how many times slower than usual a run must be to be a regression
+	runtimeFactor float64
+
+	// runtimeWindow is the window the usual runtime of a host is calculated over
+	runtimeWindow time.Duration
+
+	// ingestionWindow is the window the ingestion errors are counted over
+	ingestionWindow time.Duration
+}
+
+func (a *alertRulesCmd) Name() string {
+	return "alert-rules"
+}
+
+func (a *alertRulesCmd) Synopsis() string {
+	return "Print the Prometheus alerting rules for the exported metrics"
+}
+
+func (a *alertRulesCmd) Usage() string {
+	return `alert-rules [-format rules|operator]:
+  Print the Prometheus alerting rules for the metrics exported by the service. The per host alerts only fire for
+  hosts that are exported with their own metrics, see metrics.hosts and metrics.host_pattern.
+`
+}
+
+func (a *alertRulesCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&a.format, "format", alertRulesFormatRules, "The output format, either rules or operator")
+	f.StringVar(&a.name, "name", "puppet-reporter", "The name of the rule group")
+	f.StringVar(&a.namespace, "namespace", "", "The namespace of the PrometheusRule resource")
+	f.IntVar(&a.failures, "failures", 3, "The number of failed runs in a row before a host is alerted on")
+	f.DurationVar(&a.unresponsive, "unresponsive", 2*time.Hour, "How long a host can go without reporting before it is alerted on")
+	f.Float64Var(&a.runtimeFactor, "runtime-factor", 2, "How many times slower than usual a run must be to be a regression")
+	f.DurationVar(&a.runtimeWindow, "runtime-window", 7*24*time.Hour, "The window the usual runtime of a host is calculated over")
+	f.DurationVar(&a.ingestionWindow, "ingestion-window", 15*time.Minute, "The window the ingestion errors are counted over")
+}
+
+func (a *alertRulesCmd) Execute(_ context.Context, _ *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	doc, err := a.document()
+	if err != nil {
+		slog.Error("Error generating alert rules", slog.String(logging.KeyError, err.Error()))
+		return subcommands.ExitUsageError
+	}
+
+	enc := yaml.NewEncoder(os.Stdout)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		slog.Error("Error encoding alert rules", slog.String(logging.KeyError, err.Error()))
+		return subcommands.ExitFailure
+	}
+
+	if err := enc.Close(); err != nil {
+		slog.Error("Error encoding alert rules", slog.String(logging.KeyError, err.Error()))
+		return subcommands.ExitFailure
+	}
+
+	return subcommands.ExitSuccess
+}
+
+// ruleFile is a Prometheus rules file.
+type ruleFile struct {
+	Groups []ruleGroup `yaml:"groups"`
+}
+
+// prometheusRule is a PrometheusRule resource of the Prometheus Operator.
+type prometheusRule struct {
+	APIVersion string             `yaml:"apiVersion"`
+	Kind       string             `yaml:"kind"`
+	Metadata   prometheusRuleMeta `yaml:"metadata"`
+	Spec       ruleFile           `yaml:"spec"`
+}
+
+type prometheusRuleMeta struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace,omitempty"`
+}
+
+type ruleGroup struct {
+	Name  string      `yaml:"name"`
+	Rules []alertRule `yaml:"rules"`
+}
+
+type alertRule struct {
+	Alert       string            `yaml:"alert"`
+	Expr        string            `yaml:"expr"`
+	For         string            `yaml:"for,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// document returns the document to print for the configured format.
+func (a *alertRulesCmd) document() (any, error) {
+	if err := a.validate(); err != nil {
+		return nil, err
+	}
+
+	rules := ruleFile{
+		Groups: []ruleGroup{
+			{
+				Name:  a.name,
+				Rules: a.rules(),
+			},
+		},
+	}
+
+	switch a.format {
+	case alertRulesFormatRules:
+		return rules, nil
+	case alertRulesFormatOperator:
+		return prometheusRule{
+			APIVersion: "monitoring.coreos.com/v1",
+			Kind:       "PrometheusRule",
+			Metadata: prometheusRuleMeta{
+				Name:      a.name,
+				Namespace: a.namespace,
+			},
+			Spec: rules,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q", a.format)
+	}
+}
+
+func (a *alertRulesCmd) validate() error {
+	switch {
+	case a.name == "":
+		return fmt.Errorf("name is required")
+	case a.failures < 1:
+		return fmt.Errorf("failures must be at least 1")
+	case a.unresponsive <= 0:
+		return fmt.Errorf("unresponsive must be positive")
+	case a.runtimeFactor <= 1:
+		return fmt.Errorf("runtime factor must be greater than 1")
+	case a.runtimeWindow <= 0:
+		return fmt.Errorf("runtime window must be positive")
+	case a.ingestionWindow <= 0:
+		return fmt.Errorf("ingestion window must be positive")
+	}
+	return nil
+}
+
+// rules returns the alerting rules. The metric names are taken from the service so the rules always match the
+// metrics that are exported.
+func (a *alertRulesCmd) rules() []alertRule {
+	consecutiveFailures := svc.MetricName(svc.MetricHostConsecutiveFailures)
+	lastRun := svc.MetricName(svc.MetricHostLastRunTimestamp)
+	hostRuntime := svc.MetricName(svc.MetricHostRuntimeSeconds)
+	ingestionErrors := svc.MetricName(svc.MetricIngestionErrors)
+
+	return []alertRule{
+		{
+			Alert: alertHostFailing,
+			Expr:  fmt.Sprintf("%s >= %d", consecutiveFailures, a.failures),
+			Labels: map[string]string{
+				"severity": "critical",
+			},
+			Annotations: map[string]string{
+				"summary":     "Puppet is failing on {{ $labels.host }}",
+				"description": fmt.Sprintf("The last {{ $value }} puppet runs on {{ $labels.host }} in {{ $labels.environment }} have failed, alerting after %d.", a.failures),
+			},
+		},
+		{
+			Alert: alertHostUnresponsive,
+			Expr:  fmt.Sprintf("time() - %s > %d", lastRun, int64(a.unresponsive.Seconds())),
+			Labels: map[string]string{
+				"severity": "warning",
+			},
+			Annotations: map[string]string{
+				"summary":     "Puppet has not reported from {{ $labels.host }}",
+				"description": fmt.Sprintf("{{ $labels.host }} in {{ $labels.environment }} has not reported a puppet run for more than %s.", promDuration(a.unresponsive)),
+			},
+		},
+		{
+			Alert: alertHostRuntimeRegression,
+			Expr: fmt.Sprintf(
+				"%[1]s > %[2]g * quantile_over_time(0.5, %[1]s[%[3]s])",
+				hostRuntime, a.runtimeFactor, promDuration(a.runtimeWindow),
+			),
+			For: "1h",
+			Labels: map[string]string{
+				"severity": "warning",
+			},
+			Annotations: map[string]string{
+				"summary":     "Puppet runs on {{ $labels.host }} are slower than usual",
+				"description": fmt.Sprintf("The last puppet run on {{ $labels.host }} took {{ $value }}s, more than %g times its median over %s.", a.runtimeFactor, promDuration(a.runtimeWindow)),
+			},
+		},
+		{
+			Alert: alertIngestionErrors,
+			Expr: fmt.Sprintf(
+				"sum by (stage) (increase(%s[%s])) > 0",
+				ingestionErrors, promDuration(a.ingestionWindow),
+			),
+			Labels: map[string]string{
+				"severity": "warning",
+			},
+			Annotations: map[string]string{
+				"summary":     "Puppet reports are failing to be ingested",
+				"description": fmt.Sprintf("{{ $value }} puppet reports failed to be ingested at the {{ $labels.stage }} stage in the last %s, see /ingestion/failures.", promDuration(a.ingestionWindow)),
+			},
+		},
+	}
+}
+
+// promDuration formats the duration the way Prometheus expects it in a range selector.
+func promDuration(d time.Duration) string {
+	switch {
+	case d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	default:
+		return fmt.Sprintf("%ds", int64(d.Seconds()))
+	}
+}
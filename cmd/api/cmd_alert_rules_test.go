@@ -0,0 +1,129 @@
+package main
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	svc "github.com/jacobbrewer1/puppet-reporter/pkg/services/api"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func newTestAlertRulesCmd() *alertRulesCmd {
+	return &alertRulesCmd{
+		format:          alertRulesFormatRules,
+		name:            "puppet-reporter",
+		failures:        3,
+		unresponsive:    2 * time.Hour,
+		runtimeFactor:   2,
+		runtimeWindow:   7 * 24 * time.Hour,
+		ingestionWindow: 15 * time.Minute,
+	}
+}
+
+func TestAlertRules_Names(t *testing.T) {
+	names := make([]string, 0)
+	for _, r := range newTestAlertRulesCmd().rules() {
+		names = append(names, r.Alert)
+	}
+
+	require.ElementsMatch(t, []string{
+		alertHostFailing,
+		alertHostUnresponsive,
+		alertHostRuntimeRegression,
+		alertIngestionErrors,
+	}, names)
+}
+
+func TestAlertRules_MetricNames(t *testing.T) {
+	// The names are those of the collectors the service registered, so a renamed or removed metric fails the rules.
+	exported := make(map[string]struct{})
+	for _, name := range svc.RegisteredMetricNames() {
+		exported[name] = struct{}{}
+	}
+	require.NotEmpty(t, exported)
+
+	metricReg := regexp.MustCompile(`puppet_reporter_\w+`)
+	for _, r := range newTestAlertRulesCmd().rules() {
+		used := metricReg.FindAllString(r.Expr, -1)
+		require.NotEmpty(t, used, "rule %s does not use any metric", r.Alert)
+
+		for _, m := range used {
+			require.Contains(t, exported, m, "rule %s uses unknown metric %s", r.Alert, m)
+		}
+	}
+}
+
+func TestAlertRules_Document(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		wantKind string
+		wantErr  bool
+	}{
+		{
+			name:   "rules file",
+			format: alertRulesFormatRules,
+		},
+		{
+			name:     "prometheus operator",
+			format:   alertRulesFormatOperator,
+			wantKind: "PrometheusRule",
+		},
+		{
+			name:    "unknown format",
+			format:  "unknown",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestAlertRulesCmd()
+			a.format = tt.format
+
+			doc, err := a.document()
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			out, err := yaml.Marshal(doc)
+			require.NoError(t, err)
+
+			got := new(struct {
+				Kind   string   `yaml:"kind"`
+				Groups []any    `yaml:"groups"`
+				Spec   ruleFile `yaml:"spec"`
+			})
+			require.NoError(t, yaml.Unmarshal(out, got))
+			require.Equal(t, tt.wantKind, got.Kind)
+
+			if tt.wantKind == "" {
+				require.Len(t, got.Groups, 1)
+			} else {
+				require.Len(t, got.Spec.Groups, 1)
+			}
+		})
+	}
+}
+
+func TestPromDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{d: 7 * 24 * time.Hour, want: "7d"},
+		{d: 2 * time.Hour, want: "2h"},
+		{d: 90 * time.Minute, want: "90m"},
+		{d: 45 * time.Second, want: "45s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			require.Equal(t, tt.want, promDuration(tt.d))
+		})
+	}
+}
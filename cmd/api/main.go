@@ -11,7 +11,9 @@ import (
 
 	"github.com/google/subcommands"
 	"github.com/jacobbrewer1/puppet-reporter/pkg/logging"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/server"
 	"github.com/jacobbrewer1/puppet-reporter/pkg/utils"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/version"
 )
 
 const (
@@ -28,8 +30,11 @@ func main() {
 	subcommands.Register(subcommands.FlagsCommand(), "")
 	subcommands.Register(subcommands.CommandsCommand(), "")
 
-	subcommands.Register(new(versionCmd), "")
-	subcommands.Register(new(serveCmd), "")
+	subcommands.Register(new(version.Cmd), "")
+	subcommands.Register(server.NewServeCmd(utils.AppName(appNameSuffix), true, false), "")
+	subcommands.Register(new(server.MigrateCmd), "")
+	subcommands.Register(new(server.TokenCmd), "")
+	subcommands.Register(new(alertRulesCmd), "")
 
 	flag.Parse()
 
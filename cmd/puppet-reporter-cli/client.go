@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jacobbrewer1/puppet-reporter/pkg/apis/specs/api"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/utils"
+	"github.com/jacobbrewer1/uhttp/common"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+
+	// contentTypeReport is the content type the reports are uploaded with, the body is the report as puppet writes it.
+	contentTypeReport = "application/x-www-form-urlencoded"
+)
+
+// clientFlags are the flags of the commands that call the API. The endpoint and token can also be set in the config
+// file, or with the PUPPET_REPORTER_ENDPOINT and PUPPET_REPORTER_TOKEN environment variables, the flags win.
+type clientFlags struct {
+	// configLocation is the location of the config file
+	configLocation string
+
+	// endpoint is the URL of the API
+	endpoint string
+
+	// token is the API token the requests are authorised with
+	token string
+
+	// timeout is how long a request can take
+	timeout time.Duration
+}
+
+func (c *clientFlags) setFlags(f *flag.FlagSet) {
+	f.StringVar(&c.configLocation, "config", defaultConfigLocation(), "The location of the config file")
+	f.StringVar(&c.endpoint, "endpoint", "", "The URL of the API, such as https://puppet-reporter.example.com")
+	f.StringVar(&c.token, "token", "", "The API token")
+	f.DurationVar(&c.timeout, "timeout", 30*time.Second, "How long a request can take")
+}
+
+// defaultConfigLocation returns the config file in the config directory of the user.
+func defaultConfigLocation() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "cli.json"
+	}
+	return filepath.Join(dir, "puppet-reporter", "cli.json")
+}
+
+// newClient returns a client of the API at the endpoint, sending the token with each request.
+func (c *clientFlags) newClient() (*api.ClientWithResponses, error) {
+	server, opts, err := c.clientOptions()
+	if err != nil {
+		return nil, err
+	}
+	return api.NewClientWithResponses(server, opts...)
+}
+
+// clientOptions returns the server and the options of a client of the API, from the flags or else the config file.
+func (c *clientFlags) clientOptions() (string, []api.ClientOption, error) {
+	v, err := utils.LoadConfig(c.configLocation)
+	if err != nil {
+		return "", nil, fmt.Errorf("error loading config: %w", err)
+	}
+
+	endpoint := c.endpoint
+	if endpoint == "" {
+		endpoint = v.GetString("endpoint")
+	}
+	if endpoint == "" {
+		return "", nil, errors.New("no endpoint, set it with -endpoint or in the config file")
+	}
+
+	token := c.token
+	if token == "" {
+		token = v.GetString("token")
+	}
+
+	// The paths of the operations are relative to the endpoint, which needs a trailing slash to keep its own path.
+	return strings.TrimRight(endpoint, "/") + "/", []api.ClientOption{
+		api.WithHTTPClient(&http.Client{Timeout: c.timeout}),
+		api.WithRequestEditorFn(func(_ context.Context, req *http.Request) error {
+			if token != "" {
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+			return nil
+		}),
+	}, nil
+}
+
+// outputFlags are the flags of the commands that print results.
+type outputFlags struct {
+	// output is the format the results are printed in
+	output string
+
+	// out is where the results are printed, stdout if nil
+	out io.Writer
+}
+
+func (o *outputFlags) setFlags(f *flag.FlagSet) {
+	f.StringVar(&o.output, "output", outputTable, "The format to print the results in, table or json")
+}
+
+// validate returns an error if the output format is not known.
+func (o *outputFlags) validate() error {
+	switch o.output {
+	case outputTable, outputJSON:
+		return nil
+	default:
+		return fmt.Errorf("output %q is not table or json", o.output)
+	}
+}
+
+// stdout returns where the results are printed.
+func (o *outputFlags) stdout() io.Writer {
+	if o.out == nil {
+		return os.Stdout
+	}
+	return o.out
+}
+
+// printJSON prints the value as indented JSON.
+func (o *outputFlags) printJSON(v any) error {
+	enc := json.NewEncoder(o.stdout())
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// responseError returns the error of a response the API did not succeed with, with the detail of the error message it
+// sent if there is one.
+func responseError(resp *http.Response, body []byte) error {
+	msg := new(common.ErrorMessage)
+	if err := json.Unmarshal(body, msg); err == nil && msg.Detail != "" {
+		return fmt.Errorf("%s: %s", resp.Status, msg.Detail)
+	}
+	return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+}
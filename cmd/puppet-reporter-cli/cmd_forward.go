@@ -0,0 +1,451 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/subcommands"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/apis/specs/api"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/logging"
+	"github.com/jacobbrewer1/uhttp/common"
+)
+
+const (
+	// forwardStateFile is the file in the spool directory that records the reports that were spooled.
+	forwardStateFile = "state.json"
+
+	// forwardRejectedDir is the directory in the spool directory the reports the API rejected are moved to.
+	forwardRejectedDir = "rejected"
+
+	// rejectedDetailParse and rejectedDetailRead are the details of the 400 the API answers a report it cannot parse
+	// or read with.
+	rejectedDetailParse = "error parsing report"
+	rejectedDetailRead  = "error reading file"
+
+	// forwardSpoolExt is the extension of the spooled reports.
+	forwardSpoolExt = ".yaml"
+)
+
+type forwardCmd struct {
+	clientFlags
+
+	// lastRunReport is the lastrunreport file of the agent
+	lastRunReport string
+
+	// reportDir is the reportdir of the agent, the reports are in a directory for each host
+	reportDir string
+
+	// spoolDir is where the reports are kept until the API has them
+	spoolDir string
+
+	// interval is how often the reports are looked for
+	interval time.Duration
+
+	// maxBackoff is the longest the API is waited for when it cannot be reached
+	maxBackoff time.Duration
+
+	// once forwards the reports once and exits, for running from cron
+	once bool
+}
+
+func (c *forwardCmd) Name() string {
+	return "forward"
+}
+
+func (c *forwardCmd) Synopsis() string {
+	return "Forward the reports the agent writes to the API"
+}
+
+func (c *forwardCmd) Usage() string {
+	return `forward [-endpoint url] [-token token] [-lastrunreport file] [-reportdir dir] [-spool dir] [-once]:
+  Forward the reports the agent writes to the API.
+
+  The reports are copied to the spool directory when they are found, and removed from it when the API has them. When
+  the API cannot be reached they are kept and sent again, waiting longer after each failure up to -max-backoff.
+  Reports the API cannot parse are moved to the rejected directory of the spool directory. Any other error, such as
+  a revoked token, keeps the reports in the spool to be sent again.
+
+  By default the reports are looked for every -interval until the command is stopped, to run as a service. With -once
+  they are looked for and sent once, to run from cron, and the reports that could not be sent are sent on the next run.
+  It exits with a failure if any could not be sent.
+`
+}
+
+func (c *forwardCmd) SetFlags(f *flag.FlagSet) {
+	c.clientFlags.setFlags(f)
+	f.StringVar(&c.lastRunReport, "lastrunreport", "", "The lastrunreport file of the agent, such as /opt/puppetlabs/puppet/cache/state/last_run_report.yaml")
+	f.StringVar(&c.reportDir, "reportdir", "", "The reportdir of the agent, such as /opt/puppetlabs/puppet/cache/reports")
+	f.StringVar(&c.spoolDir, "spool", "/var/spool/puppet-reporter", "The directory the reports are kept in until the API has them")
+	f.DurationVar(&c.interval, "interval", 30*time.Second, "How often the reports are looked for")
+	f.DurationVar(&c.maxBackoff, "max-backoff", 10*time.Minute, "The longest to wait before sending again when the API cannot be reached")
+	f.BoolVar(&c.once, "once", false, "Forward the reports once and exit")
+}
+
+func (c *forwardCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if c.lastRunReport == "" && c.reportDir == "" {
+		slog.Error("A lastrunreport file or a reportdir is required")
+		return subcommands.ExitUsageError
+	} else if c.interval <= 0 || c.maxBackoff <= 0 {
+		slog.Error("The interval and max backoff must be positive")
+		return subcommands.ExitUsageError
+	}
+
+	server, opts, err := c.clientOptions()
+	if err != nil {
+		slog.Error("Error creating client", slog.String(logging.KeyError, err.Error()))
+		return subcommands.ExitUsageError
+	}
+
+	client, err := api.NewClient(server, opts...)
+	if err != nil {
+		slog.Error("Error creating client", slog.String(logging.KeyError, err.Error()))
+		return subcommands.ExitUsageError
+	}
+
+	fw, err := newForwarder(client, c.spoolDir, c.lastRunReport, c.reportDir)
+	if err != nil {
+		slog.Error("Error opening spool", slog.String(logging.KeyError, err.Error()))
+		return subcommands.ExitFailure
+	}
+
+	if c.once {
+		if !fw.forward(ctx) {
+			return subcommands.ExitFailure
+		}
+		return subcommands.ExitSuccess
+	}
+
+	backoff := time.Duration(0)
+	for {
+		wait := c.interval
+		if !fw.forward(ctx) {
+			backoff = nextBackoff(backoff, c.interval, c.maxBackoff)
+			wait = max(backoff, fw.retryAfter)
+			slog.Warn("Reports could not be sent, retrying", slog.Duration("wait", wait))
+		} else {
+			backoff = 0
+		}
+
+		select {
+		case <-ctx.Done():
+			return subcommands.ExitSuccess
+		case <-time.After(wait):
+		}
+	}
+}
+
+// nextBackoff doubles the backoff from the interval up to the max, with a jitter so the agents that lost the API at the
+// same time do not all send again at the same time.
+func nextBackoff(prev, interval, maxBackoff time.Duration) time.Duration {
+	next := min(max(interval, 2*prev), maxBackoff)
+	jitter := time.Duration(rand.Int64N(int64(next)/5 + 1))
+	return next - next/10 + jitter
+}
+
+// forwardState records the reports that were spooled, so they are not spooled again. The files are keyed by their path
+// with the time they were modified, as the lastrunreport is the same file for every run.
+type forwardState struct {
+	Seen map[string]time.Time `json:"seen"`
+}
+
+// forwarder copies the reports the agent writes to the spool, and sends the spooled reports to the API.
+type forwarder struct {
+	client        api.ClientInterface
+	spoolDir      string
+	lastRunReport string
+	reportDir     string
+	state         *forwardState
+
+	// retryAfter is how long the API asked to wait before sending again, from the last reports that were sent.
+	retryAfter time.Duration
+}
+
+func newForwarder(client api.ClientInterface, spoolDir, lastRunReport, reportDir string) (*forwarder, error) {
+	if err := os.MkdirAll(filepath.Join(spoolDir, forwardRejectedDir), 0o750); err != nil {
+		return nil, fmt.Errorf("error creating spool directory: %w", err)
+	}
+
+	state := &forwardState{Seen: make(map[string]time.Time)}
+	bts, err := os.ReadFile(filepath.Join(spoolDir, forwardStateFile))
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("error reading state: %w", err)
+	default:
+		if err := json.Unmarshal(bts, state); err != nil {
+			return nil, fmt.Errorf("error decoding state: %w", err)
+		}
+		if state.Seen == nil {
+			state.Seen = make(map[string]time.Time)
+		}
+	}
+
+	return &forwarder{
+		client:        client,
+		spoolDir:      spoolDir,
+		lastRunReport: lastRunReport,
+		reportDir:     reportDir,
+		state:         state,
+	}, nil
+}
+
+// forward spools the new reports and sends the spooled reports. It returns false if any report could not be spooled or
+// sent, they are tried again the next time.
+func (f *forwarder) forward(ctx context.Context) bool {
+	ok := true
+	if err := f.scan(); err != nil {
+		slog.Error("Error looking for reports", slog.String(logging.KeyError, err.Error()))
+		ok = false
+	}
+
+	if err := f.flush(ctx); err != nil {
+		slog.Error("Error sending reports", slog.String(logging.KeyError, err.Error()))
+		ok = false
+	}
+
+	return ok
+}
+
+// sources returns the report files of the agent with the time they were modified.
+func (f *forwarder) sources() (map[string]time.Time, error) {
+	files := make(map[string]time.Time)
+
+	if f.lastRunReport != "" {
+		info, err := os.Stat(f.lastRunReport)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			// The agent has not run yet.
+		case err != nil:
+			return nil, fmt.Errorf("error reading lastrunreport: %w", err)
+		default:
+			files[f.lastRunReport] = info.ModTime().UTC()
+		}
+	}
+
+	if f.reportDir != "" {
+		err := filepath.WalkDir(f.reportDir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			} else if d.IsDir() || !strings.HasSuffix(d.Name(), forwardSpoolExt) {
+				return nil
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			files[path] = info.ModTime().UTC()
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error reading reportdir: %w", err)
+		}
+	}
+
+	return files, nil
+}
+
+// scan copies the reports that are new or were modified since they were last seen to the spool.
+func (f *forwarder) scan() error {
+	files, err := f.sources()
+	if err != nil {
+		return err
+	}
+
+	// The files that are no longer there are forgotten, so the state does not grow as the agent removes old reports.
+	seen := make(map[string]time.Time, len(files))
+	var errs []error
+	for path, modTime := range files {
+		if last, ok := f.state.Seen[path]; ok && last.Equal(modTime) {
+			seen[path] = modTime
+			continue
+		}
+
+		if err := f.spool(path); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		seen[path] = modTime
+	}
+
+	f.state.Seen = seen
+	if err := f.saveState(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// spool copies the report to the spool. The spooled reports are named by when they were spooled, so they are sent in
+// the order they were found.
+func (f *forwarder) spool(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading report %s: %w", path, err)
+	} else if len(content) == 0 {
+		// The agent is still writing the report, it is spooled once it is modified again.
+		return nil
+	}
+
+	sum := sha256.Sum256(content)
+	name := time.Now().UTC().Format("20060102T150405.000000000Z") + "-" + hex.EncodeToString(sum[:6]) + forwardSpoolExt
+	if err := writeFileAtomic(filepath.Join(f.spoolDir, name), content); err != nil {
+		return fmt.Errorf("error spooling report %s: %w", path, err)
+	}
+
+	slog.Info("Report spooled", slog.String("file", path), slog.String("spooled", name))
+	return nil
+}
+
+func (f *forwarder) saveState() error {
+	bts, err := json.Marshal(f.state)
+	if err != nil {
+		return fmt.Errorf("error encoding state: %w", err)
+	}
+
+	if err := writeFileAtomic(filepath.Join(f.spoolDir, forwardStateFile), bts); err != nil {
+		return fmt.Errorf("error saving state: %w", err)
+	}
+
+	return nil
+}
+
+// spooled returns the names of the spooled reports in the order they were spooled.
+func (f *forwarder) spooled() ([]string, error) {
+	entries, err := os.ReadDir(f.spoolDir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading spool: %w", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e.Type().IsRegular() && !strings.HasPrefix(e.Name(), ".") && strings.HasSuffix(e.Name(), forwardSpoolExt) {
+			names = append(names, e.Name())
+		}
+	}
+	slices.Sort(names)
+
+	return names, nil
+}
+
+// flush sends the spooled reports to the API, in the order they were spooled. It stops at the first report that could
+// not be sent, as the rest would not be sent either.
+func (f *forwarder) flush(ctx context.Context) error {
+	f.retryAfter = 0
+
+	names, err := f.spooled()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		path := filepath.Join(f.spoolDir, name)
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("error reading spooled report %s: %w", name, err)
+		}
+
+		resp, err := f.client.UploadReportWithBody(ctx, nil, contentTypeReport, bytes.NewReader(content))
+		if err != nil {
+			return fmt.Errorf("error sending report %s: %w", name, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+
+		switch {
+		case resp.StatusCode >= 200 && resp.StatusCode <= 299:
+			slog.Info("Report sent", slog.String("spooled", name))
+		case resp.StatusCode == http.StatusConflict:
+			slog.Info("Report was already sent", slog.String("spooled", name))
+		case reportRejected(resp, body):
+			// The API will not take the report however many times it is sent, so it is put aside for someone to look at.
+			slog.Error("Report was rejected, moving it to the rejected directory",
+				slog.String("spooled", name),
+				slog.String(logging.KeyError, responseError(resp, body).Error()),
+			)
+			if err := os.Rename(path, filepath.Join(f.spoolDir, forwardRejectedDir, name)); err != nil {
+				return fmt.Errorf("error moving rejected report %s: %w", name, err)
+			}
+			continue
+		default:
+			// Anything else, such as a revoked token or a wrong endpoint, is fixed without changing the report, so it
+			// is kept and sent again once the API takes it.
+			if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+				f.retryAfter = time.Duration(secs) * time.Second
+			}
+			return fmt.Errorf("error sending report %s: %w", name, responseError(resp, body))
+		}
+
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("error removing sent report %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// reportRejected returns true if the API rejected the report itself, as it could not be read or parsed. The API gives
+// the reason in the details of a 400, a 400 for any other reason is not the fault of the report.
+func reportRejected(resp *http.Response, body []byte) bool {
+	if resp.StatusCode != http.StatusBadRequest {
+		return false
+	}
+
+	msg := new(common.ErrorMessage)
+	if err := json.Unmarshal(body, msg); err != nil {
+		return false
+	}
+
+	for _, detail := range msg.Details {
+		if detail == rejectedDetailParse || detail == rejectedDetailRead {
+			return true
+		}
+	}
+
+	return false
+}
+
+// writeFileAtomic writes the file with a temporary file that is renamed, so it is never read half written.
+func writeFileAtomic(path string, content []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	// The temporary file only remains if the rename is not reached.
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/apis/specs/api"
+	svc "github.com/jacobbrewer1/puppet-reporter/pkg/services/api"
+	"github.com/stretchr/testify/require"
+)
+
+// forwardTestServer is an API that answers the uploads with the status it is set to, and records the reports it took.
+type forwardTestServer struct {
+	mut      sync.Mutex
+	status   int
+	body     string
+	received []string
+}
+
+func (s *forwardTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	body, _ := io.ReadAll(r.Body)
+	if s.status == http.StatusCreated {
+		s.received = append(s.received, string(body))
+	}
+	w.WriteHeader(s.status)
+	_, _ = io.WriteString(w, s.body)
+}
+
+func (s *forwardTestServer) setStatus(status int, body string) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	s.status = status
+	s.body = body
+}
+
+func TestForwarder_Forward(t *testing.T) {
+	srvHandler := &forwardTestServer{status: http.StatusServiceUnavailable}
+	srv := httptest.NewServer(srvHandler)
+	defer srv.Close()
+
+	client, err := api.NewClient(srv.URL)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	spoolDir := filepath.Join(dir, "spool")
+	lastRunReport := filepath.Join(dir, "last_run_report.yaml")
+	reportDir := filepath.Join(dir, "reports")
+	require.NoError(t, os.MkdirAll(filepath.Join(reportDir, "web-1"), 0o750))
+
+	require.NoError(t, os.WriteFile(lastRunReport, []byte("run 1"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(reportDir, "web-1", "202401021504.yaml"), []byte("run 0"), 0o600))
+
+	fw, err := newForwarder(client, spoolDir, lastRunReport, reportDir)
+	require.NoError(t, err)
+
+	spooled := func() []string {
+		names, err := fw.spooled()
+		require.NoError(t, err)
+		return names
+	}
+
+	// The API is down, so the reports are kept in the spool.
+	require.False(t, fw.forward(context.Background()))
+	require.Len(t, spooled(), 2)
+
+	// The reports that were seen are not spooled again.
+	require.False(t, fw.forward(context.Background()))
+	require.Len(t, spooled(), 2)
+
+	// A forwarder that is started again knows the reports that were seen.
+	fw, err = newForwarder(client, spoolDir, lastRunReport, reportDir)
+	require.NoError(t, err)
+
+	srvHandler.setStatus(http.StatusCreated, "")
+	require.True(t, fw.forward(context.Background()))
+	require.Empty(t, spooled())
+	require.ElementsMatch(t, []string{"run 0", "run 1"}, srvHandler.received)
+
+	// The lastrunreport is written again by the next run.
+	require.NoError(t, os.WriteFile(lastRunReport, []byte("run 2"), 0o600))
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(lastRunReport, later, later))
+
+	// A revoked token is not the fault of the report, so it is kept to be sent again.
+	srvHandler.setStatus(http.StatusUnauthorized, `{"detail":"token revoked"}`)
+	require.False(t, fw.forward(context.Background()))
+	require.Len(t, spooled(), 1)
+
+	// Neither is a 400 for another reason than the report.
+	srvHandler.setStatus(http.StatusBadRequest, `{"detail":"invalid format","details":["invalid summary"]}`)
+	require.False(t, fw.forward(context.Background()))
+	require.Len(t, spooled(), 1)
+
+	srvHandler.setStatus(http.StatusBadRequest, `{"detail":"parsing yaml: boom","details":["error parsing report"]}`)
+	require.True(t, fw.forward(context.Background()))
+	require.Empty(t, spooled())
+
+	rejected, err := os.ReadDir(filepath.Join(spoolDir, forwardRejectedDir))
+	require.NoError(t, err)
+	require.Len(t, rejected, 1)
+}
+
+func TestForwarder_QueuedUploads(t *testing.T) {
+	// The API ingests asynchronously, so the uploads are answered from the queue endpoint.
+	mut := new(sync.Mutex)
+	queued := make([]string, 0)
+	r := mux.NewRouter()
+	r.HandleFunc("/reports/queue", func(w http.ResponseWriter, r *http.Request) {
+		mut.Lock()
+		defer mut.Unlock()
+
+		body, _ := io.ReadAll(r.Body)
+		queued = append(queued, string(body))
+		w.WriteHeader(http.StatusAccepted)
+	}).Methods(http.MethodPost)
+
+	srv := httptest.NewServer(svc.QueueUploads(r))
+	defer srv.Close()
+
+	client, err := api.NewClient(srv.URL)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	lastRunReport := filepath.Join(dir, "last_run_report.yaml")
+	reportDir := filepath.Join(dir, "reports")
+	require.NoError(t, os.MkdirAll(reportDir, 0o750))
+	require.NoError(t, os.WriteFile(lastRunReport, []byte("run 1"), 0o600))
+
+	fw, err := newForwarder(client, filepath.Join(dir, "spool"), lastRunReport, reportDir)
+	require.NoError(t, err)
+
+	require.True(t, fw.forward(context.Background()))
+
+	names, err := fw.spooled()
+	require.NoError(t, err)
+	require.Empty(t, names)
+	require.Equal(t, []string{"run 1"}, queued)
+}
+
+func TestNextBackoff(t *testing.T) {
+	interval := 10 * time.Second
+	maxBackoff := time.Minute
+
+	backoff := time.Duration(0)
+	for _, want := range []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second, time.Minute, time.Minute} {
+		backoff = nextBackoff(backoff, interval, maxBackoff)
+		require.InDelta(t, want, backoff, float64(want)/10)
+
+		// The next backoff is doubled from the one without the jitter.
+		backoff = want
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/subcommands"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/apis/specs/api"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/logging"
+)
+
+const (
+	hostsStatus = "status"
+)
+
+type hostsCmd struct {
+	clientFlags
+	outputFlags
+
+	// host is a regular expression the hosts are filtered by
+	host string
+
+	// environment filters the hosts by the environment of their latest report
+	environment string
+
+	// state filters the hosts by the state of their latest report
+	state string
+}
+
+func (c *hostsCmd) Name() string {
+	return "hosts"
+}
+
+func (c *hostsCmd) Synopsis() string {
+	return "Show the status of the hosts"
+}
+
+func (c *hostsCmd) Usage() string {
+	return `hosts [-endpoint url] [-token token] [-output table|json] status:
+  Show the status of the hosts.
+
+  status [-host regexp] [-env env] [-state state]
+                Show the state of the latest report of each host and how long ago it was.
+`
+}
+
+func (c *hostsCmd) SetFlags(f *flag.FlagSet) {
+	c.clientFlags.setFlags(f)
+	c.outputFlags.setFlags(f)
+	f.StringVar(&c.host, "host", "", "Only show the hosts that match the regular expression")
+	f.StringVar(&c.environment, "env", "", "Only show the hosts whose latest report is of the environment")
+	f.StringVar(&c.state, "state", "", "Only show the hosts whose latest report has the state, changed, failed or unchanged")
+}
+
+func (c *hostsCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	args := f.Args()
+	if len(args) != 1 || args[0] != hostsStatus {
+		f.Usage()
+		return subcommands.ExitUsageError
+	}
+
+	if err := c.validate(); err != nil {
+		slog.Error("Invalid output", slog.String(logging.KeyError, err.Error()))
+		return subcommands.ExitUsageError
+	}
+
+	client, err := c.newClient()
+	if err != nil {
+		slog.Error("Error creating client", slog.String(logging.KeyError, err.Error()))
+		return subcommands.ExitUsageError
+	}
+
+	return c.status(ctx, client)
+}
+
+// nodesQuery returns the PuppetDB query of the nodes the flags filter, empty if they filter none.
+func (c *hostsCmd) nodesQuery() (string, error) {
+	conds := make([]any, 0, 3)
+	if c.host != "" {
+		conds = append(conds, []any{"~", "certname", c.host})
+	}
+	if c.environment != "" {
+		conds = append(conds, []any{"=", "report_environment", c.environment})
+	}
+	if c.state != "" {
+		conds = append(conds, []any{"=", "latest_report_status", strings.ToLower(c.state)})
+	}
+
+	var query any
+	switch len(conds) {
+	case 0:
+		return "", nil
+	case 1:
+		query = conds[0]
+	default:
+		query = append([]any{"and"}, conds...)
+	}
+
+	bts, err := json.Marshal(query)
+	if err != nil {
+		return "", fmt.Errorf("error encoding query: %w", err)
+	}
+
+	return string(bts), nil
+}
+
+// status prints the state of the latest report of each host.
+func (c *hostsCmd) status(ctx context.Context, client api.ClientWithResponsesInterface) subcommands.ExitStatus {
+	query, err := c.nodesQuery()
+	if err != nil {
+		slog.Error("Invalid filter", slog.String(logging.KeyError, err.Error()))
+		return subcommands.ExitUsageError
+	}
+
+	orderBy := `[{"field":"certname","order":"asc"}]`
+	params := &api.QueryPDBNodesParams{
+		OrderBy: &orderBy,
+	}
+	if query != "" {
+		params.Query = &query
+	}
+
+	resp, err := client.QueryPDBNodesWithResponse(ctx, params)
+	if err != nil {
+		slog.Error("Error getting hosts", slog.String(logging.KeyError, err.Error()))
+		return subcommands.ExitFailure
+	} else if resp.JSON200 == nil {
+		slog.Error("Error getting hosts", slog.String(logging.KeyError, responseError(resp.HTTPResponse, resp.Body).Error()))
+		return subcommands.ExitFailure
+	}
+
+	if c.output == outputJSON {
+		err = c.printJSON(resp.JSON200)
+	} else {
+		err = c.printNodes(*resp.JSON200, time.Now())
+	}
+	if err != nil {
+		slog.Error("Error printing hosts", slog.String(logging.KeyError, err.Error()))
+		return subcommands.ExitFailure
+	}
+
+	return subcommands.ExitSuccess
+}
+
+// printNodes prints the nodes as a table, with how long before now their latest report was.
+func (c *hostsCmd) printNodes(nodes api.PdbResults, now time.Time) error {
+	w := tabwriter.NewWriter(c.stdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "HOST\tENVIRONMENT\tSTATUS\tLAST REPORT\tAGE")
+
+	for _, node := range nodes {
+		lastReport, _ := node["report_timestamp"].(string)
+
+		age := ""
+		if t, err := time.Parse(time.RFC3339, lastReport); err == nil {
+			age = now.Sub(t).Truncate(time.Second).String()
+		}
+
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%s\t%s\t%s\n",
+			nodeField(node, "certname"),
+			nodeField(node, "report_environment"),
+			nodeField(node, "latest_report_status"),
+			lastReport,
+			age,
+		)
+	}
+
+	return w.Flush()
+}
+
+// nodeField returns the string field of the node, empty if it is null.
+func nodeField(node map[string]any, field string) string {
+	s, _ := node[field].(string)
+	return s
+}
@@ -0,0 +1,385 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/subcommands"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/apis/specs/api"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/logging"
+)
+
+const (
+	reportsList   = "list"
+	reportsGet    = "get"
+	reportsUpload = "upload"
+)
+
+// factFlags are the fact filters given with -fact, which can be given more than once.
+type factFlags []string
+
+func (f *factFlags) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *factFlags) Set(s string) error {
+	*f = append(*f, s)
+	return nil
+}
+
+type reportsCmd struct {
+	clientFlags
+	outputFlags
+
+	// host filters the listed reports by the host
+	host string
+
+	// environment filters the listed reports by the environment
+	environment string
+
+	// state filters the listed reports by the state
+	state string
+
+	// from filters the listed reports to those executed at or after the time
+	from string
+
+	// to filters the listed reports to those executed at or before the time
+	to string
+
+	// query is the search query of the listed reports
+	query string
+
+	// facts filter the listed reports by the facts of the host
+	facts factFlags
+
+	// limit is the most reports that are listed
+	limit int
+}
+
+func (c *reportsCmd) Name() string {
+	return "reports"
+}
+
+func (c *reportsCmd) Synopsis() string {
+	return "List, show or upload the reports"
+}
+
+func (c *reportsCmd) Usage() string {
+	return `reports [-endpoint url] [-token token] [-output table|json] list|get <hash>|upload <file...>:
+  List, show or upload the reports.
+
+  list [-host host] [-env env] [-state state] [-from time] [-to time] [-q query] [-fact name=value] [-limit 50]
+                      Show the latest reports that match the filters.
+  get <hash>          Show the report and its resources.
+  upload <file...>    Upload the reports in the files, in the YAML puppet writes them in, and show whether the
+                      status of each host changed.
+
+  The times are RFC 3339, such as 2024-01-02T15:04:05Z.
+`
+}
+
+func (c *reportsCmd) SetFlags(f *flag.FlagSet) {
+	c.clientFlags.setFlags(f)
+	c.outputFlags.setFlags(f)
+	f.StringVar(&c.host, "host", "", "Only list the reports of the host, * matches any characters")
+	f.StringVar(&c.environment, "env", "", "Only list the reports of the environment")
+	f.StringVar(&c.state, "state", "", "Only list the reports with the state, changed, failed, skipped or unchanged")
+	f.StringVar(&c.from, "from", "", "Only list the reports executed at or after the time")
+	f.StringVar(&c.to, "to", "", "Only list the reports executed at or before the time")
+	f.StringVar(&c.query, "q", "", "Only list the reports that match the search query, such as state:failed AND runtime>300")
+	f.Var(&c.facts, "fact", "Only list the reports of the hosts with the fact, as name=value. Can be given more than once")
+	f.IntVar(&c.limit, "limit", 50, "The most reports to list")
+}
+
+func (c *reportsCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	args := f.Args()
+	if len(args) == 0 {
+		f.Usage()
+		return subcommands.ExitUsageError
+	}
+
+	if err := c.validate(); err != nil {
+		slog.Error("Invalid output", slog.String(logging.KeyError, err.Error()))
+		return subcommands.ExitUsageError
+	}
+
+	client, err := c.newClient()
+	if err != nil {
+		slog.Error("Error creating client", slog.String(logging.KeyError, err.Error()))
+		return subcommands.ExitUsageError
+	}
+
+	switch args[0] {
+	case reportsList:
+		return c.list(ctx, client)
+	case reportsGet:
+		if len(args) != 2 {
+			f.Usage()
+			return subcommands.ExitUsageError
+		}
+		return c.get(ctx, client, args[1])
+	case reportsUpload:
+		if len(args) < 2 {
+			f.Usage()
+			return subcommands.ExitUsageError
+		}
+		return c.upload(ctx, client, args[1:])
+	default:
+		f.Usage()
+		return subcommands.ExitUsageError
+	}
+}
+
+// listParams returns the parameters of the request for the reports the flags filter.
+func (c *reportsCmd) listParams() (*api.GetReportsParams, error) {
+	if c.limit < 1 {
+		return nil, fmt.Errorf("limit must be at least 1")
+	}
+
+	limit := strconv.Itoa(c.limit)
+	params := &api.GetReportsParams{
+		Limit: &limit,
+	}
+
+	if c.host != "" {
+		params.Host = &c.host
+	}
+	if c.environment != "" {
+		params.Environment = &c.environment
+	}
+	if c.state != "" {
+		state := api.Status(strings.ToLower(c.state))
+		if !state.IsValid() {
+			return nil, fmt.Errorf("state %q is not changed, failed, skipped or unchanged", c.state)
+		}
+		params.State = &state
+	}
+	if c.from != "" {
+		from, err := time.Parse(time.RFC3339, c.from)
+		if err != nil {
+			return nil, fmt.Errorf("from %q is not a RFC 3339 time", c.from)
+		}
+		params.From = &from
+	}
+	if c.to != "" {
+		to, err := time.Parse(time.RFC3339, c.to)
+		if err != nil {
+			return nil, fmt.Errorf("to %q is not a RFC 3339 time", c.to)
+		}
+		params.To = &to
+	}
+	if c.query != "" {
+		params.Q = &c.query
+	}
+	if len(c.facts) > 0 {
+		facts := []string(c.facts)
+		params.Fact = &facts
+	}
+
+	return params, nil
+}
+
+// list prints the reports the flags filter.
+func (c *reportsCmd) list(ctx context.Context, client api.ClientWithResponsesInterface) subcommands.ExitStatus {
+	params, err := c.listParams()
+	if err != nil {
+		slog.Error("Invalid filter", slog.String(logging.KeyError, err.Error()))
+		return subcommands.ExitUsageError
+	}
+
+	resp, err := client.GetReportsWithResponse(ctx, params)
+	if err != nil {
+		slog.Error("Error getting reports", slog.String(logging.KeyError, err.Error()))
+		return subcommands.ExitFailure
+	} else if resp.JSON200 == nil {
+		slog.Error("Error getting reports", slog.String(logging.KeyError, responseError(resp.HTTPResponse, resp.Body).Error()))
+		return subcommands.ExitFailure
+	}
+
+	if c.output == outputJSON {
+		err = c.printJSON(resp.JSON200)
+	} else {
+		err = c.printReports(resp.JSON200.Reports)
+	}
+	if err != nil {
+		slog.Error("Error printing reports", slog.String(logging.KeyError, err.Error()))
+		return subcommands.ExitFailure
+	}
+
+	return subcommands.ExitSuccess
+}
+
+// get prints the report with the hash and its resources.
+func (c *reportsCmd) get(ctx context.Context, client api.ClientWithResponsesInterface, hash string) subcommands.ExitStatus {
+	resp, err := client.GetReportWithResponse(ctx, hash, new(api.GetReportParams))
+	if err != nil {
+		slog.Error("Error getting report", slog.String(logging.KeyError, err.Error()))
+		return subcommands.ExitFailure
+	} else if resp.JSON200 == nil {
+		slog.Error("Error getting report", slog.String("hash", hash), slog.String(logging.KeyError, responseError(resp.HTTPResponse, resp.Body).Error()))
+		return subcommands.ExitFailure
+	}
+
+	if c.output == outputJSON {
+		err = c.printJSON(resp.JSON200)
+	} else {
+		err = c.printReportDetails(resp.JSON200)
+	}
+	if err != nil {
+		slog.Error("Error printing report", slog.String(logging.KeyError, err.Error()))
+		return subcommands.ExitFailure
+	}
+
+	return subcommands.ExitSuccess
+}
+
+// upload uploads the reports in the files and prints their summaries. A file that fails does not stop the others from
+// being uploaded.
+func (c *reportsCmd) upload(ctx context.Context, client api.ClientWithResponsesInterface, files []string) subcommands.ExitStatus {
+	uploaded := make([]api.ReportSummary, 0, len(files))
+	status := subcommands.ExitSuccess
+	summary := true
+
+	for _, file := range files {
+		if ctx.Err() != nil {
+			return subcommands.ExitFailure
+		}
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			slog.Error("Error reading report", slog.String("file", file), slog.String(logging.KeyError, err.Error()))
+			status = subcommands.ExitFailure
+			continue
+		}
+
+		resp, err := client.UploadReportWithBodyWithResponse(ctx, &api.UploadReportParams{Summary: &summary}, contentTypeReport, bytes.NewReader(content))
+		if err != nil {
+			slog.Error("Error uploading report", slog.String("file", file), slog.String(logging.KeyError, err.Error()))
+			status = subcommands.ExitFailure
+			continue
+		} else if resp.StatusCode() < 200 || resp.StatusCode() > 299 {
+			slog.Error("Error uploading report", slog.String("file", file), slog.String(logging.KeyError, responseError(resp.HTTPResponse, resp.Body).Error()))
+			status = subcommands.ExitFailure
+			continue
+		}
+
+		// A server ingesting the reports asynchronously answers with the status of the queued report, the summary is
+		// only known once the report has been ingested.
+		if resp.StatusCode() == http.StatusAccepted {
+			slog.Info("Report queued", slog.String("file", file))
+			continue
+		}
+
+		if resp.JSON201 != nil && resp.JSON201.Summary != nil {
+			uploaded = append(uploaded, *resp.JSON201.Summary)
+		}
+		slog.Info("Report uploaded", slog.String("file", file))
+	}
+
+	var err error
+	if c.output == outputJSON {
+		err = c.printJSON(uploaded)
+	} else {
+		err = c.printSummaries(uploaded)
+	}
+	if err != nil {
+		slog.Error("Error printing reports", slog.String(logging.KeyError, err.Error()))
+		return subcommands.ExitFailure
+	}
+
+	return status
+}
+
+// printSummaries prints the summaries of the uploaded reports as a table.
+func (c *reportsCmd) printSummaries(summaries []api.ReportSummary) error {
+	w := tabwriter.NewWriter(c.stdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "HASH\tHOST\tSTATUS\tPREVIOUS STATUS\tSTATUS CHANGED\tCHANGED\tFAILED\tSKIPPED\tTOTAL")
+
+	for _, s := range summaries {
+		prev := ""
+		if s.PreviousStatus != nil {
+			prev = string(*s.PreviousStatus)
+		}
+
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%s\t%s\t%t\t%d\t%d\t%d\t%d\n",
+			s.Hash,
+			s.Host,
+			s.Status,
+			prev,
+			s.StatusChanged,
+			s.TotalChanged,
+			s.TotalFailed,
+			s.TotalSkipped,
+			s.TotalResources,
+		)
+	}
+
+	return w.Flush()
+}
+
+// printReports prints the reports as a table.
+func (c *reportsCmd) printReports(reports []api.Report) error {
+	w := tabwriter.NewWriter(c.stdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "HASH\tHOST\tENVIRONMENT\tSTATUS\tEXECUTED AT\tRUNTIME\tCHANGED\tFAILED\tSKIPPED\tTOTAL")
+
+	for _, r := range reports {
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%s\t%s\t%s\t%s\t%d\t%d\t%d\t%d\n",
+			r.Hash,
+			r.Host,
+			r.Environment,
+			r.Status,
+			r.ExecutedAt.UTC().Format(time.RFC3339),
+			time.Duration(r.RuntimeSeconds)*time.Second,
+			r.TotalChanged,
+			r.TotalFailed,
+			r.TotalSkipped,
+			r.TotalResources,
+		)
+	}
+
+	return w.Flush()
+}
+
+// printReportDetails prints the summary of the report and a table of its resources.
+func (c *reportsCmd) printReportDetails(details *api.ReportDetails) error {
+	r := details.Report
+
+	w := tabwriter.NewWriter(c.stdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Hash:\t%s\n", r.Hash)
+	fmt.Fprintf(w, "Host:\t%s\n", r.Host)
+	fmt.Fprintf(w, "Environment:\t%s\n", r.Environment)
+	fmt.Fprintf(w, "Status:\t%s\n", r.Status)
+	fmt.Fprintf(w, "Puppet version:\t%s\n", strconv.FormatFloat(float64(r.PuppetVersion), 'f', -1, 32))
+	fmt.Fprintf(w, "Executed at:\t%s\n", r.ExecutedAt.UTC().Format(time.RFC3339))
+	fmt.Fprintf(w, "Runtime:\t%s\n", time.Duration(r.RuntimeSeconds)*time.Second)
+	fmt.Fprintf(w, "Resources:\t%d total, %d changed, %d failed, %d skipped\n", r.TotalResources, r.TotalChanged, r.TotalFailed, r.TotalSkipped)
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(details.Resources) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(c.stdout())
+	w = tabwriter.NewWriter(c.stdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STATUS\tTYPE\tNAME\tFILE\tLINE")
+	for _, res := range details.Resources {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\n", res.Status, res.Type, res.Name, res.File, res.Line)
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/subcommands"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestClientFlags returns the flags of a client of the server.
+func newTestClientFlags(t *testing.T, srv *httptest.Server) clientFlags {
+	return clientFlags{
+		configLocation: filepath.Join(t.TempDir(), "cli.json"),
+		endpoint:       srv.URL,
+		token:          "secret",
+		timeout:        time.Second,
+	}
+}
+
+func TestReports_List(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/reports", r.URL.Path)
+		require.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		require.Equal(t, "web-*", r.URL.Query().Get("host"))
+		require.Equal(t, "failed", r.URL.Query().Get("state"))
+		require.Equal(t, []string{"os=Debian", "role=web"}, r.URL.Query()["fact"])
+		require.Equal(t, "10", r.URL.Query().Get("limit"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"reports":[{"hash":"abc","host":"web-1","environment":"production","status":"failed",
+			"executed_at":"2024-01-02T15:04:05Z","runtime_seconds":90,"total_failed":2,"total_resources":10}],"total":1}`))
+	}))
+	defer srv.Close()
+
+	out := new(bytes.Buffer)
+	c := &reportsCmd{
+		clientFlags: newTestClientFlags(t, srv),
+		outputFlags: outputFlags{output: outputTable, out: out},
+		host:        "web-*",
+		state:       "FAILED",
+		facts:       factFlags{"os=Debian", "role=web"},
+		limit:       10,
+	}
+
+	client, err := c.newClient()
+	require.NoError(t, err)
+	require.Equal(t, subcommands.ExitSuccess, c.list(context.Background(), client))
+	require.Equal(t, ""+
+		"HASH  HOST   ENVIRONMENT  STATUS  EXECUTED AT           RUNTIME  CHANGED  FAILED  SKIPPED  TOTAL\n"+
+		"abc   web-1  production   failed  2024-01-02T15:04:05Z  1m30s    0        2       0        10\n",
+		out.String())
+}
+
+func TestReports_ListParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		cmd     *reportsCmd
+		wantErr string
+	}{
+		{name: "defaults", cmd: &reportsCmd{limit: 50}},
+		{name: "invalid state", cmd: &reportsCmd{limit: 50, state: "broken"}, wantErr: `state "broken" is not changed, failed, skipped or unchanged`},
+		{name: "invalid from", cmd: &reportsCmd{limit: 50, from: "yesterday"}, wantErr: `from "yesterday" is not a RFC 3339 time`},
+		{name: "invalid limit", cmd: &reportsCmd{limit: 0}, wantErr: "limit must be at least 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.cmd.listParams()
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestReports_GetError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"title":"Not Found","detail":"report not found","status":404}`))
+	}))
+	defer srv.Close()
+
+	c := &reportsCmd{
+		clientFlags: newTestClientFlags(t, srv),
+		outputFlags: outputFlags{output: outputTable, out: new(bytes.Buffer)},
+	}
+
+	client, err := c.newClient()
+	require.NoError(t, err)
+	require.Equal(t, subcommands.ExitFailure, c.get(context.Background(), client, "missing"))
+}
+
+func TestHosts_NodesQuery(t *testing.T) {
+	c := &hostsCmd{host: "^web-", state: "Failed"}
+
+	query, err := c.nodesQuery()
+	require.NoError(t, err)
+
+	var got []any
+	require.NoError(t, json.Unmarshal([]byte(query), &got))
+	require.Equal(t, []any{
+		"and",
+		[]any{"~", "certname", "^web-"},
+		[]any{"=", "latest_report_status", "failed"},
+	}, got)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/google/subcommands"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/logging"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/utils"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/version"
+)
+
+const (
+	appNameSuffix = "cli"
+)
+
+func main() {
+	// The output of the commands is on stdout, so the logs go to stderr as text for the person at the terminal.
+	if _, err := logging.CommonLoggerWithOptions(logging.NewConfig(logging.Name(utils.AppName(appNameSuffix))), os.Stderr, slog.LevelInfo, false); err != nil {
+		fmt.Println("Error setting up logging:", err)
+		os.Exit(1)
+	}
+
+	subcommands.Register(subcommands.HelpCommand(), "")
+	subcommands.Register(subcommands.FlagsCommand(), "")
+	subcommands.Register(subcommands.CommandsCommand(), "")
+
+	subcommands.Register(new(version.Cmd), "")
+	subcommands.Register(new(reportsCmd), "")
+	subcommands.Register(new(hostsCmd), "")
+	subcommands.Register(new(forwardCmd), "")
+
+	flag.Parse()
+
+	// Listen for ctrl+c and kill signals
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+		got := <-sig
+		slog.Info("Received signal, shutting down", slog.String("signal", got.String()))
+		cancel()
+	}()
+
+	os.Exit(int(subcommands.Execute(ctx)))
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/google/subcommands"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/logging"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/server"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/utils"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/version"
+)
+
+const (
+	appNameSuffix = "server"
+)
+
+func main() {
+	if err := logging.GeneralLogger(utils.AppName(appNameSuffix)); err != nil {
+		fmt.Println("Error setting up logging:", err)
+		os.Exit(1)
+	}
+
+	subcommands.Register(subcommands.HelpCommand(), "")
+	subcommands.Register(subcommands.FlagsCommand(), "")
+	subcommands.Register(subcommands.CommandsCommand(), "")
+
+	subcommands.Register(new(version.Cmd), "")
+	subcommands.Register(server.NewServeCmd(utils.AppName(appNameSuffix), true, true), "")
+	subcommands.Register(new(server.MigrateCmd), "")
+	subcommands.Register(new(server.TokenCmd), "")
+
+	flag.Parse()
+
+	// Listen for ctrl+c and kill signals
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+		got := <-sig
+		slog.Info("Received signal, shutting down", slog.String("signal", got.String()))
+		cancel()
+	}()
+
+	os.Exit(int(subcommands.Execute(ctx)))
+}
@@ -11,7 +11,9 @@ import (
 
 	"github.com/google/subcommands"
 	"github.com/jacobbrewer1/puppet-reporter/pkg/logging"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/server"
 	"github.com/jacobbrewer1/puppet-reporter/pkg/utils"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/version"
 )
 
 const (
@@ -28,8 +30,9 @@ func main() {
 	subcommands.Register(subcommands.FlagsCommand(), "")
 	subcommands.Register(subcommands.CommandsCommand(), "")
 
-	subcommands.Register(new(versionCmd), "")
-	subcommands.Register(new(serveCmd), "")
+	subcommands.Register(new(version.Cmd), "")
+	subcommands.Register(server.NewServeCmd(utils.AppName(appNameSuffix), false, true), "")
+	subcommands.Register(new(server.MigrateCmd), "")
 
 	flag.Parse()
 
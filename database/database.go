@@ -0,0 +1,21 @@
+// Package database holds the database migrations of each supported dialect.
+package database
+
+import "embed"
+
+// MySQLMigrations are the migrations of the MySQL and MariaDB schema.
+//
+//go:embed migrations/*.sql
+var MySQLMigrations embed.FS
+
+// SQLiteMigrations are the migrations of the SQLite schema. SQLite has no enum type, so the enum columns are text
+// columns with a check constraint. They compare case-insensitively, like the MySQL enums do.
+//
+//go:embed sqlite/*.sql
+var SQLiteMigrations embed.FS
+
+// PostgresMigrations are the migrations of the PostgreSQL schema. The enum columns are text columns with a check
+// constraint, so the allowed values can be changed by replacing the constraint.
+//
+//go:embed postgres/*.sql
+var PostgresMigrations embed.FS
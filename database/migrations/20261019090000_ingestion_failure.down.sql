@@ -0,0 +1,2 @@
+drop table if exists ingestion_failure_payload;
+drop table if exists ingestion_failure;
\ No newline at end of file
@@ -0,0 +1,22 @@
+create table ingestion_failure
+(
+    id           int auto_increment,
+    stage        varchar(64) not null,
+    error        text        not null,
+    payload_hash text        not null,
+    payload_size int         not null,
+    received_at  datetime    not null,
+    primary key (id)
+);
+
+create table ingestion_failure_payload
+(
+    id                   int auto_increment,
+    ingestion_failure_id int      not null,
+    payload              longblob not null,
+    primary key (id),
+    constraint ingestion_failure_payload_ingestion_failure_id_unique
+        unique (ingestion_failure_id),
+    constraint ingestion_failure_payload_ingestion_failure_id_fk
+        foreign key (ingestion_failure_id) references ingestion_failure (id)
+);
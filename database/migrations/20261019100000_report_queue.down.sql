@@ -0,0 +1 @@
+drop table if exists report_queue;
\ No newline at end of file
@@ -0,0 +1,12 @@
+create table report_queue
+(
+    id         int auto_increment,
+    hash       varchar(64)                                          not null,
+    status     enum ('pending', 'processing', 'done', 'failed')     not null,
+    payload    longblob                                             not null,
+    error      text                                                 not null,
+    created_at datetime                                             not null,
+    updated_at datetime                                             not null,
+    primary key (id),
+    index report_queue_status_index (status)
+);
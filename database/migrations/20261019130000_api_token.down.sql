@@ -0,0 +1 @@
+drop table if exists api_token;
\ No newline at end of file
@@ -0,0 +1 @@
+drop table if exists audit_event;
\ No newline at end of file
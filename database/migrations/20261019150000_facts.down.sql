@@ -0,0 +1,2 @@
+drop table if exists host_fact;
+drop table if exists fact_set;
\ No newline at end of file
@@ -0,0 +1,23 @@
+create table fact_set
+(
+    id          int auto_increment,
+    host        varchar(255) not null,
+    hash        varchar(64)  not null,
+    facts       longtext     not null,
+    changes     longtext     not null,
+    received_at datetime     not null,
+    primary key (id),
+    index fact_set_host_index (host)
+);
+
+create table host_fact
+(
+    id    int auto_increment,
+    host  varchar(255) not null,
+    name  varchar(255) not null,
+    value text         not null,
+    primary key (id),
+    constraint host_fact_host_name_unique
+        unique (host, name),
+    index host_fact_name_index (name)
+);
@@ -0,0 +1 @@
+alter table report drop column runtime_regression;
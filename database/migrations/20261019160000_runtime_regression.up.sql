@@ -0,0 +1 @@
+alter table report add column runtime_regression boolean not null default false;
@@ -0,0 +1,6 @@
+drop table report_queue;
+drop table ingestion_failure_payload;
+drop table ingestion_failure;
+drop table log_message;
+drop table resource;
+drop table report;
@@ -0,0 +1,73 @@
+create table report
+(
+    id             serial primary key,
+    hash           text           not null,
+    host           text           not null,
+    puppet_version numeric(10, 2) not null,
+    environment    text           not null,
+    state          text           not null check (state in ('changed', 'failed', 'unchanged')),
+    executed_at    timestamp      not null,
+    runtime        int            not null,
+    failed         int            not null,
+    changed        int            not null,
+    skipped        int            not null,
+    total          int            not null,
+    constraint report_hash_unique
+        unique (hash)
+);
+
+create table resource
+(
+    id        serial primary key,
+    report_id int  not null,
+    status    text not null check (status in ('skipped', 'changed', 'failed', 'unchanged')),
+    name      text not null,
+    type      text not null,
+    file      text not null,
+    line      int  not null,
+    constraint resource_report_id_fk
+        foreign key (report_id) references report (id)
+);
+
+create table log_message
+(
+    id        serial primary key,
+    report_id int  not null,
+    message   text not null,
+    constraint log_message_report_id_fk
+        foreign key (report_id) references report (id)
+);
+
+create table ingestion_failure
+(
+    id           serial primary key,
+    stage        varchar(64) not null,
+    error        text        not null,
+    payload_hash text        not null,
+    payload_size int         not null,
+    received_at  timestamp   not null
+);
+
+create table ingestion_failure_payload
+(
+    id                   serial primary key,
+    ingestion_failure_id int   not null,
+    payload              bytea not null,
+    constraint ingestion_failure_payload_ingestion_failure_id_unique
+        unique (ingestion_failure_id),
+    constraint ingestion_failure_payload_ingestion_failure_id_fk
+        foreign key (ingestion_failure_id) references ingestion_failure (id)
+);
+
+create table report_queue
+(
+    id         serial primary key,
+    hash       varchar(64) not null,
+    status     text        not null check (status in ('pending', 'processing', 'done', 'failed')),
+    payload    bytea       not null,
+    error      text        not null,
+    created_at timestamp   not null,
+    updated_at timestamp   not null
+);
+
+create index report_queue_status_index on report_queue (status);
@@ -0,0 +1,11 @@
+create table api_token
+(
+    id           serial primary key,
+    name         varchar(255) not null,
+    token_hash   varchar(64)  not null unique,
+    scopes       varchar(255) not null,
+    created_at   timestamp    not null,
+    expires_at   timestamp    null,
+    last_used_at timestamp    null,
+    revoked_at   timestamp    null
+);
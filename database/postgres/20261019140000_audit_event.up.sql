@@ -0,0 +1,16 @@
+create table audit_event
+(
+    id                serial primary key,
+    occurred_at       timestamp    not null,
+    request_id        varchar(64)  not null,
+    actor_ip          varchar(64)  not null,
+    actor_certificate varchar(255) not null,
+    actor_token       varchar(255) not null,
+    action            varchar(64)  not null,
+    target            varchar(255) not null,
+    outcome           varchar(32)  not null,
+    status_code       integer      not null,
+    detail            text         not null
+);
+
+create index audit_event_occurred_at_index on audit_event (occurred_at);
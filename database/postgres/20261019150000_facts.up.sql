@@ -0,0 +1,22 @@
+create table fact_set
+(
+    id          serial primary key,
+    host        varchar(255) not null,
+    hash        varchar(64)  not null,
+    facts       text         not null,
+    changes     text         not null,
+    received_at timestamp    not null
+);
+
+create index fact_set_host_index on fact_set (host);
+
+create table host_fact
+(
+    id    serial primary key,
+    host  varchar(255) not null,
+    name  varchar(255) not null,
+    value text         not null,
+    unique (host, name)
+);
+
+create index host_fact_name_index on host_fact (name);
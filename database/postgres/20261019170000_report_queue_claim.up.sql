@@ -0,0 +1 @@
+alter table report_queue add column claim varchar(32) not null default '';
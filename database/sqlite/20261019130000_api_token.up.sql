@@ -0,0 +1,11 @@
+create table api_token
+(
+    id           integer primary key autoincrement,
+    name         varchar(255) not null,
+    token_hash   varchar(64)  not null unique,
+    scopes       varchar(255) not null,
+    created_at   datetime     not null,
+    expires_at   datetime     null,
+    last_used_at datetime     null,
+    revoked_at   datetime     null
+);
@@ -0,0 +1 @@
+alter table report_queue drop column claim;
@@ -12,6 +12,8 @@ import (
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/hashicorp/vault/api"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/storage"
+	"github.com/jacobbrewer1/vaulty/repositories"
 	"github.com/jmoiron/sqlx"
 	"github.com/magefile/mage/mg" // mg contains helpful utility functions, like Deps
 )
@@ -157,28 +159,24 @@ func (l LocalDev) vaultInit() error {
 }
 
 func (l LocalDev) setupLocalDatabase() error {
-	// Current working directory
-	cwd, err := os.Getwd()
+	db, err := sqlx.Open("mysql", "root:Password123@tcp(localhost:3306)/puppetreporter?parseTime=true")
 	if err != nil {
-		return fmt.Errorf("failed to get current working directory: %w", err)
+		return fmt.Errorf("failed to open database connection: %w", err)
 	}
+	defer db.Close()
 
-	defer func() {
-		if err := os.Chdir(cwd); err != nil {
-			fmt.Println("failed to change directory: %w", err)
-		}
-	}()
-
-	os.Setenv("DATABASE_URL", "root:Password123@tcp(localhost:3306)/puppetreporter")
+	// Run the database migrations built into the application
+	m, err := storage.NewMigrator(repositories.NewDatabase(db))
+	if err != nil {
+		return fmt.Errorf("failed to create migrator: %w", err)
+	}
 
-	// Set the working directory to the database/migrations directory
-	if err := os.Chdir("./database/migrations"); err != nil {
-		return fmt.Errorf("failed to change directory: %w", err)
+	n, err := m.Up()
+	if err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
 	}
 
-	// Run the database migrations
-	cmd := exec.Command("goschema", "migrate", "-up")
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	fmt.Println("Applied", n, "migrations")
+
+	return nil
 }
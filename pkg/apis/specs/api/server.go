@@ -11,6 +11,7 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"strconv"
 
 	"github.com/gorilla/mux"
 	"github.com/jacobbrewer1/puppet-reporter/pkg/logging"
@@ -22,27 +23,105 @@ import (
 
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// Get the audit log of the state-changing requests
+	// GetAuditEvents (GET /audit)
+	GetAuditEvents(l *slog.Logger, r *http.Request, params GetAuditEventsParams) (*AuditEventResponse, error)
+
+	// Get the latest facts of a host
+	// GetFacts (GET /facts/{host})
+	GetFacts(l *slog.Logger, r *http.Request, host string) (*HostFacts, error)
+
+	// Upload the facts of a host, in the YAML or JSON of puppet facts or the facts terminus
+	// UploadFacts (POST /facts/{host})
+	UploadFacts(l *slog.Logger, r *http.Request, host string, body0 *UploadFactsRequestBody) (*HostFacts, error)
+
+	// Get the changes to the facts of a host
+	// GetFactHistory (GET /facts/{host}/history)
+	GetFactHistory(l *slog.Logger, r *http.Request, host string, params GetFactHistoryParams) (*FactHistoryResponse, error)
+
+	// Get the reports that failed to be ingested
+	// GetIngestionFailures (GET /ingestion/failures)
+	GetIngestionFailures(l *slog.Logger, r *http.Request, params GetIngestionFailuresParams) (*IngestionFailureResponse, error)
+
+	// Get a report that failed to be ingested by ID
+	// GetIngestionFailure (GET /ingestion/failures/{id})
+	GetIngestionFailure(l *slog.Logger, r *http.Request, id int64) (*IngestionFailure, error)
+
+	// Download the payload of a report that failed to be ingested
+	// GetIngestionFailurePayload (GET /ingestion/failures/{id}/payload)
+	GetIngestionFailurePayload(l *slog.Logger, r *http.Request, id int64) ([]byte, error)
+
+	// Query the events, the resources of the reports that did not stay unchanged
+	// QueryPDBEvents (GET /pdb/query/v4/events)
+	QueryPDBEvents(l *slog.Logger, r *http.Request, params QueryPDBEventsParams) (*PdbResults, error)
+
+	// Query the nodes, the hosts with their latest report
+	// QueryPDBNodes (GET /pdb/query/v4/nodes)
+	QueryPDBNodes(l *slog.Logger, r *http.Request, params QueryPDBNodesParams) (*PdbResults, error)
+
+	// Query the reports
+	// QueryPDBReports (GET /pdb/query/v4/reports)
+	QueryPDBReports(l *slog.Logger, r *http.Request, params QueryPDBReportsParams) (*PdbResults, error)
+
+	// Query the resources of the latest report of each host
+	// QueryPDBResources (GET /pdb/query/v4/resources)
+	QueryPDBResources(l *slog.Logger, r *http.Request, params QueryPDBResourcesParams) (*PdbResults, error)
+
 	// Get all reports
 	// GetReports (GET /reports)
 	GetReports(l *slog.Logger, r *http.Request, params GetReportsParams) (*ReportResponse, error)
 
+	// StreamGetReports streams the response of GetReports in a format other than JSON, or returns a nil Stream for JSON.
+	StreamGetReports(l *slog.Logger, r *http.Request, params GetReportsParams) (Stream, error)
+
 	// Upload a report
 	// UploadReport (POST /reports)
-	UploadReport(l *slog.Logger, r *http.Request, body0 *UploadReportRequestBody) (*ReportDetails, error)
+	UploadReport(l *slog.Logger, r *http.Request, params UploadReportParams, body0 *UploadReportRequestBody) (*ReportUpload, error)
+
+	// Queue a report to be ingested asynchronously
+	// QueueReport (POST /reports/queue)
+	QueueReport(l *slog.Logger, r *http.Request, body0 *QueueReportRequestBody) (*ReportQueueStatus, error)
+
+	// Get the status of a queued report
+	// GetQueuedReport (GET /reports/queue/{id})
+	GetQueuedReport(l *slog.Logger, r *http.Request, id int64) (*ReportQueueStatus, error)
+
+	// Stream the reports as they are ingested
+	// GetReportStream (GET /reports/stream)
+	GetReportStream(l *slog.Logger, r *http.Request, params GetReportStreamParams) ([]byte, error)
+
+	// StreamGetReportStream streams the response of GetReportStream in a format other than JSON, or returns a nil Stream for JSON.
+	StreamGetReportStream(l *slog.Logger, r *http.Request, params GetReportStreamParams) (Stream, error)
 
 	// Get a report by hash
 	// GetReport (GET /reports/{hash})
-	GetReport(l *slog.Logger, r *http.Request, hash string) (*ReportDetails, error)
+	GetReport(l *slog.Logger, r *http.Request, hash string, params GetReportParams) (*ReportDetails, error)
+
+	// StreamGetReport streams the response of GetReport in a format other than JSON, or returns a nil Stream for JSON.
+	StreamGetReport(l *slog.Logger, r *http.Request, hash string, params GetReportParams) (Stream, error)
+
+	// Get the hosts and resources that flap
+	// GetFlappingStats (GET /stats/flapping)
+	GetFlappingStats(l *slog.Logger, r *http.Request, params GetFlappingStatsParams) (*FlappingStats, error)
 }
 
 const (
 	loggingKeyError = "error"
 )
 
-type RateLimiterFunc = func(http.ResponseWriter, context.Context) error
+type RateLimiterFunc = func(http.ResponseWriter, *http.Request) error
 type MetricsMiddlewareFunc = http.HandlerFunc
 type ErrorHandlerFunc = func(http.ResponseWriter, context.Context, error)
 
+// Stream is a response of an operation with x-stream that writes itself as it is read, so it is never held in memory.
+type Stream interface {
+	// ContentType returns the content type of the response.
+	ContentType() string
+
+	// Encode writes the response to w.
+	Encode(w io.Writer) error
+}
+
 // ServerInterfaceWrapper converts contexts to parameters.
 type ServerInterfaceWrapper struct {
 	authz             ServerInterface
@@ -91,8 +170,8 @@ func WithInternalAPI(isInternalAPI bool) ServerOption {
 // ServerOption represents an optional feature applied to the server.
 type ServerOption func(s *ServerInterfaceWrapper)
 
-// GetReports operation middleware
-func (siw *ServerInterfaceWrapper) GetReports(w http.ResponseWriter, r *http.Request) {
+// GetAuditEvents operation middleware
+func (siw *ServerInterfaceWrapper) GetAuditEvents(w http.ResponseWriter, r *http.Request) {
 	l := logging.LoggerFromRequest(r)
 
 	ctx := r.Context()
@@ -109,7 +188,7 @@ func (siw *ServerInterfaceWrapper) GetReports(w http.ResponseWriter, r *http.Req
 	}()
 
 	// Parameter object where we will unmarshal all parameters from the context
-	var params GetReportsParams
+	var params GetAuditEventsParams
 
 	// ------------- Optional query parameter "limit" -------------
 	if err := runtime.BindQueryParameter(
@@ -176,42 +255,55 @@ func (siw *ServerInterfaceWrapper) GetReports(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	// ------------- Optional query parameter "host" -------------
+	// ------------- Optional query parameter "action" -------------
 	if err := runtime.BindQueryParameter(
 		"form",
 		true,
 		false,
-		"host",
+		"action",
 		r.URL.Query(),
-		&params.Host,
+		&params.Action,
 	); err != nil {
-		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "host", Err: err})
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "action", Err: err})
 		return
 	}
 
-	// ------------- Optional query parameter "environment" -------------
+	// ------------- Optional query parameter "outcome" -------------
 	if err := runtime.BindQueryParameter(
 		"form",
 		true,
 		false,
-		"environment",
+		"outcome",
 		r.URL.Query(),
-		&params.Environment,
+		&params.Outcome,
 	); err != nil {
-		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "environment", Err: err})
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "outcome", Err: err})
 		return
 	}
 
-	// ------------- Optional query parameter "state" -------------
+	// ------------- Optional query parameter "actor" -------------
 	if err := runtime.BindQueryParameter(
 		"form",
 		true,
 		false,
-		"state",
+		"actor",
 		r.URL.Query(),
-		&params.State,
+		&params.Actor,
 	); err != nil {
-		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "state", Err: err})
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "actor", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "target" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"target",
+		r.URL.Query(),
+		&params.Target,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "target", Err: err})
 		return
 	}
 
@@ -247,7 +339,7 @@ func (siw *ServerInterfaceWrapper) GetReports(w http.ResponseWriter, r *http.Req
 	}
 
 	// Invoke the callback with all the unmarshalled arguments
-	resp, err := h.GetReports(l, r, params)
+	resp, err := h.GetAuditEvents(l, r, params)
 	if err != nil {
 		siw.errorHandlerFunc(cw, ctx, err)
 		return
@@ -262,8 +354,8 @@ func (siw *ServerInterfaceWrapper) GetReports(w http.ResponseWriter, r *http.Req
 	}
 }
 
-// UploadReport operation middleware
-func (siw *ServerInterfaceWrapper) UploadReport(w http.ResponseWriter, r *http.Request) {
+// GetFacts operation middleware
+func (siw *ServerInterfaceWrapper) GetFacts(w http.ResponseWriter, r *http.Request) {
 	l := logging.LoggerFromRequest(r)
 
 	ctx := r.Context()
@@ -279,12 +371,16 @@ func (siw *ServerInterfaceWrapper) UploadReport(w http.ResponseWriter, r *http.R
 		}
 	}()
 
-	body := &UploadReportRequestBody{
-		File: new(openapi_types.File),
-	}
-
-	if err := siw.parseRequestBody(r, body.File); err != nil {
-		siw.errorHandlerFunc(cw, ctx, err)
+	// ------------- Path parameter "host" -------------
+	var host string
+	if err := runtime.BindStyledParameterWithOptions(
+		"simple",
+		"host",
+		mux.Vars(r)["host"],
+		&host,
+		runtime.BindStyledParameterOptions{Explode: false, Required: true},
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "host", Err: err})
 		return
 	}
 
@@ -294,14 +390,14 @@ func (siw *ServerInterfaceWrapper) UploadReport(w http.ResponseWriter, r *http.R
 	}
 
 	// Invoke the callback with all the unmarshalled arguments
-	resp, err := h.UploadReport(l, r, body)
+	resp, err := h.GetFacts(l, r, host)
 	if err != nil {
 		siw.errorHandlerFunc(cw, ctx, err)
 		return
 	}
 
 	w.Header().Set(uhttp.HeaderContentType, "application/json; charset=utf-8")
-	w.WriteHeader(201)
+	w.WriteHeader(200)
 	err = json.NewEncoder(w).Encode(resp)
 	if err != nil {
 		siw.errorHandlerFunc(cw, ctx, err)
@@ -309,8 +405,8 @@ func (siw *ServerInterfaceWrapper) UploadReport(w http.ResponseWriter, r *http.R
 	}
 }
 
-// GetReport operation middleware
-func (siw *ServerInterfaceWrapper) GetReport(w http.ResponseWriter, r *http.Request) {
+// UploadFacts operation middleware
+func (siw *ServerInterfaceWrapper) UploadFacts(w http.ResponseWriter, r *http.Request) {
 	l := logging.LoggerFromRequest(r)
 
 	ctx := r.Context()
@@ -326,16 +422,32 @@ func (siw *ServerInterfaceWrapper) GetReport(w http.ResponseWriter, r *http.Requ
 		}
 	}()
 
-	// ------------- Path parameter "hash" -------------
-	var hash string
+	if siw.rateLimiter != nil {
+		if err := siw.rateLimiter(cw, r); err != nil {
+			siw.errorHandlerFunc(cw, ctx, err)
+			return
+		}
+	}
+
+	// ------------- Path parameter "host" -------------
+	var host string
 	if err := runtime.BindStyledParameterWithOptions(
 		"simple",
-		"hash",
-		mux.Vars(r)["hash"],
-		&hash,
+		"host",
+		mux.Vars(r)["host"],
+		&host,
 		runtime.BindStyledParameterOptions{Explode: false, Required: true},
 	); err != nil {
-		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "hash", Err: err})
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "host", Err: err})
+		return
+	}
+
+	body := &UploadFactsRequestBody{
+		File: new(openapi_types.File),
+	}
+
+	if err := siw.parseRequestBody(r, body.File); err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
 		return
 	}
 
@@ -345,7 +457,7 @@ func (siw *ServerInterfaceWrapper) GetReport(w http.ResponseWriter, r *http.Requ
 	}
 
 	// Invoke the callback with all the unmarshalled arguments
-	resp, err := h.GetReport(l, r, hash)
+	resp, err := h.UploadFacts(l, r, host, body)
 	if err != nil {
 		siw.errorHandlerFunc(cw, ctx, err)
 		return
@@ -360,101 +472,1538 @@ func (siw *ServerInterfaceWrapper) GetReport(w http.ResponseWriter, r *http.Requ
 	}
 }
 
-// parseRequestBody parses the request body into the expected type.
-func (siw *ServerInterfaceWrapper) parseRequestBody(r *http.Request, dest any) error {
-	if r.Body == http.NoBody {
-		return &UnmarshalingBodyError{Err: errors.New("empty body")}
-	}
+// GetFactHistory operation middleware
+func (siw *ServerInterfaceWrapper) GetFactHistory(w http.ResponseWriter, r *http.Request) {
+	l := logging.LoggerFromRequest(r)
 
-	contentType := r.Header.Get(uhttp.HeaderContentType)
-	switch contentType {
-	case "application/json":
-		decoder := json.NewDecoder(r.Body)
-		if !siw.isInternalAPI {
-			decoder.DisallowUnknownFields()
-		}
-		if err := decoder.Decode(dest); err != nil {
-			return &UnmarshalingBodyError{Err: err}
-		}
-	case "application/x-www-form-urlencoded":
-		bdy, err := io.ReadAll(r.Body)
-		if err != nil {
-			return &UnmarshalingBodyError{Err: err}
-		}
+	ctx := r.Context()
+	cw := uhttp.NewResponseWriter(w,
+		uhttp.WithDefaultStatusCode(http.StatusOK),
+		uhttp.WithDefaultHeader(uhttp.HeaderRequestID, uhttp.RequestIDFromContext(ctx)),
+		uhttp.WithDefaultHeader(uhttp.HeaderContentType, uhttp.ContentTypeJSON),
+	)
 
-		body, ok := dest.(*openapi_types.File)
-		if !ok {
-			return &UnmarshalingBodyError{Err: fmt.Errorf("expected *openapi_types.FormData, got %T", dest)}
+	defer func() {
+		if siw.metricsMiddleware != nil {
+			siw.metricsMiddleware(cw, r)
 		}
+	}()
 
-		body.InitFromBytes(bdy, "file")
-	default:
-		return &UnsupportedContentTypeError{ContentType: contentType}
+	// ------------- Path parameter "host" -------------
+	var host string
+	if err := runtime.BindStyledParameterWithOptions(
+		"simple",
+		"host",
+		mux.Vars(r)["host"],
+		&host,
+		runtime.BindStyledParameterOptions{Explode: false, Required: true},
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "host", Err: err})
+		return
 	}
 
-	return nil
-}
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetFactHistoryParams
 
-// handleError handles returning a correctly-formatted error to the API caller.
-func handleError(w http.ResponseWriter, ctx context.Context, err error) {
-	l := logging.LoggerFromContext(ctx)
-	l.Error("Error handling request", slog.String(loggingKeyError, err.Error()))
+	// ------------- Optional query parameter "limit" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"limit",
+		r.URL.Query(),
+		&params.Limit,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
 
-	e := new(uhttp.HTTPError)
-	if errors.As(err, &e) {
-		e.RequestId = uhttp.RequestIDFromContext(ctx)
-		_ = encodeErrorResponse(w, e)
+	// ------------- Optional query parameter "last_val" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"last_val",
+		r.URL.Query(),
+		&params.LastVal,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "last_val", Err: err})
 		return
 	}
 
-	code := http.StatusInternalServerError
-	if e, ok := err.(uhttp.StatusCoder); ok {
-		code = e.StatusCode()
+	// ------------- Optional query parameter "last_id" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"last_id",
+		r.URL.Query(),
+		&params.LastId,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "last_id", Err: err})
+		return
 	}
 
-	_ = encodeErrorResponse(w, &uhttp.HTTPError{
-		ErrorMessage: common.ErrorMessage{
-			Title:     http.StatusText(code),
-			Detail:    "error handling request",
-			Status:    code,
-			RequestId: uhttp.RequestIDFromContext(ctx),
-			Details: []interface{}{
-				err.Error(),
-			},
-		},
-	})
-}
+	// ------------- Optional query parameter "sort_by" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"sort_by",
+		r.URL.Query(),
+		&params.SortBy,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "sort_by", Err: err})
+		return
+	}
 
-// encodeErrorResponse encodes input response as an RFC-7807-compliant response to w.
-func encodeErrorResponse(w http.ResponseWriter, response any) error {
-	w.Header().Set(uhttp.HeaderContentType, "application/problem+json; charset=utf-8")
+	// ------------- Optional query parameter "sort_dir" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"sort_dir",
+		r.URL.Query(),
+		&params.SortDir,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "sort_dir", Err: err})
+		return
+	}
 
-	if e, ok := response.(uhttp.StatusCoder); ok {
-		w.WriteHeader(e.StatusCode())
+	h := siw.handler
+	if siw.authz != nil {
+		h = siw.authz
 	}
 
-	return json.NewEncoder(w).Encode(response)
-}
+	// Invoke the callback with all the unmarshalled arguments
+	resp, err := h.GetFactHistory(l, r, host, params)
+	if err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
 
-type UnescapedCookieParamError struct {
-	ParamName string
-	Err       error
+	w.Header().Set(uhttp.HeaderContentType, "application/json; charset=utf-8")
+	w.WriteHeader(200)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
 }
 
-func (e *UnescapedCookieParamError) StatusCode() int {
-	return http.StatusBadRequest
-}
+// GetIngestionFailures operation middleware
+func (siw *ServerInterfaceWrapper) GetIngestionFailures(w http.ResponseWriter, r *http.Request) {
+	l := logging.LoggerFromRequest(r)
 
-func (e *UnescapedCookieParamError) Error() string {
-	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
-}
+	ctx := r.Context()
+	cw := uhttp.NewResponseWriter(w,
+		uhttp.WithDefaultStatusCode(http.StatusOK),
+		uhttp.WithDefaultHeader(uhttp.HeaderRequestID, uhttp.RequestIDFromContext(ctx)),
+		uhttp.WithDefaultHeader(uhttp.HeaderContentType, uhttp.ContentTypeJSON),
+	)
 
-func (e *UnescapedCookieParamError) Unwrap() error {
-	return e.Err
-}
+	defer func() {
+		if siw.metricsMiddleware != nil {
+			siw.metricsMiddleware(cw, r)
+		}
+	}()
 
-type UnsupportedContentTypeError struct {
-	ContentType string
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetIngestionFailuresParams
+
+	// ------------- Optional query parameter "limit" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"limit",
+		r.URL.Query(),
+		&params.Limit,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "last_val" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"last_val",
+		r.URL.Query(),
+		&params.LastVal,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "last_val", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "last_id" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"last_id",
+		r.URL.Query(),
+		&params.LastId,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "last_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "sort_by" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"sort_by",
+		r.URL.Query(),
+		&params.SortBy,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "sort_by", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "sort_dir" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"sort_dir",
+		r.URL.Query(),
+		&params.SortDir,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "sort_dir", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "stage" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"stage",
+		r.URL.Query(),
+		&params.Stage,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "stage", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "from" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"from",
+		r.URL.Query(),
+		&params.From,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "from", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "to" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"to",
+		r.URL.Query(),
+		&params.To,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "to", Err: err})
+		return
+	}
+
+	h := siw.handler
+	if siw.authz != nil {
+		h = siw.authz
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	resp, err := h.GetIngestionFailures(l, r, params)
+	if err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
+
+	w.Header().Set(uhttp.HeaderContentType, "application/json; charset=utf-8")
+	w.WriteHeader(200)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
+}
+
+// GetIngestionFailure operation middleware
+func (siw *ServerInterfaceWrapper) GetIngestionFailure(w http.ResponseWriter, r *http.Request) {
+	l := logging.LoggerFromRequest(r)
+
+	ctx := r.Context()
+	cw := uhttp.NewResponseWriter(w,
+		uhttp.WithDefaultStatusCode(http.StatusOK),
+		uhttp.WithDefaultHeader(uhttp.HeaderRequestID, uhttp.RequestIDFromContext(ctx)),
+		uhttp.WithDefaultHeader(uhttp.HeaderContentType, uhttp.ContentTypeJSON),
+	)
+
+	defer func() {
+		if siw.metricsMiddleware != nil {
+			siw.metricsMiddleware(cw, r)
+		}
+	}()
+
+	// ------------- Path parameter "id" -------------
+	var id int64
+	if err := runtime.BindStyledParameterWithOptions(
+		"simple",
+		"id",
+		mux.Vars(r)["id"],
+		&id,
+		runtime.BindStyledParameterOptions{Explode: false, Required: true},
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	h := siw.handler
+	if siw.authz != nil {
+		h = siw.authz
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	resp, err := h.GetIngestionFailure(l, r, id)
+	if err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
+
+	w.Header().Set(uhttp.HeaderContentType, "application/json; charset=utf-8")
+	w.WriteHeader(200)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
+}
+
+// GetIngestionFailurePayload operation middleware
+func (siw *ServerInterfaceWrapper) GetIngestionFailurePayload(w http.ResponseWriter, r *http.Request) {
+	l := logging.LoggerFromRequest(r)
+
+	ctx := r.Context()
+	cw := uhttp.NewResponseWriter(w,
+		uhttp.WithDefaultStatusCode(http.StatusOK),
+		uhttp.WithDefaultHeader(uhttp.HeaderRequestID, uhttp.RequestIDFromContext(ctx)),
+		uhttp.WithDefaultHeader(uhttp.HeaderContentType, uhttp.ContentTypeJSON),
+	)
+
+	defer func() {
+		if siw.metricsMiddleware != nil {
+			siw.metricsMiddleware(cw, r)
+		}
+	}()
+
+	// ------------- Path parameter "id" -------------
+	var id int64
+	if err := runtime.BindStyledParameterWithOptions(
+		"simple",
+		"id",
+		mux.Vars(r)["id"],
+		&id,
+		runtime.BindStyledParameterOptions{Explode: false, Required: true},
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	h := siw.handler
+	if siw.authz != nil {
+		h = siw.authz
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	resp, err := h.GetIngestionFailurePayload(l, r, id)
+	if err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
+
+	w.Header().Set(uhttp.HeaderContentType, "text/plain; charset=utf-8")
+	w.Header().Set("Content-Length", strconv.Itoa(len(resp)))
+	w.WriteHeader(200)
+	_, err = w.Write(resp)
+
+	if err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
+}
+
+// QueryPDBEvents operation middleware
+func (siw *ServerInterfaceWrapper) QueryPDBEvents(w http.ResponseWriter, r *http.Request) {
+	l := logging.LoggerFromRequest(r)
+
+	ctx := r.Context()
+	cw := uhttp.NewResponseWriter(w,
+		uhttp.WithDefaultStatusCode(http.StatusOK),
+		uhttp.WithDefaultHeader(uhttp.HeaderRequestID, uhttp.RequestIDFromContext(ctx)),
+		uhttp.WithDefaultHeader(uhttp.HeaderContentType, uhttp.ContentTypeJSON),
+	)
+
+	defer func() {
+		if siw.metricsMiddleware != nil {
+			siw.metricsMiddleware(cw, r)
+		}
+	}()
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params QueryPDBEventsParams
+
+	// ------------- Optional query parameter "query" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"query",
+		r.URL.Query(),
+		&params.Query,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "query", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"limit",
+		r.URL.Query(),
+		&params.Limit,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"offset",
+		r.URL.Query(),
+		&params.Offset,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "order_by" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"order_by",
+		r.URL.Query(),
+		&params.OrderBy,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "order_by", Err: err})
+		return
+	}
+
+	h := siw.handler
+	if siw.authz != nil {
+		h = siw.authz
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	resp, err := h.QueryPDBEvents(l, r, params)
+	if err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
+
+	w.Header().Set(uhttp.HeaderContentType, "application/json; charset=utf-8")
+	w.WriteHeader(200)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
+}
+
+// QueryPDBNodes operation middleware
+func (siw *ServerInterfaceWrapper) QueryPDBNodes(w http.ResponseWriter, r *http.Request) {
+	l := logging.LoggerFromRequest(r)
+
+	ctx := r.Context()
+	cw := uhttp.NewResponseWriter(w,
+		uhttp.WithDefaultStatusCode(http.StatusOK),
+		uhttp.WithDefaultHeader(uhttp.HeaderRequestID, uhttp.RequestIDFromContext(ctx)),
+		uhttp.WithDefaultHeader(uhttp.HeaderContentType, uhttp.ContentTypeJSON),
+	)
+
+	defer func() {
+		if siw.metricsMiddleware != nil {
+			siw.metricsMiddleware(cw, r)
+		}
+	}()
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params QueryPDBNodesParams
+
+	// ------------- Optional query parameter "query" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"query",
+		r.URL.Query(),
+		&params.Query,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "query", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"limit",
+		r.URL.Query(),
+		&params.Limit,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"offset",
+		r.URL.Query(),
+		&params.Offset,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "order_by" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"order_by",
+		r.URL.Query(),
+		&params.OrderBy,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "order_by", Err: err})
+		return
+	}
+
+	h := siw.handler
+	if siw.authz != nil {
+		h = siw.authz
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	resp, err := h.QueryPDBNodes(l, r, params)
+	if err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
+
+	w.Header().Set(uhttp.HeaderContentType, "application/json; charset=utf-8")
+	w.WriteHeader(200)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
+}
+
+// QueryPDBReports operation middleware
+func (siw *ServerInterfaceWrapper) QueryPDBReports(w http.ResponseWriter, r *http.Request) {
+	l := logging.LoggerFromRequest(r)
+
+	ctx := r.Context()
+	cw := uhttp.NewResponseWriter(w,
+		uhttp.WithDefaultStatusCode(http.StatusOK),
+		uhttp.WithDefaultHeader(uhttp.HeaderRequestID, uhttp.RequestIDFromContext(ctx)),
+		uhttp.WithDefaultHeader(uhttp.HeaderContentType, uhttp.ContentTypeJSON),
+	)
+
+	defer func() {
+		if siw.metricsMiddleware != nil {
+			siw.metricsMiddleware(cw, r)
+		}
+	}()
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params QueryPDBReportsParams
+
+	// ------------- Optional query parameter "query" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"query",
+		r.URL.Query(),
+		&params.Query,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "query", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"limit",
+		r.URL.Query(),
+		&params.Limit,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"offset",
+		r.URL.Query(),
+		&params.Offset,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "order_by" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"order_by",
+		r.URL.Query(),
+		&params.OrderBy,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "order_by", Err: err})
+		return
+	}
+
+	h := siw.handler
+	if siw.authz != nil {
+		h = siw.authz
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	resp, err := h.QueryPDBReports(l, r, params)
+	if err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
+
+	w.Header().Set(uhttp.HeaderContentType, "application/json; charset=utf-8")
+	w.WriteHeader(200)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
+}
+
+// QueryPDBResources operation middleware
+func (siw *ServerInterfaceWrapper) QueryPDBResources(w http.ResponseWriter, r *http.Request) {
+	l := logging.LoggerFromRequest(r)
+
+	ctx := r.Context()
+	cw := uhttp.NewResponseWriter(w,
+		uhttp.WithDefaultStatusCode(http.StatusOK),
+		uhttp.WithDefaultHeader(uhttp.HeaderRequestID, uhttp.RequestIDFromContext(ctx)),
+		uhttp.WithDefaultHeader(uhttp.HeaderContentType, uhttp.ContentTypeJSON),
+	)
+
+	defer func() {
+		if siw.metricsMiddleware != nil {
+			siw.metricsMiddleware(cw, r)
+		}
+	}()
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params QueryPDBResourcesParams
+
+	// ------------- Optional query parameter "query" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"query",
+		r.URL.Query(),
+		&params.Query,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "query", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"limit",
+		r.URL.Query(),
+		&params.Limit,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"offset",
+		r.URL.Query(),
+		&params.Offset,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "order_by" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"order_by",
+		r.URL.Query(),
+		&params.OrderBy,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "order_by", Err: err})
+		return
+	}
+
+	h := siw.handler
+	if siw.authz != nil {
+		h = siw.authz
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	resp, err := h.QueryPDBResources(l, r, params)
+	if err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
+
+	w.Header().Set(uhttp.HeaderContentType, "application/json; charset=utf-8")
+	w.WriteHeader(200)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
+}
+
+// GetReports operation middleware
+func (siw *ServerInterfaceWrapper) GetReports(w http.ResponseWriter, r *http.Request) {
+	l := logging.LoggerFromRequest(r)
+
+	ctx := r.Context()
+	cw := uhttp.NewResponseWriter(w,
+		uhttp.WithDefaultStatusCode(http.StatusOK),
+		uhttp.WithDefaultHeader(uhttp.HeaderRequestID, uhttp.RequestIDFromContext(ctx)),
+		uhttp.WithDefaultHeader(uhttp.HeaderContentType, uhttp.ContentTypeJSON),
+	)
+
+	defer func() {
+		if siw.metricsMiddleware != nil {
+			siw.metricsMiddleware(cw, r)
+		}
+	}()
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetReportsParams
+
+	// ------------- Optional query parameter "limit" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"limit",
+		r.URL.Query(),
+		&params.Limit,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "last_val" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"last_val",
+		r.URL.Query(),
+		&params.LastVal,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "last_val", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "last_id" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"last_id",
+		r.URL.Query(),
+		&params.LastId,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "last_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "sort_by" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"sort_by",
+		r.URL.Query(),
+		&params.SortBy,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "sort_by", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "sort_dir" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"sort_dir",
+		r.URL.Query(),
+		&params.SortDir,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "sort_dir", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "host" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"host",
+		r.URL.Query(),
+		&params.Host,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "host", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "environment" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"environment",
+		r.URL.Query(),
+		&params.Environment,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "environment", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "state" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"state",
+		r.URL.Query(),
+		&params.State,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "state", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "from" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"from",
+		r.URL.Query(),
+		&params.From,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "from", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "to" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"to",
+		r.URL.Query(),
+		&params.To,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "to", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "fact" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"fact",
+		r.URL.Query(),
+		&params.Fact,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "fact", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "runtime_regression" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"runtime_regression",
+		r.URL.Query(),
+		&params.RuntimeRegression,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "runtime_regression", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "q" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"q",
+		r.URL.Query(),
+		&params.Q,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "q", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "format" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"format",
+		r.URL.Query(),
+		&params.Format,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	h := siw.handler
+	if siw.authz != nil {
+		h = siw.authz
+	}
+
+	stream, err := h.StreamGetReports(l, r, params)
+	if err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
+
+	if stream != nil {
+		w.Header().Set(uhttp.HeaderContentType, stream.ContentType())
+		w.WriteHeader(http.StatusOK)
+		if err := stream.Encode(w); err != nil {
+			// The status has been sent, so the error can only be logged.
+			l.Error("Error streaming response", slog.String(loggingKeyError, err.Error()))
+		}
+		return
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	resp, err := h.GetReports(l, r, params)
+	if err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
+
+	w.Header().Set(uhttp.HeaderContentType, "application/json; charset=utf-8")
+	w.WriteHeader(200)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
+}
+
+// UploadReport operation middleware
+func (siw *ServerInterfaceWrapper) UploadReport(w http.ResponseWriter, r *http.Request) {
+	l := logging.LoggerFromRequest(r)
+
+	ctx := r.Context()
+	cw := uhttp.NewResponseWriter(w,
+		uhttp.WithDefaultStatusCode(http.StatusOK),
+		uhttp.WithDefaultHeader(uhttp.HeaderRequestID, uhttp.RequestIDFromContext(ctx)),
+		uhttp.WithDefaultHeader(uhttp.HeaderContentType, uhttp.ContentTypeJSON),
+	)
+
+	defer func() {
+		if siw.metricsMiddleware != nil {
+			siw.metricsMiddleware(cw, r)
+		}
+	}()
+
+	if siw.rateLimiter != nil {
+		if err := siw.rateLimiter(cw, r); err != nil {
+			siw.errorHandlerFunc(cw, ctx, err)
+			return
+		}
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params UploadReportParams
+
+	// ------------- Optional query parameter "summary" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"summary",
+		r.URL.Query(),
+		&params.Summary,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "summary", Err: err})
+		return
+	}
+
+	body := &UploadReportRequestBody{
+		File: new(openapi_types.File),
+	}
+
+	if err := siw.parseRequestBody(r, body.File); err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
+
+	h := siw.handler
+	if siw.authz != nil {
+		h = siw.authz
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	resp, err := h.UploadReport(l, r, params, body)
+	if err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
+
+	w.Header().Set(uhttp.HeaderContentType, "application/json; charset=utf-8")
+	w.WriteHeader(201)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
+}
+
+// QueueReport operation middleware
+func (siw *ServerInterfaceWrapper) QueueReport(w http.ResponseWriter, r *http.Request) {
+	l := logging.LoggerFromRequest(r)
+
+	ctx := r.Context()
+	cw := uhttp.NewResponseWriter(w,
+		uhttp.WithDefaultStatusCode(http.StatusOK),
+		uhttp.WithDefaultHeader(uhttp.HeaderRequestID, uhttp.RequestIDFromContext(ctx)),
+		uhttp.WithDefaultHeader(uhttp.HeaderContentType, uhttp.ContentTypeJSON),
+	)
+
+	defer func() {
+		if siw.metricsMiddleware != nil {
+			siw.metricsMiddleware(cw, r)
+		}
+	}()
+
+	if siw.rateLimiter != nil {
+		if err := siw.rateLimiter(cw, r); err != nil {
+			siw.errorHandlerFunc(cw, ctx, err)
+			return
+		}
+	}
+
+	body := &QueueReportRequestBody{
+		File: new(openapi_types.File),
+	}
+
+	if err := siw.parseRequestBody(r, body.File); err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
+
+	h := siw.handler
+	if siw.authz != nil {
+		h = siw.authz
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	resp, err := h.QueueReport(l, r, body)
+	if err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
+
+	w.Header().Set(uhttp.HeaderContentType, "application/json; charset=utf-8")
+	w.WriteHeader(202)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
+}
+
+// GetQueuedReport operation middleware
+func (siw *ServerInterfaceWrapper) GetQueuedReport(w http.ResponseWriter, r *http.Request) {
+	l := logging.LoggerFromRequest(r)
+
+	ctx := r.Context()
+	cw := uhttp.NewResponseWriter(w,
+		uhttp.WithDefaultStatusCode(http.StatusOK),
+		uhttp.WithDefaultHeader(uhttp.HeaderRequestID, uhttp.RequestIDFromContext(ctx)),
+		uhttp.WithDefaultHeader(uhttp.HeaderContentType, uhttp.ContentTypeJSON),
+	)
+
+	defer func() {
+		if siw.metricsMiddleware != nil {
+			siw.metricsMiddleware(cw, r)
+		}
+	}()
+
+	// ------------- Path parameter "id" -------------
+	var id int64
+	if err := runtime.BindStyledParameterWithOptions(
+		"simple",
+		"id",
+		mux.Vars(r)["id"],
+		&id,
+		runtime.BindStyledParameterOptions{Explode: false, Required: true},
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	h := siw.handler
+	if siw.authz != nil {
+		h = siw.authz
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	resp, err := h.GetQueuedReport(l, r, id)
+	if err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
+
+	w.Header().Set(uhttp.HeaderContentType, "application/json; charset=utf-8")
+	w.WriteHeader(200)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
+}
+
+// GetReportStream operation middleware
+func (siw *ServerInterfaceWrapper) GetReportStream(w http.ResponseWriter, r *http.Request) {
+	l := logging.LoggerFromRequest(r)
+
+	ctx := r.Context()
+	cw := uhttp.NewResponseWriter(w,
+		uhttp.WithDefaultStatusCode(http.StatusOK),
+		uhttp.WithDefaultHeader(uhttp.HeaderRequestID, uhttp.RequestIDFromContext(ctx)),
+		uhttp.WithDefaultHeader(uhttp.HeaderContentType, uhttp.ContentTypeJSON),
+	)
+
+	defer func() {
+		if siw.metricsMiddleware != nil {
+			siw.metricsMiddleware(cw, r)
+		}
+	}()
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetReportStreamParams
+
+	// ------------- Optional query parameter "host" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"host",
+		r.URL.Query(),
+		&params.Host,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "host", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "environment" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"environment",
+		r.URL.Query(),
+		&params.Environment,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "environment", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "state" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"state",
+		r.URL.Query(),
+		&params.State,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "state", Err: err})
+		return
+	}
+
+	h := siw.handler
+	if siw.authz != nil {
+		h = siw.authz
+	}
+
+	stream, err := h.StreamGetReportStream(l, r, params)
+	if err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
+
+	if stream != nil {
+		w.Header().Set(uhttp.HeaderContentType, stream.ContentType())
+		w.WriteHeader(http.StatusOK)
+		if err := stream.Encode(w); err != nil {
+			// The status has been sent, so the error can only be logged.
+			l.Error("Error streaming response", slog.String(loggingKeyError, err.Error()))
+		}
+		return
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	resp, err := h.GetReportStream(l, r, params)
+	if err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
+
+	w.Header().Set(uhttp.HeaderContentType, "text/event-stream; charset=utf-8")
+	w.WriteHeader(200)
+	_, err = w.Write(resp)
+
+	if err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
+}
+
+// GetReport operation middleware
+func (siw *ServerInterfaceWrapper) GetReport(w http.ResponseWriter, r *http.Request) {
+	l := logging.LoggerFromRequest(r)
+
+	ctx := r.Context()
+	cw := uhttp.NewResponseWriter(w,
+		uhttp.WithDefaultStatusCode(http.StatusOK),
+		uhttp.WithDefaultHeader(uhttp.HeaderRequestID, uhttp.RequestIDFromContext(ctx)),
+		uhttp.WithDefaultHeader(uhttp.HeaderContentType, uhttp.ContentTypeJSON),
+	)
+
+	defer func() {
+		if siw.metricsMiddleware != nil {
+			siw.metricsMiddleware(cw, r)
+		}
+	}()
+
+	// ------------- Path parameter "hash" -------------
+	var hash string
+	if err := runtime.BindStyledParameterWithOptions(
+		"simple",
+		"hash",
+		mux.Vars(r)["hash"],
+		&hash,
+		runtime.BindStyledParameterOptions{Explode: false, Required: true},
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "hash", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetReportParams
+
+	// ------------- Optional query parameter "format" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"format",
+		r.URL.Query(),
+		&params.Format,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	h := siw.handler
+	if siw.authz != nil {
+		h = siw.authz
+	}
+
+	stream, err := h.StreamGetReport(l, r, hash, params)
+	if err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
+
+	if stream != nil {
+		w.Header().Set(uhttp.HeaderContentType, stream.ContentType())
+		w.WriteHeader(http.StatusOK)
+		if err := stream.Encode(w); err != nil {
+			// The status has been sent, so the error can only be logged.
+			l.Error("Error streaming response", slog.String(loggingKeyError, err.Error()))
+		}
+		return
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	resp, err := h.GetReport(l, r, hash, params)
+	if err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
+
+	w.Header().Set(uhttp.HeaderContentType, "application/json; charset=utf-8")
+	w.WriteHeader(200)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
+}
+
+// GetFlappingStats operation middleware
+func (siw *ServerInterfaceWrapper) GetFlappingStats(w http.ResponseWriter, r *http.Request) {
+	l := logging.LoggerFromRequest(r)
+
+	ctx := r.Context()
+	cw := uhttp.NewResponseWriter(w,
+		uhttp.WithDefaultStatusCode(http.StatusOK),
+		uhttp.WithDefaultHeader(uhttp.HeaderRequestID, uhttp.RequestIDFromContext(ctx)),
+		uhttp.WithDefaultHeader(uhttp.HeaderContentType, uhttp.ContentTypeJSON),
+	)
+
+	defer func() {
+		if siw.metricsMiddleware != nil {
+			siw.metricsMiddleware(cw, r)
+		}
+	}()
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetFlappingStatsParams
+
+	// ------------- Optional query parameter "window" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"window",
+		r.URL.Query(),
+		&params.Window,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "window", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "threshold" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"threshold",
+		r.URL.Query(),
+		&params.Threshold,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "threshold", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "host" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"host",
+		r.URL.Query(),
+		&params.Host,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "host", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "environment" -------------
+	if err := runtime.BindQueryParameter(
+		"form",
+		true,
+		false,
+		"environment",
+		r.URL.Query(),
+		&params.Environment,
+	); err != nil {
+		siw.errorHandlerFunc(cw, ctx, &InvalidParamFormatError{ParamName: "environment", Err: err})
+		return
+	}
+
+	h := siw.handler
+	if siw.authz != nil {
+		h = siw.authz
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	resp, err := h.GetFlappingStats(l, r, params)
+	if err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
+
+	w.Header().Set(uhttp.HeaderContentType, "application/json; charset=utf-8")
+	w.WriteHeader(200)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		siw.errorHandlerFunc(cw, ctx, err)
+		return
+	}
+}
+
+// parseRequestBody parses the request body into the expected type.
+func (siw *ServerInterfaceWrapper) parseRequestBody(r *http.Request, dest any) error {
+	if r.Body == http.NoBody {
+		return &UnmarshalingBodyError{Err: errors.New("empty body")}
+	}
+
+	contentType := r.Header.Get(uhttp.HeaderContentType)
+	switch contentType {
+	case "application/json":
+		decoder := json.NewDecoder(r.Body)
+		if !siw.isInternalAPI {
+			decoder.DisallowUnknownFields()
+		}
+		if err := decoder.Decode(dest); err != nil {
+			return bodyError(err)
+		}
+	case "application/x-www-form-urlencoded":
+		bdy, err := io.ReadAll(r.Body)
+		if err != nil {
+			return bodyError(err)
+		}
+
+		body, ok := dest.(*openapi_types.File)
+		if !ok {
+			return &UnmarshalingBodyError{Err: fmt.Errorf("expected *openapi_types.FormData, got %T", dest)}
+		}
+
+		body.InitFromBytes(bdy, "file")
+	default:
+		return &UnsupportedContentTypeError{ContentType: contentType}
+	}
+
+	return nil
+}
+
+// bodyError returns the error of reading the request body, a RequestBodyTooLargeError if the body is over the
+// limit set with http.MaxBytesReader.
+func bodyError(err error) error {
+	maxBytesErr := new(http.MaxBytesError)
+	if errors.As(err, &maxBytesErr) {
+		return &RequestBodyTooLargeError{Limit: maxBytesErr.Limit}
+	}
+	return &UnmarshalingBodyError{Err: err}
+}
+
+// handleError handles returning a correctly-formatted error to the API caller.
+func handleError(w http.ResponseWriter, ctx context.Context, err error) {
+	l := logging.LoggerFromContext(ctx)
+	l.Error("Error handling request", slog.String(loggingKeyError, err.Error()))
+
+	e := new(uhttp.HTTPError)
+	if errors.As(err, &e) {
+		e.RequestId = uhttp.RequestIDFromContext(ctx)
+		_ = encodeErrorResponse(w, e)
+		return
+	}
+
+	code := http.StatusInternalServerError
+	if e, ok := err.(uhttp.StatusCoder); ok {
+		code = e.StatusCode()
+	}
+
+	_ = encodeErrorResponse(w, &uhttp.HTTPError{
+		ErrorMessage: common.ErrorMessage{
+			Title:     http.StatusText(code),
+			Detail:    "error handling request",
+			Status:    code,
+			RequestId: uhttp.RequestIDFromContext(ctx),
+			Details: []interface{}{
+				err.Error(),
+			},
+		},
+	})
+}
+
+// encodeErrorResponse encodes input response as an RFC-7807-compliant response to w.
+func encodeErrorResponse(w http.ResponseWriter, response any) error {
+	w.Header().Set(uhttp.HeaderContentType, "application/problem+json; charset=utf-8")
+
+	if e, ok := response.(uhttp.StatusCoder); ok {
+		w.WriteHeader(e.StatusCode())
+	}
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnsupportedContentTypeError struct {
+	ContentType string
 }
 
 func (e *UnsupportedContentTypeError) StatusCode() int {
@@ -477,6 +2026,18 @@ func (e *UnmarshalingBodyError) Error() string {
 	return fmt.Sprintf("Error unmarshaling request body: %s", e.Err.Error())
 }
 
+type RequestBodyTooLargeError struct {
+	Limit int64
+}
+
+func (e *RequestBodyTooLargeError) StatusCode() int {
+	return http.StatusRequestEntityTooLarge
+}
+
+func (e *RequestBodyTooLargeError) Error() string {
+	return fmt.Sprintf("Request body is larger than %d bytes", e.Limit)
+}
+
 type UnmarshalingParamError struct {
 	ParamName string
 	Err       error
@@ -566,8 +2127,8 @@ func wrapHandler(handler http.HandlerFunc, middlewares ...mux.MiddlewareFunc) ht
 	return wrappedHandler
 }
 
-// RegisterUnauthedHandlers registers any api handlers which do not have any authentication on them. Most services will not have any.
-func RegisterUnauthedHandlers(router *mux.Router, si ServerInterface, opts ...ServerOption) {
+// RegisterHandlers registers the api handlers.
+func RegisterHandlers(router *mux.Router, si ServerInterface, opts ...ServerOption) {
 	wrapper := ServerInterfaceWrapper{
 		authz:             nil,
 		handler:           si,
@@ -586,7 +2147,22 @@ func RegisterUnauthedHandlers(router *mux.Router, si ServerInterface, opts ...Se
 	router.Use(uhttp.AuthHeaderToContextMux())
 	router.Use(uhttp.GenerateOrCopyRequestIDMux())
 
+	router.Methods(http.MethodGet).Path("/audit").Handler(wrapHandler(wrapper.GetAuditEvents))
+	router.Methods(http.MethodGet).Path("/facts/{host}").Handler(wrapHandler(wrapper.GetFacts))
+	router.Methods(http.MethodPost).Path("/facts/{host}").Handler(wrapHandler(wrapper.UploadFacts))
+	router.Methods(http.MethodGet).Path("/facts/{host}/history").Handler(wrapHandler(wrapper.GetFactHistory))
+	router.Methods(http.MethodGet).Path("/ingestion/failures").Handler(wrapHandler(wrapper.GetIngestionFailures))
+	router.Methods(http.MethodGet).Path("/ingestion/failures/{id}").Handler(wrapHandler(wrapper.GetIngestionFailure))
+	router.Methods(http.MethodGet).Path("/ingestion/failures/{id}/payload").Handler(wrapHandler(wrapper.GetIngestionFailurePayload))
+	router.Methods(http.MethodGet).Path("/pdb/query/v4/events").Handler(wrapHandler(wrapper.QueryPDBEvents))
+	router.Methods(http.MethodGet).Path("/pdb/query/v4/nodes").Handler(wrapHandler(wrapper.QueryPDBNodes))
+	router.Methods(http.MethodGet).Path("/pdb/query/v4/reports").Handler(wrapHandler(wrapper.QueryPDBReports))
+	router.Methods(http.MethodGet).Path("/pdb/query/v4/resources").Handler(wrapHandler(wrapper.QueryPDBResources))
 	router.Methods(http.MethodGet).Path("/reports").Handler(wrapHandler(wrapper.GetReports))
 	router.Methods(http.MethodPost).Path("/reports").Handler(wrapHandler(wrapper.UploadReport))
+	router.Methods(http.MethodPost).Path("/reports/queue").Handler(wrapHandler(wrapper.QueueReport))
+	router.Methods(http.MethodGet).Path("/reports/queue/{id}").Handler(wrapHandler(wrapper.GetQueuedReport))
+	router.Methods(http.MethodGet).Path("/reports/stream").Handler(wrapHandler(wrapper.GetReportStream))
 	router.Methods(http.MethodGet).Path("/reports/{hash}").Handler(wrapHandler(wrapper.GetReport))
+	router.Methods(http.MethodGet).Path("/stats/flapping").Handler(wrapHandler(wrapper.GetFlappingStats))
 }
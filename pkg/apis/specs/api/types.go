@@ -14,25 +14,258 @@ import (
 	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
+const (
+	BearerAuthScopes = "bearerAuth.Scopes"
+)
+
+// AuditActor defines the model for audit_actor.
+type AuditActor = struct {
+	Certificate *string `json:"certificate,omitempty"`
+	Ip          string  `json:"ip"`
+	Token       *string `json:"token,omitempty"`
+}
+
+// AuditEvent defines the model for audit_event.
+type AuditEvent = struct {
+	Action     string       `json:"action"`
+	Actor      AuditActor   `json:"actor"`
+	Detail     *string      `json:"detail,omitempty"`
+	Id         int64        `json:"id"`
+	OccurredAt time.Time    `json:"occurred_at"`
+	Outcome    AuditOutcome `json:"outcome"`
+	RequestId  string       `json:"request_id"`
+	StatusCode int          `json:"status_code"`
+	Target     string       `json:"target"`
+}
+
+// AuditEventResponse defines the model for audit_event_response.
+type AuditEventResponse = struct {
+	Events []AuditEvent `json:"events"`
+	Total  int64        `json:"total"`
+}
+
+// AuditOutcome defines the model for audit_outcome.
+type AuditOutcome string
+
+// List of AuditOutcome
+const (
+	AuditOutcomeconflict AuditOutcome = "conflict"
+	AuditOutcomedenied   AuditOutcome = "denied"
+	AuditOutcomeerror    AuditOutcome = "error"
+	AuditOutcomeinvalid  AuditOutcome = "invalid"
+	AuditOutcomesuccess  AuditOutcome = "success"
+)
+
+func (e *AuditOutcome) IsValid() bool {
+	if e == nil {
+		return false
+	}
+
+	switch *e {
+	case AuditOutcomeconflict:
+		return true
+	case AuditOutcomedenied:
+		return true
+	case AuditOutcomeerror:
+		return true
+	case AuditOutcomeinvalid:
+		return true
+	case AuditOutcomesuccess:
+		return true
+	default:
+		return false
+	}
+}
+
+func (e *AuditOutcome) MarshalJSON() ([]byte, error) {
+	if !e.IsValid() {
+		return nil, uhttp.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("%s is not a valid AuditOutcome", *e))
+	}
+
+	return json.Marshal(string(*e))
+}
+
+func (e *AuditOutcome) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	e2 := AuditOutcome(s)
+	if !e2.IsValid() {
+		return uhttp.NewHTTPError(http.StatusBadRequest, fmt.Errorf("%s is not a valid AuditOutcome", s))
+	}
+
+	*e = e2
+	return nil
+}
+
+// FactChange defines the model for fact_change.
+type FactChange = struct {
+	Name     string  `json:"name"`
+	NewValue *string `json:"new_value,omitempty"`
+	OldValue *string `json:"old_value,omitempty"`
+}
+
+// FactHistoryResponse defines the model for fact_history_response.
+type FactHistoryResponse = struct {
+	History []FactSet `json:"history"`
+	Total   int64     `json:"total"`
+}
+
+// FactSet defines the model for fact_set.
+type FactSet = struct {
+	Changes    []FactChange `json:"changes"`
+	Hash       string       `json:"hash"`
+	Id         int64        `json:"id"`
+	ReceivedAt time.Time    `json:"received_at"`
+}
+
+// FlappingHost defines the model for flapping_host.
+type FlappingHost = struct {
+	Environment string `json:"environment"`
+	Host        string `json:"host"`
+
+	// Ratio The share of the runs that changed state
+	Ratio float64 `json:"ratio"`
+
+	// Runs The number of reports of the host that were looked at
+	Runs int `json:"runs"`
+
+	// States The states of the runs, the latest first
+	States []ReportStatus `json:"states"`
+
+	// Transitions The number of runs that ended in a different state to the run before
+	Transitions int `json:"transitions"`
+}
+
+// FlappingResource defines the model for flapping_resource.
+type FlappingResource = struct {
+	// Changes The number of the runs that changed the resource
+	Changes int    `json:"changes"`
+	Host    string `json:"host"`
+	Name    string `json:"name"`
+
+	// Ratio The share of the runs that changed the resource
+	Ratio float64 `json:"ratio"`
+
+	// Runs The number of reports of the host that were looked at
+	Runs int    `json:"runs"`
+	Type string `json:"type"`
+}
+
+// FlappingStats defines the model for flapping_stats.
+type FlappingStats = struct {
+	Hosts     []FlappingHost     `json:"hosts"`
+	Resources []FlappingResource `json:"resources"`
+	Threshold float64            `json:"threshold"`
+
+	// Window The number of the latest reports of each host that were looked at
+	Window int `json:"window"`
+}
+
+// HostFacts defines the model for host_facts.
+type HostFacts = struct {
+	Facts      map[string]interface{} `json:"facts"`
+	Hash       string                 `json:"hash"`
+	Host       string                 `json:"host"`
+	ReceivedAt time.Time              `json:"received_at"`
+}
+
+// IngestionFailure defines the model for ingestion_failure.
+type IngestionFailure = struct {
+	Error       string    `json:"error"`
+	Id          int64     `json:"id"`
+	PayloadHash string    `json:"payload_hash"`
+	PayloadSize int64     `json:"payload_size"`
+	ReceivedAt  time.Time `json:"received_at"`
+	Stage       string    `json:"stage"`
+}
+
+// IngestionFailureResponse defines the model for ingestion_failure_response.
+type IngestionFailureResponse = struct {
+	Failures []IngestionFailure `json:"failures"`
+	Total    int64              `json:"total"`
+}
+
 // LogMessage defines the model for log_message.
 type LogMessage = struct {
 	Message string `json:"message"`
 }
 
+// PdbResults defines the model for pdb_results.
+type PdbResults = []map[string]interface{}
+
+// QueueStatus defines the model for queue_status.
+type QueueStatus string
+
+// List of QueueStatus
+const (
+	QueueStatusdone       QueueStatus = "done"
+	QueueStatusfailed     QueueStatus = "failed"
+	QueueStatuspending    QueueStatus = "pending"
+	QueueStatusprocessing QueueStatus = "processing"
+)
+
+func (e *QueueStatus) IsValid() bool {
+	if e == nil {
+		return false
+	}
+
+	switch *e {
+	case QueueStatusdone:
+		return true
+	case QueueStatusfailed:
+		return true
+	case QueueStatuspending:
+		return true
+	case QueueStatusprocessing:
+		return true
+	default:
+		return false
+	}
+}
+
+func (e *QueueStatus) MarshalJSON() ([]byte, error) {
+	if !e.IsValid() {
+		return nil, uhttp.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("%s is not a valid QueueStatus", *e))
+	}
+
+	return json.Marshal(string(*e))
+}
+
+func (e *QueueStatus) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	e2 := QueueStatus(s)
+	if !e2.IsValid() {
+		return uhttp.NewHTTPError(http.StatusBadRequest, fmt.Errorf("%s is not a valid QueueStatus", s))
+	}
+
+	*e = e2
+	return nil
+}
+
 // Report defines the model for report.
 type Report = struct {
-	Environment    string       `json:"environment"`
-	ExecutedAt     time.Time    `json:"executed_at"`
-	Hash           string       `json:"hash"`
-	Host           string       `json:"host"`
-	Id             int64        `json:"id"`
-	PuppetVersion  float32      `json:"puppet_version"`
-	RuntimeSeconds int64        `json:"runtime_seconds"`
-	Status         ReportStatus `json:"status"`
-	TotalChanged   int64        `json:"total_changed"`
-	TotalFailed    int64        `json:"total_failed"`
-	TotalResources int64        `json:"total_resources"`
-	TotalSkipped   int64        `json:"total_skipped"`
+	Environment   string    `json:"environment"`
+	ExecutedAt    time.Time `json:"executed_at"`
+	Hash          string    `json:"hash"`
+	Host          string    `json:"host"`
+	Id            int64     `json:"id"`
+	PuppetVersion float32   `json:"puppet_version"`
+
+	// RuntimeRegression Whether the runtime is well above the median runtime of the previous reports of the host, false if the host has too few previous reports
+	RuntimeRegression bool         `json:"runtime_regression"`
+	RuntimeSeconds    int64        `json:"runtime_seconds"`
+	Status            ReportStatus `json:"status"`
+	TotalChanged      int64        `json:"total_changed"`
+	TotalFailed       int64        `json:"total_failed"`
+	TotalResources    int64        `json:"total_resources"`
+	TotalSkipped      int64        `json:"total_skipped"`
 }
 
 // ReportDetails defines the model for report_details.
@@ -42,6 +275,17 @@ type ReportDetails = struct {
 	Resources []Resource   `json:"resources"`
 }
 
+// ReportQueueStatus defines the model for report_queue_status.
+type ReportQueueStatus = struct {
+	CreatedAt time.Time   `json:"created_at"`
+	Error     *string     `json:"error,omitempty"`
+	Hash      string      `json:"hash"`
+	Id        int64       `json:"id"`
+	Status    QueueStatus `json:"status"`
+	StatusUrl string      `json:"status_url"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
 // ReportResponse defines the model for report_response.
 type ReportResponse = struct {
 	Reports []Report `json:"reports"`
@@ -98,6 +342,32 @@ func (e *ReportStatus) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// ReportSummary defines the model for report_summary.
+type ReportSummary = struct {
+	Hash           string        `json:"hash"`
+	Host           string        `json:"host"`
+	PreviousStatus *ReportStatus `json:"previous_status,omitempty"`
+
+	// RuntimeRegression Whether the runtime is well above the median runtime of the previous reports of the host
+	RuntimeRegression bool         `json:"runtime_regression"`
+	Status            ReportStatus `json:"status"`
+
+	// StatusChanged Whether the status is not the status of the previous report of the host, false if there is none
+	StatusChanged  bool  `json:"status_changed"`
+	TotalChanged   int64 `json:"total_changed"`
+	TotalFailed    int64 `json:"total_failed"`
+	TotalResources int64 `json:"total_resources"`
+	TotalSkipped   int64 `json:"total_skipped"`
+}
+
+// ReportUpload defines the model for report_upload.
+type ReportUpload = struct {
+	Logs      *[]LogMessage  `json:"logs,omitempty"`
+	Report    *Report        `json:"report,omitempty"`
+	Resources *[]Resource    `json:"resources,omitempty"`
+	Summary   *ReportSummary `json:"summary,omitempty"`
+}
+
 // Resource defines the model for resource.
 type Resource = struct {
 	File   string `json:"file"`
@@ -160,21 +430,283 @@ func (e *Status) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// PdbLimit defines the model for pdb_limit.
+type PdbLimit = int
+
+// PdbOffset defines the model for pdb_offset.
+type PdbOffset = int
+
+// PdbOrderBy defines the model for pdb_order_by.
+type PdbOrderBy = string
+
+// PdbQuery defines the model for pdb_query.
+type PdbQuery = string
+
+// QueryAction defines the model for query_action.
+type QueryAction = string
+
+// QueryActor defines the model for query_actor.
+type QueryActor = string
+
 // QueryEnvironment defines the model for query_environment.
 type QueryEnvironment = string
 
+// QueryFact defines the model for query_fact.
+type QueryFact = []string
+
+// QueryFormat defines the model for query_format.
+type QueryFormat string
+
+// List of QueryFormat
+const (
+	QueryFormatcsv    QueryFormat = "csv"
+	QueryFormatjson   QueryFormat = "json"
+	QueryFormatndjson QueryFormat = "ndjson"
+)
+
+func (e *QueryFormat) IsValid() bool {
+	if e == nil {
+		return false
+	}
+
+	switch *e {
+	case QueryFormatcsv:
+		return true
+	case QueryFormatjson:
+		return true
+	case QueryFormatndjson:
+		return true
+	default:
+		return false
+	}
+}
+
+func (e *QueryFormat) MarshalJSON() ([]byte, error) {
+	if !e.IsValid() {
+		return nil, uhttp.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("%s is not a valid QueryFormat", *e))
+	}
+
+	return json.Marshal(string(*e))
+}
+
+func (e *QueryFormat) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	e2 := QueryFormat(s)
+	if !e2.IsValid() {
+		return uhttp.NewHTTPError(http.StatusBadRequest, fmt.Errorf("%s is not a valid QueryFormat", s))
+	}
+
+	*e = e2
+	return nil
+}
+
 // QueryFrom defines the model for query_from.
 type QueryFrom = time.Time
 
 // QueryHost defines the model for query_host.
 type QueryHost = string
 
+// QueryOccurredFrom defines the model for query_occurred_from.
+type QueryOccurredFrom = time.Time
+
+// QueryOccurredTo defines the model for query_occurred_to.
+type QueryOccurredTo = time.Time
+
+// QueryOutcome defines the model for query_outcome.
+type QueryOutcome = AuditOutcome
+
+// QueryQ defines the model for query_q.
+type QueryQ = string
+
+// QueryReceivedFrom defines the model for query_received_from.
+type QueryReceivedFrom = time.Time
+
+// QueryReceivedTo defines the model for query_received_to.
+type QueryReceivedTo = time.Time
+
+// QueryRuntimeRegression defines the model for query_runtime_regression.
+type QueryRuntimeRegression = bool
+
+// QueryStage defines the model for query_stage.
+type QueryStage = string
+
 // QueryState defines the model for query_state.
 type QueryState = Status
 
+// QuerySummary defines the model for query_summary.
+type QuerySummary = bool
+
+// QueryTarget defines the model for query_target.
+type QueryTarget = string
+
+// QueryThreshold defines the model for query_threshold.
+type QueryThreshold = float64
+
 // QueryTo defines the model for query_to.
 type QueryTo = time.Time
 
+// QueryWindow defines the model for query_window.
+type QueryWindow = int
+
+// GetAuditEventsParams defines parameters for GetAuditEvents.
+type GetAuditEventsParams struct {
+	// Limit Report type
+	Limit *externalRef0.LimitParam `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// LastVal Pagination details, last value of the sort column on the previous page.
+	LastVal *externalRef0.LastValue `form:"last_val,omitempty" json:"last_val,omitempty"`
+
+	// LastId Pagination details, last value of the id column on the previous page.
+	LastId *externalRef0.LastId `form:"last_id,omitempty" json:"last_id,omitempty"`
+
+	// SortBy Pagination details, sort column, if empty uses the id column.
+	SortBy *externalRef0.SortBy `form:"sort_by,omitempty" json:"sort_by,omitempty"`
+
+	// SortDir Pagination details, sorting order.
+	SortDir *GetAuditEventsParamsSortDir `form:"sort_dir,omitempty" json:"sort_dir,omitempty"`
+
+	// Action Filter by the action, such as report.upload
+	Action *QueryAction `form:"action,omitempty" json:"action,omitempty"`
+
+	// Outcome Filter by the outcome
+	Outcome *QueryOutcome `form:"outcome,omitempty" json:"outcome,omitempty"`
+
+	// Actor Filter by the IP address, certificate or token of the actor
+	Actor *QueryActor `form:"actor,omitempty" json:"actor,omitempty"`
+
+	// Target Filter by the target, such as the host of a report
+	Target *QueryTarget `form:"target,omitempty" json:"target,omitempty"`
+
+	// From Filter by occurred from date
+	From *QueryOccurredFrom `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Filter by occurred to date
+	To *QueryOccurredTo `form:"to,omitempty" json:"to,omitempty"`
+}
+
+// GetAuditEventsParamsSortDir defines parameters for GetAuditEvents.
+type GetAuditEventsParamsSortDir string
+
+// UploadFactsFormdataBody defines parameters for UploadFacts.
+type UploadFactsFormdataBody struct {
+	File *openapi_types.File `form:"file,omitempty" json:"file,omitempty"`
+}
+
+// GetFactHistoryParams defines parameters for GetFactHistory.
+type GetFactHistoryParams struct {
+	// Limit Report type
+	Limit *externalRef0.LimitParam `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// LastVal Pagination details, last value of the sort column on the previous page.
+	LastVal *externalRef0.LastValue `form:"last_val,omitempty" json:"last_val,omitempty"`
+
+	// LastId Pagination details, last value of the id column on the previous page.
+	LastId *externalRef0.LastId `form:"last_id,omitempty" json:"last_id,omitempty"`
+
+	// SortBy Pagination details, sort column, if empty uses the id column.
+	SortBy *externalRef0.SortBy `form:"sort_by,omitempty" json:"sort_by,omitempty"`
+
+	// SortDir Pagination details, sorting order.
+	SortDir *GetFactHistoryParamsSortDir `form:"sort_dir,omitempty" json:"sort_dir,omitempty"`
+}
+
+// GetFactHistoryParamsSortDir defines parameters for GetFactHistory.
+type GetFactHistoryParamsSortDir string
+
+// GetIngestionFailuresParams defines parameters for GetIngestionFailures.
+type GetIngestionFailuresParams struct {
+	// Limit Report type
+	Limit *externalRef0.LimitParam `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// LastVal Pagination details, last value of the sort column on the previous page.
+	LastVal *externalRef0.LastValue `form:"last_val,omitempty" json:"last_val,omitempty"`
+
+	// LastId Pagination details, last value of the id column on the previous page.
+	LastId *externalRef0.LastId `form:"last_id,omitempty" json:"last_id,omitempty"`
+
+	// SortBy Pagination details, sort column, if empty uses the id column.
+	SortBy *externalRef0.SortBy `form:"sort_by,omitempty" json:"sort_by,omitempty"`
+
+	// SortDir Pagination details, sorting order.
+	SortDir *GetIngestionFailuresParamsSortDir `form:"sort_dir,omitempty" json:"sort_dir,omitempty"`
+
+	// Stage Filter by the stage the ingestion failed at
+	Stage *QueryStage `form:"stage,omitempty" json:"stage,omitempty"`
+
+	// From Filter by received from date
+	From *QueryReceivedFrom `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Filter by received to date
+	To *QueryReceivedTo `form:"to,omitempty" json:"to,omitempty"`
+}
+
+// GetIngestionFailuresParamsSortDir defines parameters for GetIngestionFailures.
+type GetIngestionFailuresParamsSortDir string
+
+// QueryPDBEventsParams defines parameters for QueryPDBEvents.
+type QueryPDBEventsParams struct {
+	// Query The query of the PuppetDB AST query language, as JSON
+	Query *PdbQuery `form:"query,omitempty" json:"query,omitempty"`
+
+	// Limit The largest number of results to return
+	Limit *PdbLimit `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Offset The number of results to skip
+	Offset *PdbOffset `form:"offset,omitempty" json:"offset,omitempty"`
+
+	// OrderBy The fields to order the results by, as a JSON array of objects with a field and an order of asc or desc
+	OrderBy *PdbOrderBy `form:"order_by,omitempty" json:"order_by,omitempty"`
+}
+
+// QueryPDBNodesParams defines parameters for QueryPDBNodes.
+type QueryPDBNodesParams struct {
+	// Query The query of the PuppetDB AST query language, as JSON
+	Query *PdbQuery `form:"query,omitempty" json:"query,omitempty"`
+
+	// Limit The largest number of results to return
+	Limit *PdbLimit `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Offset The number of results to skip
+	Offset *PdbOffset `form:"offset,omitempty" json:"offset,omitempty"`
+
+	// OrderBy The fields to order the results by, as a JSON array of objects with a field and an order of asc or desc
+	OrderBy *PdbOrderBy `form:"order_by,omitempty" json:"order_by,omitempty"`
+}
+
+// QueryPDBReportsParams defines parameters for QueryPDBReports.
+type QueryPDBReportsParams struct {
+	// Query The query of the PuppetDB AST query language, as JSON
+	Query *PdbQuery `form:"query,omitempty" json:"query,omitempty"`
+
+	// Limit The largest number of results to return
+	Limit *PdbLimit `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Offset The number of results to skip
+	Offset *PdbOffset `form:"offset,omitempty" json:"offset,omitempty"`
+
+	// OrderBy The fields to order the results by, as a JSON array of objects with a field and an order of asc or desc
+	OrderBy *PdbOrderBy `form:"order_by,omitempty" json:"order_by,omitempty"`
+}
+
+// QueryPDBResourcesParams defines parameters for QueryPDBResources.
+type QueryPDBResourcesParams struct {
+	// Query The query of the PuppetDB AST query language, as JSON
+	Query *PdbQuery `form:"query,omitempty" json:"query,omitempty"`
+
+	// Limit The largest number of results to return
+	Limit *PdbLimit `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Offset The number of results to skip
+	Offset *PdbOffset `form:"offset,omitempty" json:"offset,omitempty"`
+
+	// OrderBy The fields to order the results by, as a JSON array of objects with a field and an order of asc or desc
+	OrderBy *PdbOrderBy `form:"order_by,omitempty" json:"order_by,omitempty"`
+}
+
 // GetReportsParams defines parameters for GetReports.
 type GetReportsParams struct {
 	// Limit Report type
@@ -206,18 +738,92 @@ type GetReportsParams struct {
 
 	// To Filter by executed to date
 	To *QueryTo `form:"to,omitempty" json:"to,omitempty"`
+
+	// Fact Filter by the latest facts of the host, as the dotted name and value of a fact such as os.family=RedHat
+	Fact *QueryFact `form:"fact,omitempty" json:"fact,omitempty"`
+
+	// RuntimeRegression Filter by whether the runtime of the report regressed from the baseline of the host
+	RuntimeRegression *QueryRuntimeRegression `form:"runtime_regression,omitempty" json:"runtime_regression,omitempty"`
+
+	// Q Filter by a search query. A term is a field, an operator and a value, such as host:web-* or runtime>300. The fields are host, env, state, hash, version, runtime, executed, failed, changed, skipped and total. The operators are : to match a value, where * matches any characters, :~ to match a regular expression, and <, <=, > and >= to compare numbers and times. Terms can be combined with AND, OR, NOT and parentheses, and terms next to each other are combined with AND. The regular expressions are run by the database, so only the syntax MySQL, PostgreSQL and SQLite share, such as anchors, classes, groups, alternation and repetition, works on every database.
+	Q *QueryQ `form:"q,omitempty" json:"q,omitempty"`
+
+	// Format The format of the response. csv and ndjson are streamed from the database, every report matching the filters for the reports and the resources for a report. The format can also be asked for with the Accept header, as text/csv or application/x-ndjson.
+	Format *GetReportsParamsFormat `form:"format,omitempty" json:"format,omitempty"`
 }
 
 // GetReportsParamsSortDir defines parameters for GetReports.
 type GetReportsParamsSortDir string
 
+// GetReportsParamsFormat defines parameters for GetReports.
+type GetReportsParamsFormat string
+
 // UploadReportFormdataBody defines parameters for UploadReport.
 type UploadReportFormdataBody struct {
 	File *openapi_types.File `form:"file,omitempty" json:"file,omitempty"`
 }
 
+// UploadReportParams defines parameters for UploadReport.
+type UploadReportParams struct {
+	// Summary Return only the summary of the uploaded report, its hash, state, counts and whether the state changed from the previous report of the host. The summary can also be asked for with the Prefer: return=minimal header. A server ingesting the reports asynchronously answers with the 202 status of the queued report instead, as the summary is only known once the report is ingested.
+	Summary *QuerySummary `form:"summary,omitempty" json:"summary,omitempty"`
+}
+
+// QueueReportFormdataBody defines parameters for QueueReport.
+type QueueReportFormdataBody struct {
+	File *openapi_types.File `form:"file,omitempty" json:"file,omitempty"`
+}
+
+// GetReportStreamParams defines parameters for GetReportStream.
+type GetReportStreamParams struct {
+	// Host Filter by host
+	Host *QueryHost `form:"host,omitempty" json:"host,omitempty"`
+
+	// Environment Filter by environment
+	Environment *QueryEnvironment `form:"environment,omitempty" json:"environment,omitempty"`
+
+	// State Filter by status
+	State *QueryState `form:"state,omitempty" json:"state,omitempty"`
+}
+
+// GetReportParams defines parameters for GetReport.
+type GetReportParams struct {
+	// Format The format of the response. csv and ndjson are streamed from the database, every report matching the filters for the reports and the resources for a report. The format can also be asked for with the Accept header, as text/csv or application/x-ndjson.
+	Format *GetReportParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// GetReportParamsFormat defines parameters for GetReport.
+type GetReportParamsFormat string
+
+// GetFlappingStatsParams defines parameters for GetFlappingStats.
+type GetFlappingStatsParams struct {
+	// Window The number of the latest reports of each host to look at, 10 if not set
+	Window *QueryWindow `form:"window,omitempty" json:"window,omitempty"`
+
+	// Threshold The share of the runs, from 0 to 1, a resource must be changed in or a host must change state in to flap, 0.5 if not set
+	Threshold *QueryThreshold `form:"threshold,omitempty" json:"threshold,omitempty"`
+
+	// Host Filter by host
+	Host *QueryHost `form:"host,omitempty" json:"host,omitempty"`
+
+	// Environment Filter by environment
+	Environment *QueryEnvironment `form:"environment,omitempty" json:"environment,omitempty"`
+}
+
+// UploadFactsFormdataRequestBody defines body for UploadFacts for application/x-www-form-urlencoded ContentType.
+type UploadFactsFormdataRequestBody UploadFactsFormdataBody
+
+// UploadFactsRequestBody defines a new type that can be used to unmarshal application/x-www-form-urlencoded request body.
+type UploadFactsRequestBody = UploadFactsFormdataBody
+
 // UploadReportFormdataRequestBody defines body for UploadReport for application/x-www-form-urlencoded ContentType.
 type UploadReportFormdataRequestBody UploadReportFormdataBody
 
 // UploadReportRequestBody defines a new type that can be used to unmarshal application/x-www-form-urlencoded request body.
 type UploadReportRequestBody = UploadReportFormdataBody
+
+// QueueReportFormdataRequestBody defines body for QueueReport for application/x-www-form-urlencoded ContentType.
+type QueueReportFormdataRequestBody QueueReportFormdataBody
+
+// QueueReportRequestBody defines a new type that can be used to unmarshal application/x-www-form-urlencoded request body.
+type QueueReportRequestBody = QueueReportFormdataBody
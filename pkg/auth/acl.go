@@ -0,0 +1,197 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/spf13/viper"
+)
+
+const (
+	// HeaderForwardedUser is the header a trusted proxy puts the authenticated user in.
+	HeaderForwardedUser = "X-Forwarded-User"
+
+	// AllEnvironments allows a role to read every environment.
+	AllEnvironments = "*"
+
+	// tokenIdentityPrefix is the prefix of the identity of an API token, so a forwarded user cannot take the
+	// identity of a token.
+	tokenIdentityPrefix = "token:"
+)
+
+// accessKey is the context key of the Access of a request.
+type accessKey struct{}
+
+// Access is what an identity is allowed to read.
+type Access struct {
+	// Identity is the user or token the access is for, empty if the request has no identity.
+	Identity string
+
+	// environments are the environments the identity can read, upper case as the reports store them.
+	environments []string
+
+	// all is true if the identity can read every environment.
+	all bool
+}
+
+// Unrestricted returns the access of an identity that can read every environment.
+func Unrestricted(identity string) *Access {
+	return &Access{
+		Identity: identity,
+		all:      true,
+	}
+}
+
+// Environments returns the environments the identity can read, and false if it can read every environment.
+func (a *Access) Environments() ([]string, bool) {
+	if a == nil || a.all {
+		return nil, false
+	}
+	return a.environments, true
+}
+
+// AllowsEnvironment returns true if the identity can read the environment. A nil Access allows everything, the
+// access control is not configured.
+func (a *Access) AllowsEnvironment(env string) bool {
+	if a == nil || a.all {
+		return true
+	}
+	return slices.Contains(a.environments, strings.ToUpper(env))
+}
+
+// WithAccess returns the context with the access of the request.
+func WithAccess(ctx context.Context, access *Access) context.Context {
+	return context.WithValue(ctx, accessKey{}, access)
+}
+
+// AccessFromContext returns the access of the request, nil if the access control is not configured.
+func AccessFromContext(ctx context.Context) *Access {
+	access, _ := ctx.Value(accessKey{}).(*Access)
+	return access
+}
+
+// ACL maps the identities to the environments they can read through their roles.
+type ACL struct {
+	// roles are the environments of each role.
+	roles map[string][]string
+
+	// identities are the roles of each identity.
+	identities map[string][]string
+
+	// trustForwardedUser is true if the identity is taken from the X-Forwarded-User header.
+	trustForwardedUser bool
+}
+
+// NewACL creates the access control from the environments of each role and the roles of each identity. The identity
+// of an API token is its name prefixed with "token:".
+func NewACL(roles, identities map[string][]string, trustForwardedUser bool) (*ACL, error) {
+	a := &ACL{
+		roles:              make(map[string][]string, len(roles)),
+		identities:         make(map[string][]string, len(identities)),
+		trustForwardedUser: trustForwardedUser,
+	}
+
+	// The role names are not case sensitive, the config keys are read in lower case.
+	for role, envs := range roles {
+		upper := make([]string, len(envs))
+		for i, env := range envs {
+			upper[i] = strings.ToUpper(env)
+		}
+		a.roles[strings.ToLower(role)] = upper
+	}
+
+	for identity, idRoles := range identities {
+		for _, role := range idRoles {
+			role = strings.ToLower(role)
+			if _, ok := a.roles[role]; !ok {
+				return nil, fmt.Errorf("identity %s has unknown role %s", identity, role)
+			}
+			a.identities[identity] = append(a.identities[identity], role)
+		}
+	}
+
+	return a, nil
+}
+
+// aclConfig is the acl section of the config. The identities are a list, as the users are often email addresses
+// which cannot be config keys.
+type aclConfig struct {
+	TrustForwardedUser bool                `mapstructure:"trust_forwarded_user"`
+	Roles              map[string][]string `mapstructure:"roles"`
+	Identities         []struct {
+		Name  string   `mapstructure:"name"`
+		Roles []string `mapstructure:"roles"`
+	} `mapstructure:"identities"`
+}
+
+// ACLFromConfig creates the access control from acl.roles, acl.identities and acl.trust_forwarded_user. It returns
+// nil if no roles are configured, every identity can then read every environment.
+func ACLFromConfig(v *viper.Viper) (*ACL, error) {
+	if !v.IsSet("acl.roles") {
+		return nil, nil
+	}
+
+	cfg := new(aclConfig)
+	if err := v.UnmarshalKey("acl", cfg); err != nil {
+		return nil, fmt.Errorf("error reading acl config: %w", err)
+	}
+
+	identities := make(map[string][]string, len(cfg.Identities))
+	for _, identity := range cfg.Identities {
+		identities[identity.Name] = append(identities[identity.Name], identity.Roles...)
+	}
+
+	return NewACL(cfg.Roles, identities, cfg.TrustForwardedUser)
+}
+
+// Access returns what the identity can read. An unknown identity cannot read any environment.
+func (a *ACL) Access(identity string) *Access {
+	access := &Access{
+		Identity:     identity,
+		environments: make([]string, 0),
+	}
+
+	for _, role := range a.identities[identity] {
+		for _, env := range a.roles[role] {
+			if env == AllEnvironments {
+				return Unrestricted(identity)
+			}
+			if !slices.Contains(access.environments, env) {
+				access.environments = append(access.environments, env)
+			}
+		}
+	}
+
+	return access
+}
+
+// TokenAccess returns what the API token with the name can read.
+func (a *ACL) TokenAccess(name string) *Access {
+	return a.Access(tokenIdentityPrefix + name)
+}
+
+// Middleware puts the access of the logged-in or forwarded user into the context of each request. Requests without a
+// session or a trusted forwarded user cannot read any environment, unless the API token of the request allows it.
+func (a *ACL) Middleware() mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			identity := ""
+			if session := SessionFromContext(r.Context()); session != nil {
+				identity = session.User
+			} else if a.trustForwardedUser {
+				identity = strings.TrimSpace(r.Header.Get(HeaderForwardedUser))
+			}
+
+			// The identities of the tokens can only be taken with the token.
+			if strings.HasPrefix(identity, tokenIdentityPrefix) {
+				identity = ""
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithAccess(r.Context(), a.Access(identity))))
+		})
+	}
+}
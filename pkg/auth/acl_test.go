@@ -0,0 +1,183 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACL_Access(t *testing.T) {
+	acl, err := NewACL(
+		map[string][]string{
+			"Prod":    {"production"},
+			"staging": {"staging", "Production"},
+			"ops":     {AllEnvironments},
+		},
+		map[string][]string{
+			"dev@example.com": {"prod", "staging"},
+			"ops@example.com": {"staging", "ops"},
+			"token:ci":        {"staging"},
+		},
+		false,
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		access         *Access
+		wantEnvs       []string
+		wantRestricted bool
+	}{
+		{
+			name:           "roles are merged",
+			access:         acl.Access("dev@example.com"),
+			wantEnvs:       []string{"PRODUCTION", "STAGING"},
+			wantRestricted: true,
+		},
+		{
+			name:   "all environments",
+			access: acl.Access("ops@example.com"),
+		},
+		{
+			name:           "unknown identity",
+			access:         acl.Access("nobody@example.com"),
+			wantEnvs:       []string{},
+			wantRestricted: true,
+		},
+		{
+			name:           "token",
+			access:         acl.TokenAccess("ci"),
+			wantEnvs:       []string{"STAGING", "PRODUCTION"},
+			wantRestricted: true,
+		},
+		{
+			name: "not configured",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			envs, restricted := tt.access.Environments()
+			require.Equal(t, tt.wantRestricted, restricted)
+			require.ElementsMatch(t, tt.wantEnvs, envs)
+
+			for _, env := range tt.wantEnvs {
+				require.True(t, tt.access.AllowsEnvironment(strings.ToLower(env)))
+			}
+			require.Equal(t, !tt.wantRestricted, tt.access.AllowsEnvironment("development"))
+		})
+	}
+}
+
+func TestNewACL_UnknownRole(t *testing.T) {
+	_, err := NewACL(
+		map[string][]string{"prod": {"production"}},
+		map[string][]string{"dev@example.com": {"admin"}},
+		false,
+	)
+	require.EqualError(t, err, "identity dev@example.com has unknown role admin")
+}
+
+func TestACL_Middleware(t *testing.T) {
+	roles := map[string][]string{"prod": {"production"}}
+	identities := map[string][]string{
+		"dev@example.com": {"prod"},
+		"token:ci":        {"prod"},
+	}
+
+	tests := []struct {
+		name           string
+		trust          bool
+		user           string
+		session        *Session
+		wantIdentity   string
+		wantRestricted bool
+		wantEnvs       []string
+	}{
+		{
+			name:           "trusted forwarded user",
+			trust:          true,
+			user:           "dev@example.com",
+			wantIdentity:   "dev@example.com",
+			wantEnvs:       []string{"PRODUCTION"},
+			wantRestricted: true,
+		},
+		{
+			name:           "untrusted forwarded user",
+			trust:          false,
+			user:           "dev@example.com",
+			wantEnvs:       []string{},
+			wantRestricted: true,
+		},
+		{
+			name:           "logged-in user",
+			trust:          true,
+			user:           "ops@example.com",
+			session:        &Session{User: "dev@example.com"},
+			wantIdentity:   "dev@example.com",
+			wantEnvs:       []string{"PRODUCTION"},
+			wantRestricted: true,
+		},
+		{
+			name:           "forwarded user cannot be a token",
+			trust:          true,
+			user:           "token:ci",
+			wantEnvs:       []string{},
+			wantRestricted: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			acl, err := NewACL(roles, identities, tt.trust)
+			require.NoError(t, err)
+
+			var got *Access
+			h := acl.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = AccessFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(HeaderForwardedUser, tt.user)
+			if tt.session != nil {
+				req = req.WithContext(WithSession(req.Context(), tt.session))
+			}
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			require.NotNil(t, got)
+			require.Equal(t, tt.wantIdentity, got.Identity)
+
+			envs, restricted := got.Environments()
+			require.Equal(t, tt.wantRestricted, restricted)
+			require.Equal(t, tt.wantEnvs, envs)
+		})
+	}
+}
+
+func TestACLFromConfig(t *testing.T) {
+	v := viper.New()
+	acl, err := ACLFromConfig(v)
+	require.NoError(t, err)
+	require.Nil(t, acl)
+
+	v.SetConfigType("json")
+	require.NoError(t, v.ReadConfig(strings.NewReader(`{
+		"acl": {
+			"trust_forwarded_user": true,
+			"roles": {"prod": ["production"]},
+			"identities": [{"name": "dev@example.com", "roles": ["prod"]}]
+		}
+	}`)))
+
+	acl, err = ACLFromConfig(v)
+	require.NoError(t, err)
+	require.True(t, acl.trustForwardedUser)
+
+	envs, restricted := acl.Access("dev@example.com").Environments()
+	require.True(t, restricted)
+	require.Equal(t, []string{"PRODUCTION"}, envs)
+}
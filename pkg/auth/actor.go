@@ -0,0 +1,30 @@
+package auth
+
+import "context"
+
+// actorKey is the context key of the Actor of a request.
+type actorKey struct{}
+
+// Actor is who made a request, as recorded in the audit log.
+type Actor struct {
+	// IP is the IP address of the client.
+	IP string
+
+	// Certificate is the common name of the verified client certificate, empty if the client did not present one.
+	Certificate string
+
+	// Token is the name of the API token, empty until the token has been validated.
+	Token string
+}
+
+// WithActor returns the context with the actor of the request. The actor is shared, so the identities found while the
+// request is authorised are seen by whoever put it in the context.
+func WithActor(ctx context.Context, actor *Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor of the request, nil if the request is not audited.
+func ActorFromContext(ctx context.Context) *Actor {
+	actor, _ := ctx.Value(actorKey{}).(*Actor)
+	return actor
+}
@@ -0,0 +1,283 @@
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jacobbrewer1/puppet-reporter/pkg/logging"
+	"github.com/spf13/viper"
+)
+
+var (
+	// ErrCertificateRevoked is returned when a client certificate has been revoked by the CRL.
+	ErrCertificateRevoked = errors.New("certificate has been revoked")
+)
+
+const (
+	// pemTypeCertificate is the PEM block type of a certificate.
+	pemTypeCertificate = "CERTIFICATE"
+
+	// pemTypeCRL is the PEM block type of a certificate revocation list.
+	pemTypeCRL = "X509 CRL"
+)
+
+// TLSConfig creates the TLS configuration of the server from tls.cert_file, tls.key_file, tls.client_ca_file and
+// tls.crl_file. It returns nil if TLS is not configured, the server then serves plain HTTP.
+func TLSConfig(v *viper.Viper) (*tls.Config, error) {
+	certFile := v.GetString("tls.cert_file")
+	clientCAFile := v.GetString("tls.client_ca_file")
+	crlFile := v.GetString("tls.crl_file")
+
+	switch {
+	case certFile == "" && (clientCAFile != "" || crlFile != ""):
+		return nil, errors.New("tls.cert_file is required for client certificates")
+	case certFile == "":
+		return nil, nil
+	case crlFile != "" && clientCAFile == "":
+		return nil, errors.New("tls.client_ca_file is required for the CRL")
+	}
+
+	var crl *CRL
+	if crlFile != "" {
+		var err error
+		crl, err = LoadCRL(crlFile, clientCAFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ServerTLSConfig(certFile, v.GetString("tls.key_file"), clientCAFile, crl)
+}
+
+// ServerTLSConfig creates the TLS configuration of the server from the certificate and key. If the client CA bundle
+// is set, the clients may present a certificate signed by one of the CAs, the routes that need a client certificate
+// check for it. The client certificates are checked against the CRL if it is set.
+func ServerTLSConfig(certFile, keyFile, clientCAFile string, crl *CRL) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("error loading server certificate: %w", err)
+	}
+
+	cfg := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+
+	if clientCAFile == "" {
+		return cfg, nil
+	}
+
+	cas, err := loadCertificates(clientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("error loading client CA bundle: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	for _, ca := range cas {
+		pool.AddCert(ca)
+	}
+
+	// The web UI and the reads do not need a client certificate, so the certificate is only verified if given.
+	cfg.ClientAuth = tls.VerifyClientCertIfGiven
+	cfg.ClientCAs = pool
+
+	if crl != nil {
+		cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+			for _, chain := range cs.VerifiedChains {
+				for _, c := range chain {
+					revoked, err := crl.Revoked(c)
+					if err != nil {
+						return err
+					} else if revoked {
+						return fmt.Errorf("%w: %s", ErrCertificateRevoked, c.Subject.CommonName)
+					}
+				}
+			}
+			return nil
+		}
+	}
+
+	return cfg, nil
+}
+
+// ClientCertificate returns the verified client certificate of the request, nil if the client did not present one.
+func ClientCertificate(r *http.Request) *x509.Certificate {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return r.TLS.VerifiedChains[0][0]
+}
+
+// CertificateMatchesHost returns true if the common name or one of the DNS names of the certificate is the host. The
+// Puppet CA signs the agent certificates with the certname of the node, which is the host of its reports.
+func CertificateMatchesHost(cert *x509.Certificate, host string) bool {
+	if strings.EqualFold(cert.Subject.CommonName, host) {
+		return true
+	}
+
+	for _, name := range cert.DNSNames {
+		if strings.EqualFold(name, host) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CRL is a certificate revocation list read from a file. The file may hold a CRL for each CA of the chain, as the
+// Puppet CA writes it. The file is read again when it changes, so the revocations are picked up without restarting.
+type CRL struct {
+	// path is the path of the CRL file.
+	path string
+
+	// issuers are the certificates the CRLs may be signed by.
+	issuers []*x509.Certificate
+
+	// mu guards the fields below.
+	mu sync.Mutex
+
+	// modTime is the modification time of the file when it was last read.
+	modTime time.Time
+
+	// revoked are the serial numbers of the revoked certificates, by the raw issuer of the CRL revoking them.
+	revoked map[string]map[string]struct{}
+}
+
+// LoadCRL reads the CRL file, PEM or DER encoded. Each CRL must be signed by one of the certificates in the CA bundle.
+func LoadCRL(path, caFile string) (*CRL, error) {
+	issuers, err := loadCertificates(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("error loading CRL issuers: %w", err)
+	}
+
+	c := &CRL{
+		path:    path,
+		issuers: issuers,
+	}
+
+	if err := c.reload(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Revoked returns true if the certificate is revoked by the CRL of its issuer.
+func (c *CRL) Revoked(cert *x509.Certificate) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.reload(); err != nil {
+		// Keep using the last CRL that was read, rather than rejecting every client.
+		slog.Error("Error reloading CRL", slog.String("path", c.path), slog.String(logging.KeyError, err.Error()))
+	}
+
+	if c.revoked == nil {
+		return false, errors.New("no CRL loaded")
+	}
+
+	_, ok := c.revoked[string(cert.RawIssuer)][cert.SerialNumber.String()]
+	return ok, nil
+}
+
+// reload reads the CRL file if it has changed since it was last read. The caller must hold the lock, or be the
+// constructor.
+func (c *CRL) reload() error {
+	info, err := os.Stat(c.path)
+	if err != nil {
+		return fmt.Errorf("error reading CRL: %w", err)
+	} else if info.ModTime().Equal(c.modTime) {
+		return nil
+	}
+
+	bts, err := os.ReadFile(c.path)
+	if err != nil {
+		return fmt.Errorf("error reading CRL: %w", err)
+	}
+
+	ders := make([][]byte, 0)
+	for rest := bts; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		} else if block.Type != pemTypeCRL {
+			return fmt.Errorf("unexpected PEM block %q in CRL", block.Type)
+		}
+		ders = append(ders, block.Bytes)
+	}
+	if len(ders) == 0 {
+		// The file is not PEM encoded, so it is a single DER encoded CRL.
+		ders = append(ders, bts)
+	}
+
+	revoked := make(map[string]map[string]struct{}, len(ders))
+	total := 0
+	for _, der := range ders {
+		rl, err := x509.ParseRevocationList(der)
+		if err != nil {
+			return fmt.Errorf("error parsing CRL: %w", err)
+		}
+
+		if !slices.ContainsFunc(c.issuers, func(issuer *x509.Certificate) bool {
+			return rl.CheckSignatureFrom(issuer) == nil
+		}) {
+			return fmt.Errorf("CRL of %s is not signed by the client CA", rl.Issuer.CommonName)
+		}
+
+		serials := make(map[string]struct{}, len(rl.RevokedCertificateEntries))
+		for _, entry := range rl.RevokedCertificateEntries {
+			serials[entry.SerialNumber.String()] = struct{}{}
+		}
+		revoked[string(rl.RawIssuer)] = serials
+		total += len(serials)
+	}
+
+	c.modTime = info.ModTime()
+	c.revoked = revoked
+
+	slog.Info("Loaded CRL", slog.String("path", c.path), slog.Int("revoked", total))
+
+	return nil
+}
+
+// loadCertificates reads the PEM encoded certificates in the file.
+func loadCertificates(path string) ([]*x509.Certificate, error) {
+	bts, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	certs := make([]*x509.Certificate, 0)
+	for {
+		var block *pem.Block
+		block, bts = pem.Decode(bts)
+		if block == nil {
+			break
+		} else if block.Type != pemTypeCertificate {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+
+	return certs, nil
+}
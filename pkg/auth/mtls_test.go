@@ -0,0 +1,238 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+// testCA is a certificate authority signing the certificates of a test.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCA{cert: cert, key: key}
+}
+
+// issue signs a client certificate for the common name and DNS names.
+func (ca *testCA) issue(t *testing.T, serial int64, cn string, dnsNames ...string) *x509.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: cn},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return cert
+}
+
+// writeCRL writes a PEM encoded CRL revoking the serials to the path.
+func (ca *testCA) writeCRL(t *testing.T, path string, number int64, serials ...int64) {
+	t.Helper()
+
+	entries := make([]x509.RevocationListEntry, len(serials))
+	for i, serial := range serials {
+		entries[i] = x509.RevocationListEntry{
+			SerialNumber:   big.NewInt(serial),
+			RevocationTime: time.Now(),
+		}
+	}
+
+	der, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:                    big.NewInt(number),
+		ThisUpdate:                time.Now(),
+		NextUpdate:                time.Now().Add(time.Hour),
+		RevokedCertificateEntries: entries,
+	}, ca.cert, ca.key)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: pemTypeCRL, Bytes: der}), 0o600))
+}
+
+// writeCert writes the PEM encoded certificate to the path.
+func writeCert(t *testing.T, path string, cert *x509.Certificate) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: pemTypeCertificate, Bytes: cert.Raw}), 0o600))
+}
+
+func TestCertificateMatchesHost(t *testing.T) {
+	ca := newTestCA(t, "Puppet CA")
+
+	tests := []struct {
+		name string
+		cert *x509.Certificate
+		host string
+		want bool
+	}{
+		{
+			name: "common name",
+			cert: ca.issue(t, 2, "web-1.example.com"),
+			host: "web-1.example.com",
+			want: true,
+		},
+		{
+			name: "common name is not case sensitive",
+			cert: ca.issue(t, 3, "Web-1.example.com"),
+			host: "web-1.example.com",
+			want: true,
+		},
+		{
+			name: "dns name",
+			cert: ca.issue(t, 4, "web-1", "web-1.example.com"),
+			host: "web-1.example.com",
+			want: true,
+		},
+		{
+			name: "another host",
+			cert: ca.issue(t, 5, "web-1.example.com", "web-1"),
+			host: "db-1.example.com",
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, CertificateMatchesHost(tt.cert, tt.host))
+		})
+	}
+}
+
+func TestCRL_Revoked(t *testing.T) {
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	crlFile := filepath.Join(dir, "crl.pem")
+
+	ca := newTestCA(t, "Puppet CA")
+	writeCert(t, caFile, ca.cert)
+	ca.writeCRL(t, crlFile, 1, 10)
+
+	crl, err := LoadCRL(crlFile, caFile)
+	require.NoError(t, err)
+
+	revoked, err := crl.Revoked(ca.issue(t, 10, "web-1"))
+	require.NoError(t, err)
+	require.True(t, revoked)
+
+	revoked, err = crl.Revoked(ca.issue(t, 11, "web-2"))
+	require.NoError(t, err)
+	require.False(t, revoked)
+
+	// The certificates of another CA are not revoked by the CRL, even with the same serial.
+	revoked, err = crl.Revoked(newTestCA(t, "Other CA").issue(t, 10, "web-1"))
+	require.NoError(t, err)
+	require.False(t, revoked)
+
+	// A new CRL is read when the file changes.
+	ca.writeCRL(t, crlFile, 2, 10, 11)
+	require.NoError(t, os.Chtimes(crlFile, time.Now(), time.Now().Add(time.Minute)))
+
+	revoked, err = crl.Revoked(ca.issue(t, 11, "web-2"))
+	require.NoError(t, err)
+	require.True(t, revoked)
+}
+
+func TestLoadCRL_WrongIssuer(t *testing.T) {
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	crlFile := filepath.Join(dir, "crl.pem")
+
+	writeCert(t, caFile, newTestCA(t, "Puppet CA").cert)
+	newTestCA(t, "Other CA").writeCRL(t, crlFile, 1, 10)
+
+	_, err := LoadCRL(crlFile, caFile)
+	require.ErrorContains(t, err, "not signed by the client CA")
+}
+
+func TestTLSConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]string
+		wantErr string
+	}{
+		{
+			name:   "not configured",
+			config: map[string]string{},
+		},
+		{
+			name: "client CA without certificate",
+			config: map[string]string{
+				"tls.client_ca_file": "ca.pem",
+			},
+			wantErr: "tls.cert_file is required",
+		},
+		{
+			name: "CRL without client CA",
+			config: map[string]string{
+				"tls.cert_file": "cert.pem",
+				"tls.key_file":  "key.pem",
+				"tls.crl_file":  "crl.pem",
+			},
+			wantErr: "tls.client_ca_file is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := viper.New()
+			for k, val := range tt.config {
+				v.Set(k, val)
+			}
+
+			cfg, err := TLSConfig(v)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Nil(t, cfg)
+		})
+	}
+}
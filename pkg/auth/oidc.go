@@ -0,0 +1,562 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/gorilla/mux"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/logging"
+	"github.com/jacobbrewer1/uhttp"
+	"github.com/spf13/viper"
+)
+
+var (
+	// ErrInvalidIDToken is returned when the ID token from the provider cannot be verified.
+	ErrInvalidIDToken = errors.New("invalid id token")
+
+	// ErrGroupNotAllowed is returned when the user is not in any of the groups allowed to log in.
+	ErrGroupNotAllowed = errors.New("user is not in an allowed group")
+)
+
+const (
+	// LoginPath is the path that starts the login.
+	LoginPath = "/login"
+
+	// LogoutPath is the path that ends the session.
+	LogoutPath = "/logout"
+
+	// flowCookieName is the name of the cookie holding the state of a login until the provider redirects back.
+	flowCookieName = "puppet_reporter_oidc"
+
+	// flowTTL is how long the user has to log in with the provider.
+	flowTTL = 10 * time.Minute
+
+	// discoveryPath is the path of the provider metadata, relative to the issuer.
+	discoveryPath = "/.well-known/openid-configuration"
+
+	// jwksRefreshInterval is how often the signing keys can be fetched again for an unknown key ID, so the tokens
+	// with made up key IDs cannot flood the provider.
+	jwksRefreshInterval = time.Minute
+
+	// clockSkew is the leeway of the times of the ID token.
+	clockSkew = time.Minute
+
+	// headerHTMXRequest is set by htmx on the requests it makes, they cannot follow a redirect to the provider.
+	headerHTMXRequest = "HX-Request"
+)
+
+// signatureAlgorithms are the algorithms the ID tokens may be signed with.
+var signatureAlgorithms = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512,
+	jose.ES256, jose.ES384, jose.ES512,
+	jose.PS256, jose.PS384, jose.PS512,
+}
+
+// OIDCConfig is the oidc section of the config.
+type OIDCConfig struct {
+	// Issuer is the URL of the provider, the metadata is discovered from it.
+	Issuer string `mapstructure:"issuer"`
+
+	// ClientID is the ID of the client registered with the provider.
+	ClientID string `mapstructure:"client_id"`
+
+	// ClientSecret is the secret of the client, empty for a public client.
+	ClientSecret string `mapstructure:"client_secret"`
+
+	// RedirectURL is the URL the provider redirects back to, its path is the callback route.
+	RedirectURL string `mapstructure:"redirect_url"`
+
+	// PostLogoutRedirectURL is where the provider redirects to after logging out, if it supports it.
+	PostLogoutRedirectURL string `mapstructure:"post_logout_redirect_url"`
+
+	// Scopes are the scopes requested from the provider.
+	Scopes []string `mapstructure:"scopes"`
+
+	// Claims maps the claims of the ID token to the session.
+	Claims struct {
+		// Username is the claim the name of the user is taken from.
+		Username string `mapstructure:"username"`
+
+		// Groups is the claim the groups of the user are taken from, empty if the groups are not used.
+		Groups string `mapstructure:"groups"`
+	} `mapstructure:"claims"`
+
+	// AllowedGroups are the groups that can log in, everyone can log in if empty.
+	AllowedGroups []string `mapstructure:"allowed_groups"`
+
+	// SessionKey is the key the cookies are signed with, at least 32 bytes.
+	SessionKey string `mapstructure:"session_key"`
+
+	// SessionTTL is how long a session lasts before the user logs in again.
+	SessionTTL time.Duration `mapstructure:"session_ttl"`
+}
+
+// providerMetadata is the metadata of the provider, discovered from the issuer.
+type providerMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+	EndSessionEndpoint    string `json:"end_session_endpoint"`
+}
+
+// loginFlow is the state of a login, kept in a cookie until the provider redirects back.
+type loginFlow struct {
+	State     string `json:"state"`
+	Nonce     string `json:"nonce"`
+	Verifier  string `json:"verifier"`
+	Return    string `json:"return"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// OIDC logs the users of the web UI in with an OpenID Connect provider, using the authorization code flow with PKCE.
+type OIDC struct {
+	// cfg is the configuration of the client.
+	cfg *OIDCConfig
+
+	// client makes the requests to the provider.
+	client *http.Client
+
+	// provider is the metadata of the provider.
+	provider *providerMetadata
+
+	// callbackPath is the path of the redirect URL.
+	callbackPath string
+
+	// sessions stores the sessions and the login flows.
+	sessions *Sessions
+
+	// mu guards the fields below.
+	mu sync.Mutex
+
+	// keys are the signing keys of the provider.
+	keys *jose.JSONWebKeySet
+
+	// keysFetchedAt is when the keys were last fetched.
+	keysFetchedAt time.Time
+}
+
+// OIDCFromConfig creates the OIDC login from the oidc section of the config. It returns nil if oidc.issuer is not
+// set, the web UI then does not need a login.
+func OIDCFromConfig(ctx context.Context, v *viper.Viper) (*OIDC, error) {
+	if !v.IsSet("oidc.issuer") {
+		return nil, nil
+	}
+
+	v.SetDefault("oidc.scopes", []string{"openid", "profile", "email"})
+	v.SetDefault("oidc.claims.username", "email")
+	v.SetDefault("oidc.session_ttl", 8*time.Hour)
+
+	cfg := new(OIDCConfig)
+	if err := v.UnmarshalKey("oidc", cfg); err != nil {
+		return nil, fmt.Errorf("error reading oidc config: %w", err)
+	}
+
+	return NewOIDC(ctx, cfg, &http.Client{Timeout: 10 * time.Second})
+}
+
+// NewOIDC creates the OIDC login, discovering the metadata of the provider from the issuer.
+func NewOIDC(ctx context.Context, cfg *OIDCConfig, client *http.Client) (*OIDC, error) {
+	switch {
+	case cfg.ClientID == "":
+		return nil, errors.New("oidc.client_id is required")
+	case cfg.RedirectURL == "":
+		return nil, errors.New("oidc.redirect_url is required")
+	case cfg.Claims.Username == "":
+		return nil, errors.New("oidc.claims.username is required")
+	case len(cfg.AllowedGroups) > 0 && cfg.Claims.Groups == "":
+		return nil, errors.New("oidc.claims.groups is required for oidc.allowed_groups")
+	}
+
+	redirect, err := url.Parse(cfg.RedirectURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid oidc.redirect_url: %w", err)
+	} else if redirect.Path == "" || redirect.Path == "/" || redirect.Path == LoginPath || redirect.Path == LogoutPath {
+		return nil, fmt.Errorf("oidc.redirect_url must have a callback path, not %q", redirect.Path)
+	}
+
+	// The cookies are only sent over HTTPS if the web UI is served over HTTPS.
+	sessions, err := NewSessions([]byte(cfg.SessionKey), cfg.SessionTTL, redirect.Scheme == "https")
+	if err != nil {
+		return nil, fmt.Errorf("invalid oidc.session_key: %w", err)
+	}
+
+	o := &OIDC{
+		cfg:          cfg,
+		client:       client,
+		callbackPath: redirect.Path,
+		sessions:     sessions,
+	}
+
+	o.provider, err = o.discover(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error discovering oidc provider: %w", err)
+	}
+
+	return o, nil
+}
+
+// discover fetches the metadata of the provider.
+func (o *OIDC) discover(ctx context.Context) (*providerMetadata, error) {
+	issuer := strings.TrimSuffix(o.cfg.Issuer, "/")
+
+	m := new(providerMetadata)
+	if err := o.getJSON(ctx, issuer+discoveryPath, m); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case strings.TrimSuffix(m.Issuer, "/") != issuer:
+		return nil, fmt.Errorf("provider issuer %q does not match %q", m.Issuer, o.cfg.Issuer)
+	case m.AuthorizationEndpoint == "" || m.TokenEndpoint == "" || m.JWKSURI == "":
+		return nil, errors.New("provider metadata is missing an endpoint")
+	}
+
+	return m, nil
+}
+
+// Register registers the login, callback and logout routes.
+func (o *OIDC) Register(r *mux.Router) {
+	r.HandleFunc(LoginPath, o.login).Methods(http.MethodGet)
+	r.HandleFunc(o.callbackPath, o.callback).Methods(http.MethodGet)
+	r.HandleFunc(LogoutPath, o.logout).Methods(http.MethodGet, http.MethodPost)
+}
+
+// Middleware puts the session of each request into the context. It does not require a session, the routes that do
+// are wrapped with Require.
+func (o *OIDC) Middleware() mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			session, err := o.sessions.Get(r)
+			if err == nil {
+				r = r.WithContext(WithSession(r.Context(), session))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Require sends the users that are not logged in to the login. The requests made by htmx get a 401, as the page
+// cannot be swapped with the login of the provider.
+func (o *OIDC) Require(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if SessionFromContext(r.Context()) != nil {
+			next(w, r)
+			return
+		}
+
+		if r.Method != http.MethodGet || r.Header.Get(headerHTMXRequest) != "" {
+			uhttp.SendMessageWithStatus(w, http.StatusUnauthorized, "login required")
+			return
+		}
+
+		http.Redirect(w, r, LoginPath+"?"+url.Values{"return": {r.URL.RequestURI()}}.Encode(), http.StatusFound)
+	}
+}
+
+// login redirects the user to the provider.
+func (o *OIDC) login(w http.ResponseWriter, r *http.Request) {
+	flow, err := newLoginFlow(r.URL.Query().Get("return"))
+	if err == nil {
+		err = o.sessions.write(w, flowCookieName, flow, flowTTL)
+	}
+	if err != nil {
+		slog.Error("Error starting login", slog.String(logging.KeyError, err.Error()))
+		uhttp.SendMessageWithStatus(w, http.StatusInternalServerError, "error starting login")
+		return
+	}
+
+	challenge := sha256.Sum256([]byte(flow.Verifier))
+	q := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {o.cfg.ClientID},
+		"redirect_uri":          {o.cfg.RedirectURL},
+		"scope":                 {strings.Join(o.cfg.Scopes, " ")},
+		"state":                 {flow.State},
+		"nonce":                 {flow.Nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+
+	http.Redirect(w, r, o.provider.AuthorizationEndpoint+"?"+q.Encode(), http.StatusFound)
+}
+
+// callback exchanges the code from the provider for the ID token and starts the session.
+func (o *OIDC) callback(w http.ResponseWriter, r *http.Request) {
+	flow := new(loginFlow)
+	err := o.sessions.read(r, flowCookieName, flow)
+	o.sessions.clear(w, flowCookieName)
+	if err != nil || time.Now().Unix() >= flow.ExpiresAt {
+		uhttp.SendMessageWithStatus(w, http.StatusBadRequest, "login has expired, try again")
+		return
+	}
+
+	q := r.URL.Query()
+	if e := q.Get("error"); e != "" {
+		slog.Warn("Login failed at the provider", slog.String("error", e), slog.String("description", q.Get("error_description")))
+		uhttp.SendMessageWithStatus(w, http.StatusUnauthorized, "login failed")
+		return
+	} else if subtle.ConstantTimeCompare([]byte(q.Get("state")), []byte(flow.State)) != 1 {
+		uhttp.SendMessageWithStatus(w, http.StatusBadRequest, "invalid login state")
+		return
+	}
+
+	rawIDToken, err := o.exchange(r.Context(), q.Get("code"), flow.Verifier)
+	if err != nil {
+		slog.Error("Error exchanging login code", slog.String(logging.KeyError, err.Error()))
+		uhttp.SendMessageWithStatus(w, http.StatusBadGateway, "error completing login")
+		return
+	}
+
+	session, err := o.verify(r.Context(), rawIDToken, flow.Nonce)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrGroupNotAllowed):
+			slog.Warn("User is not allowed to log in", slog.String(logging.KeyError, err.Error()))
+			uhttp.SendMessageWithStatus(w, http.StatusForbidden, "not allowed to log in")
+		default:
+			slog.Error("Error verifying id token", slog.String(logging.KeyError, err.Error()))
+			uhttp.SendMessageWithStatus(w, http.StatusUnauthorized, "login failed")
+		}
+		return
+	}
+
+	if err := o.sessions.Set(w, session); err != nil {
+		slog.Error("Error starting session", slog.String(logging.KeyError, err.Error()))
+		uhttp.SendMessageWithStatus(w, http.StatusInternalServerError, "error starting session")
+		return
+	}
+
+	slog.Info("User logged in", slog.String("user", session.User))
+
+	http.Redirect(w, r, flow.Return, http.StatusFound)
+}
+
+// logout ends the session, and the session with the provider if it supports it.
+func (o *OIDC) logout(w http.ResponseWriter, r *http.Request) {
+	o.sessions.Clear(w)
+
+	if o.provider.EndSessionEndpoint == "" {
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	q := url.Values{"client_id": {o.cfg.ClientID}}
+	if o.cfg.PostLogoutRedirectURL != "" {
+		q.Set("post_logout_redirect_uri", o.cfg.PostLogoutRedirectURL)
+	}
+
+	http.Redirect(w, r, o.provider.EndSessionEndpoint+"?"+q.Encode(), http.StatusFound)
+}
+
+// exchange exchanges the code for the tokens, returning the ID token.
+func (o *OIDC) exchange(ctx context.Context, code, verifier string) (string, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {o.cfg.RedirectURL},
+		"code_verifier": {verifier},
+	}
+	if o.cfg.ClientSecret == "" {
+		form.Set("client_id", o.cfg.ClientID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.provider.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if o.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(o.cfg.ClientID), url.QueryEscape(o.cfg.ClientSecret))
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("token endpoint returned %s: %s", resp.Status, body)
+	}
+
+	tokens := new(struct {
+		IDToken string `json:"id_token"`
+	})
+	if err := json.NewDecoder(resp.Body).Decode(tokens); err != nil {
+		return "", fmt.Errorf("error decoding token response: %w", err)
+	} else if tokens.IDToken == "" {
+		return "", errors.New("token response has no id token")
+	}
+
+	return tokens.IDToken, nil
+}
+
+// verify verifies the ID token and creates the session from its claims.
+func (o *OIDC) verify(ctx context.Context, rawIDToken, nonce string) (*Session, error) {
+	tok, err := jwt.ParseSigned(rawIDToken, signatureAlgorithms)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIDToken, err)
+	}
+
+	key, err := o.key(ctx, tok.Headers[0].KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	std := new(jwt.Claims)
+	claims := make(map[string]any)
+	if err := tok.Claims(key, std, &claims); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIDToken, err)
+	}
+
+	if err := std.ValidateWithLeeway(jwt.Expected{
+		Issuer:      o.provider.Issuer,
+		AnyAudience: jwt.Audience{o.cfg.ClientID},
+		Time:        time.Now(),
+	}, clockSkew); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIDToken, err)
+	} else if got, _ := claims["nonce"].(string); subtle.ConstantTimeCompare([]byte(got), []byte(nonce)) != 1 {
+		return nil, fmt.Errorf("%w: nonce does not match", ErrInvalidIDToken)
+	}
+
+	session := &Session{
+		Subject: std.Subject,
+	}
+
+	session.User, _ = claims[o.cfg.Claims.Username].(string)
+	if session.User == "" {
+		return nil, fmt.Errorf("%w: missing %s claim", ErrInvalidIDToken, o.cfg.Claims.Username)
+	}
+
+	if o.cfg.Claims.Groups != "" {
+		session.Groups = claimStrings(claims[o.cfg.Claims.Groups])
+	}
+
+	if len(o.cfg.AllowedGroups) > 0 && !slices.ContainsFunc(session.Groups, func(g string) bool {
+		return slices.Contains(o.cfg.AllowedGroups, g)
+	}) {
+		return nil, fmt.Errorf("%w: %s", ErrGroupNotAllowed, session.User)
+	}
+
+	return session, nil
+}
+
+// key returns the signing key with the ID, fetching the keys again if it is unknown as the provider may have rotated
+// them.
+func (o *OIDC) key(ctx context.Context, kid string) (*jose.JSONWebKey, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.keys != nil {
+		if keys := o.keys.Key(kid); len(keys) > 0 {
+			return &keys[0], nil
+		} else if time.Since(o.keysFetchedAt) < jwksRefreshInterval {
+			return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidIDToken, kid)
+		}
+	}
+
+	keys := new(jose.JSONWebKeySet)
+	if err := o.getJSON(ctx, o.provider.JWKSURI, keys); err != nil {
+		return nil, fmt.Errorf("error fetching signing keys: %w", err)
+	}
+	o.keys = keys
+	o.keysFetchedAt = time.Now()
+
+	if found := keys.Key(kid); len(found) > 0 {
+		return &found[0], nil
+	}
+
+	// A provider with one key may not set the key ID on the tokens.
+	if kid == "" && len(keys.Keys) == 1 {
+		return &keys.Keys[0], nil
+	}
+
+	return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidIDToken, kid)
+}
+
+// getJSON fetches the URL and decodes the JSON response into the value.
+func (o *OIDC) getJSON(ctx context.Context, u string, value any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", u, resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(value)
+}
+
+// claimStrings returns the strings of a claim, which is either a list or a single string.
+func claimStrings(claim any) []string {
+	switch c := claim.(type) {
+	case string:
+		return []string{c}
+	case []any:
+		strs := make([]string, 0, len(c))
+		for _, v := range c {
+			if s, ok := v.(string); ok {
+				strs = append(strs, s)
+			}
+		}
+		return strs
+	default:
+		return nil
+	}
+}
+
+// safeReturnPath returns the path to return to after logging in, "/" if it is not a local path so the login cannot
+// redirect to another site.
+func safeReturnPath(path string) string {
+	if !strings.HasPrefix(path, "/") || strings.HasPrefix(path, "//") || strings.HasPrefix(path, "/\\") {
+		return "/"
+	}
+	return path
+}
+
+// newLoginFlow creates the state of a login with a random state, nonce and PKCE verifier.
+func newLoginFlow(returnPath string) (*loginFlow, error) {
+	values := make([]string, 3)
+	for i := range values {
+		bts := make([]byte, 32)
+		if _, err := rand.Read(bts); err != nil {
+			return nil, fmt.Errorf("error generating login state: %w", err)
+		}
+		values[i] = base64.RawURLEncoding.EncodeToString(bts)
+	}
+
+	return &loginFlow{
+		State:     values[0],
+		Nonce:     values[1],
+		Verifier:  values[2],
+		Return:    safeReturnPath(returnPath),
+		ExpiresAt: time.Now().Add(flowTTL).Unix(),
+	}, nil
+}
@@ -0,0 +1,340 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testClientID     = "puppet-reporter"
+	testClientSecret = "secret"
+	testCode         = "code"
+)
+
+// mockProvider is an OIDC provider issuing the ID tokens the test asks for.
+type mockProvider struct {
+	*httptest.Server
+
+	// key signs the ID tokens.
+	key *rsa.PrivateKey
+
+	// challenge is the PKCE challenge of the login.
+	challenge string
+
+	// claims are the claims of the next ID token, on top of the standard claims.
+	claims map[string]any
+
+	// audience is the audience of the next ID token.
+	audience string
+
+	// expiry is when the next ID token expires.
+	expiry time.Time
+}
+
+func newMockProvider(t *testing.T) *mockProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	p := &mockProvider{key: key}
+
+	r := mux.NewRouter()
+	r.HandleFunc(discoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(providerMetadata{
+			Issuer:                p.URL,
+			AuthorizationEndpoint: p.URL + "/authorize",
+			TokenEndpoint:         p.URL + "/token",
+			JWKSURI:               p.URL + "/jwks",
+			EndSessionEndpoint:    p.URL + "/logout",
+		})
+	})
+	r.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: &key.PublicKey, KeyID: "test", Algorithm: string(jose.RS256), Use: "sig"},
+		}})
+	})
+	r.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		id, secret, _ := r.BasicAuth()
+		verifier := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+		if id != testClientID || secret != testClientSecret || r.PostFormValue("code") != testCode ||
+			base64.RawURLEncoding.EncodeToString(verifier[:]) != p.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]string{"id_token": p.idToken(t)})
+	})
+
+	p.Server = httptest.NewServer(r)
+	t.Cleanup(p.Close)
+
+	return p
+}
+
+// idToken signs an ID token with the claims of the provider.
+func (p *mockProvider) idToken(t *testing.T) string {
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: p.key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", "test"),
+	)
+	require.NoError(t, err)
+
+	token, err := jwt.Signed(signer).Claims(jwt.Claims{
+		Issuer:   p.URL,
+		Subject:  "1234",
+		Audience: jwt.Audience{p.audience},
+		Expiry:   jwt.NewNumericDate(p.expiry),
+		IssuedAt: jwt.NewNumericDate(time.Now()),
+	}).Claims(p.claims).Serialize()
+	require.NoError(t, err)
+
+	return token
+}
+
+func newTestOIDC(t *testing.T, p *mockProvider, allowedGroups ...string) *OIDC {
+	cfg := &OIDCConfig{
+		Issuer:        p.URL,
+		ClientID:      testClientID,
+		ClientSecret:  testClientSecret,
+		RedirectURL:   "http://reports.example.com/callback",
+		Scopes:        []string{"openid", "email"},
+		AllowedGroups: allowedGroups,
+		SessionKey:    "0123456789abcdef0123456789abcdef",
+		SessionTTL:    time.Hour,
+	}
+	cfg.Claims.Username = "email"
+	cfg.Claims.Groups = "groups"
+
+	o, err := NewOIDC(context.Background(), cfg, p.Client())
+	require.NoError(t, err)
+
+	return o
+}
+
+func TestOIDC_Login(t *testing.T) {
+	tests := []struct {
+		name          string
+		allowedGroups []string
+		claims        map[string]any
+		audience      string
+		expiry        time.Duration
+		badState      bool
+		providerError string
+		wantStatus    int
+		wantUser      string
+		wantGroups    []string
+	}{
+		{
+			name:       "logged in",
+			claims:     map[string]any{"email": "dev@example.com", "groups": []string{"ops"}},
+			wantStatus: http.StatusFound,
+			wantUser:   "dev@example.com",
+			wantGroups: []string{"ops"},
+		},
+		{
+			name:          "allowed group",
+			allowedGroups: []string{"admins", "ops"},
+			claims:        map[string]any{"email": "dev@example.com", "groups": "ops"},
+			wantStatus:    http.StatusFound,
+			wantUser:      "dev@example.com",
+			wantGroups:    []string{"ops"},
+		},
+		{
+			name:          "group not allowed",
+			allowedGroups: []string{"admins"},
+			claims:        map[string]any{"email": "dev@example.com", "groups": []string{"ops"}},
+			wantStatus:    http.StatusForbidden,
+		},
+		{
+			name:       "missing username claim",
+			claims:     map[string]any{"name": "Dev"},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "wrong audience",
+			claims:     map[string]any{"email": "dev@example.com"},
+			audience:   "another-client",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "expired token",
+			claims:     map[string]any{"email": "dev@example.com"},
+			expiry:     -time.Hour,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "wrong nonce",
+			claims:     map[string]any{"email": "dev@example.com", "nonce": "replayed"},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "wrong state",
+			claims:     map[string]any{"email": "dev@example.com"},
+			badState:   true,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:          "provider error",
+			providerError: "access_denied",
+			wantStatus:    http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newMockProvider(t)
+			o := newTestOIDC(t, p, tt.allowedGroups...)
+
+			r := mux.NewRouter()
+			o.Register(r)
+
+			// The login redirects to the provider.
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, LoginPath+"?return=/?host=web-1", nil))
+			require.Equal(t, http.StatusFound, w.Code)
+
+			loc, err := url.Parse(w.Header().Get("Location"))
+			require.NoError(t, err)
+			require.Equal(t, p.URL+"/authorize", loc.Scheme+"://"+loc.Host+loc.Path)
+			require.Equal(t, "S256", loc.Query().Get("code_challenge_method"))
+			require.Equal(t, "openid email", loc.Query().Get("scope"))
+
+			// The provider authenticates the user and redirects back.
+			p.challenge = loc.Query().Get("code_challenge")
+			p.audience = testClientID
+			if tt.audience != "" {
+				p.audience = tt.audience
+			}
+			p.expiry = time.Now().Add(time.Hour)
+			if tt.expiry != 0 {
+				p.expiry = time.Now().Add(tt.expiry)
+			}
+			p.claims = map[string]any{"nonce": loc.Query().Get("nonce")}
+			for k, v := range tt.claims {
+				p.claims[k] = v
+			}
+
+			q := url.Values{"code": {testCode}, "state": {loc.Query().Get("state")}}
+			if tt.badState {
+				q.Set("state", "forged")
+			}
+			if tt.providerError != "" {
+				q = url.Values{"error": {tt.providerError}, "state": {loc.Query().Get("state")}}
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/callback?"+q.Encode(), nil)
+			for _, c := range w.Result().Cookies() {
+				req.AddCookie(c)
+			}
+
+			w = httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			require.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			if tt.wantStatus != http.StatusFound {
+				return
+			}
+			require.Equal(t, "/?host=web-1", w.Header().Get("Location"))
+
+			// The session cookie logs the user in.
+			req = httptest.NewRequest(http.MethodGet, "/", nil)
+			for _, c := range w.Result().Cookies() {
+				if c.Name == SessionCookieName {
+					req.AddCookie(c)
+				}
+			}
+			session, err := o.sessions.Get(req)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantUser, session.User)
+			require.Equal(t, tt.wantGroups, session.Groups)
+			require.Equal(t, "1234", session.Subject)
+		})
+	}
+}
+
+func TestOIDC_Require(t *testing.T) {
+	p := newMockProvider(t)
+	o := newTestOIDC(t, p)
+
+	var got *Session
+	h := o.Middleware()(o.Require(func(w http.ResponseWriter, r *http.Request) {
+		got = SessionFromContext(r.Context())
+	}))
+
+	// Without a session the page redirects to the login.
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?host=web-1", nil))
+	require.Equal(t, http.StatusFound, w.Code)
+	require.Equal(t, LoginPath+"?return=%2F%3Fhost%3Dweb-1", w.Header().Get("Location"))
+
+	// The htmx requests cannot follow the redirect.
+	req := httptest.NewRequest(http.MethodGet, "/api/reports", nil)
+	req.Header.Set(headerHTMXRequest, "true")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// A forged session is ignored.
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: SessionCookieName, Value: "eyJ1c2VyIjoiYWRtaW4ifQ.forged"})
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	require.Equal(t, http.StatusFound, w.Code)
+	require.Nil(t, got)
+
+	// A valid session is passed on.
+	w = httptest.NewRecorder()
+	require.NoError(t, o.sessions.Set(w, &Session{User: "dev@example.com"}))
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(w.Result().Cookies()[0])
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "dev@example.com", got.User)
+}
+
+func TestOIDC_Logout(t *testing.T) {
+	p := newMockProvider(t)
+	o := newTestOIDC(t, p)
+
+	r := mux.NewRouter()
+	o.Register(r)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, LogoutPath, nil))
+	require.Equal(t, http.StatusFound, w.Code)
+	require.Equal(t, p.URL+"/logout?client_id="+testClientID, w.Header().Get("Location"))
+
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	require.Equal(t, SessionCookieName, cookies[0].Name)
+	require.Negative(t, cookies[0].MaxAge)
+}
+
+func TestSafeReturnPath(t *testing.T) {
+	tests := map[string]string{
+		"/?host=web-1":          "/?host=web-1",
+		"":                      "/",
+		"https://evil.example":  "/",
+		"//evil.example":        "/",
+		"/\\evil.example":       "/",
+		"javascript:alert(1)":   "/",
+		"/api/reports?limit=15": "/api/reports?limit=15",
+	}
+
+	for path, want := range tests {
+		require.Equal(t, want, safeReturnPath(path), path)
+	}
+}
@@ -0,0 +1,167 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNoSession is returned when the request does not have a valid session.
+	ErrNoSession = errors.New("no session")
+)
+
+const (
+	// SessionCookieName is the name of the cookie holding the session of the web UI.
+	SessionCookieName = "puppet_reporter_session"
+
+	// minSessionKeyLength is the shortest key the cookies can be signed with.
+	minSessionKeyLength = 32
+)
+
+// sessionKey is the context key of the session of a request.
+type sessionKey struct{}
+
+// Session is the logged-in user of the web UI.
+type Session struct {
+	// Subject is the subject of the ID token the session was created from.
+	Subject string `json:"sub"`
+
+	// User is the name of the user, taken from the username claim.
+	User string `json:"user"`
+
+	// Groups are the groups of the user, taken from the groups claim.
+	Groups []string `json:"groups,omitempty"`
+
+	// ExpiresAt is when the session expires, in unix seconds.
+	ExpiresAt int64 `json:"exp"`
+}
+
+// WithSession returns the context with the session of the request.
+func WithSession(ctx context.Context, session *Session) context.Context {
+	return context.WithValue(ctx, sessionKey{}, session)
+}
+
+// SessionFromContext returns the session of the request, nil if the user is not logged in.
+func SessionFromContext(ctx context.Context) *Session {
+	session, _ := ctx.Value(sessionKey{}).(*Session)
+	return session
+}
+
+// Sessions stores the sessions in signed cookies, so the servers do not share any state.
+type Sessions struct {
+	// key is the key the cookies are signed with.
+	key []byte
+
+	// ttl is how long a session lasts.
+	ttl time.Duration
+
+	// secure is true if the cookies are only sent over HTTPS.
+	secure bool
+}
+
+// NewSessions creates the sessions signed with the key, which must be at least 32 bytes.
+func NewSessions(key []byte, ttl time.Duration, secure bool) (*Sessions, error) {
+	if len(key) < minSessionKeyLength {
+		return nil, fmt.Errorf("session key must be at least %d bytes", minSessionKeyLength)
+	}
+
+	return &Sessions{
+		key:    key,
+		ttl:    ttl,
+		secure: secure,
+	}, nil
+}
+
+// Get returns the session of the request, or ErrNoSession if it does not have a valid one.
+func (s *Sessions) Get(r *http.Request) (*Session, error) {
+	session := new(Session)
+	if err := s.read(r, SessionCookieName, session); err != nil {
+		return nil, err
+	} else if time.Now().Unix() >= session.ExpiresAt {
+		return nil, fmt.Errorf("%w: session has expired", ErrNoSession)
+	}
+	return session, nil
+}
+
+// Set sets the session cookie, the session expires after the TTL of the sessions.
+func (s *Sessions) Set(w http.ResponseWriter, session *Session) error {
+	session.ExpiresAt = time.Now().Add(s.ttl).Unix()
+	return s.write(w, SessionCookieName, session, s.ttl)
+}
+
+// Clear removes the session cookie.
+func (s *Sessions) Clear(w http.ResponseWriter) {
+	s.clear(w, SessionCookieName)
+}
+
+// write sets the cookie to the signed JSON of the value.
+func (s *Sessions) write(w http.ResponseWriter, name string, value any, ttl time.Duration) error {
+	bts, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("error encoding cookie: %w", err)
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(bts)
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    payload + "." + s.sign(name, payload),
+		Path:     "/",
+		MaxAge:   int(ttl.Seconds()),
+		Secure:   s.secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return nil
+}
+
+// read decodes the signed cookie into the value.
+func (s *Sessions) read(r *http.Request, name string, value any) error {
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return ErrNoSession
+	}
+
+	payload, sig, ok := strings.Cut(cookie.Value, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(s.sign(name, payload))) {
+		return fmt.Errorf("%w: invalid signature", ErrNoSession)
+	}
+
+	bts, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrNoSession, err)
+	} else if err := json.Unmarshal(bts, value); err != nil {
+		return fmt.Errorf("%w: %w", ErrNoSession, err)
+	}
+
+	return nil
+}
+
+// clear removes the cookie.
+func (s *Sessions) clear(w http.ResponseWriter, name string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		Secure:   s.secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// sign returns the signature of the payload of the cookie. The name is signed too, so the value of one cookie cannot
+// be used as another.
+func (s *Sessions) sign(name, payload string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(name + "." + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var (
+	// ErrUnknownScope is returned when a scope is not one of the known scopes.
+	ErrUnknownScope = errors.New("unknown scope")
+)
+
+const (
+	// TokenPrefix is the prefix of every API token, so leaked tokens are easy to recognise.
+	TokenPrefix = "prt_"
+
+	// tokenBytes is the number of random bytes in a token.
+	tokenBytes = 32
+
+	// bearerPrefix is the prefix of the Authorization header carrying a bearer token.
+	bearerPrefix = "Bearer "
+)
+
+// Scope is a permission granted to an API token.
+type Scope string
+
+const (
+	// ScopeReportsRead allows reading the reports and the queued reports.
+	ScopeReportsRead Scope = "reports:read"
+
+	// ScopeReportsWrite allows uploading reports.
+	ScopeReportsWrite Scope = "reports:write"
+
+	// ScopeAdmin allows everything, including reading the reports that failed to be ingested.
+	ScopeAdmin Scope = "admin"
+)
+
+// knownScopes are the scopes a token can be granted.
+var knownScopes = []Scope{
+	ScopeReportsRead,
+	ScopeReportsWrite,
+	ScopeAdmin,
+}
+
+// Scopes are the permissions granted to an API token.
+type Scopes []Scope
+
+// ParseScopes parses the scopes, separated by commas or spaces.
+func ParseScopes(s string) (Scopes, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+
+	scopes := make(Scopes, 0, len(fields))
+	for _, f := range fields {
+		scope := Scope(strings.ToLower(f))
+		if !slices.Contains(knownScopes, scope) {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownScope, f)
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	return scopes, nil
+}
+
+// Has returns true if the scope is granted. The admin scope grants every scope.
+func (s Scopes) Has(scope Scope) bool {
+	return slices.Contains(s, scope) || slices.Contains(s, ScopeAdmin)
+}
+
+// String returns the scopes separated by spaces, the way they are stored.
+func (s Scopes) String() string {
+	strs := make([]string, len(s))
+	for i, scope := range s {
+		strs[i] = string(scope)
+	}
+	return strings.Join(strs, " ")
+}
+
+// GenerateToken returns a new random token and its hash. Only the hash is stored, the token is shown once.
+func GenerateToken() (token, hash string, err error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("error generating token: %w", err)
+	}
+
+	token = TokenPrefix + hex.EncodeToString(b)
+	return token, HashToken(token), nil
+}
+
+// HashToken returns the hash a token is stored as. The tokens are random, so a fast hash is enough to keep them
+// secret if the database leaks.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// BearerToken returns the token of an Authorization header, false if the header does not carry a bearer token.
+func BearerToken(header string) (string, bool) {
+	if len(header) < len(bearerPrefix) || !strings.EqualFold(header[:len(bearerPrefix)], bearerPrefix) {
+		return "", false
+	}
+
+	token := strings.TrimSpace(header[len(bearerPrefix):])
+	return token, token != ""
+}
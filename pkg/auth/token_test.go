@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScopes(t *testing.T) {
+	tests := []struct {
+		name    string
+		scopes  string
+		want    Scopes
+		wantErr error
+	}{
+		{
+			name:   "comma separated",
+			scopes: "reports:read,reports:write",
+			want:   Scopes{ScopeReportsRead, ScopeReportsWrite},
+		},
+		{
+			name:   "space separated as stored",
+			scopes: "reports:read admin",
+			want:   Scopes{ScopeReportsRead, ScopeAdmin},
+		},
+		{
+			name:   "duplicates and case are ignored",
+			scopes: "Reports:Read, reports:read",
+			want:   Scopes{ScopeReportsRead},
+		},
+		{
+			name:   "empty",
+			scopes: "",
+			want:   Scopes{},
+		},
+		{
+			name:    "unknown scope",
+			scopes:  "reports:read,reports:delete",
+			wantErr: ErrUnknownScope,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseScopes(tt.scopes)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestScopes_Has(t *testing.T) {
+	require.True(t, Scopes{ScopeReportsRead}.Has(ScopeReportsRead))
+	require.False(t, Scopes{ScopeReportsRead}.Has(ScopeReportsWrite))
+	require.True(t, Scopes{ScopeAdmin}.Has(ScopeReportsWrite))
+	require.False(t, Scopes{}.Has(ScopeReportsRead))
+}
+
+func TestGenerateToken(t *testing.T) {
+	token, hash, err := GenerateToken()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(token, TokenPrefix))
+	require.Equal(t, HashToken(token), hash)
+	require.NotContains(t, hash, token)
+
+	other, _, err := GenerateToken()
+	require.NoError(t, err)
+	require.NotEqual(t, token, other)
+}
+
+func TestBearerToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+		wantOk bool
+	}{
+		{
+			name:   "bearer token",
+			header: "Bearer prt_abc",
+			want:   "prt_abc",
+			wantOk: true,
+		},
+		{
+			name:   "lower case scheme",
+			header: "bearer prt_abc",
+			want:   "prt_abc",
+			wantOk: true,
+		},
+		{
+			name:   "basic auth",
+			header: "Basic dXNlcjpwYXNz",
+		},
+		{
+			name:   "empty token",
+			header: "Bearer ",
+		},
+		{
+			name:   "no header",
+			header: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := BearerToken(tt.header)
+			require.Equal(t, tt.wantOk, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
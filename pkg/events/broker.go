@@ -0,0 +1,168 @@
+package events
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/jacobbrewer1/puppet-reporter/pkg/auth"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/utils"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	// DefaultBufferSize is how many reports a subscriber can fall behind by before the reports are dropped for it.
+	DefaultBufferSize = 64
+
+	appNameSuffix = "events"
+)
+
+var (
+	// subscribers is the number of subscribers of the reports
+	subscribers = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name:      "subscribers",
+			Namespace: utils.AppName(appNameSuffix),
+			Help:      "Number of subscribers to the newly ingested reports",
+		},
+	)
+
+	// droppedReports is a counter of the reports that were dropped for subscribers that fell behind
+	droppedReports = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name:      "dropped_reports_total",
+			Namespace: utils.AppName(appNameSuffix),
+			Help:      "Total number of reports dropped for subscribers that fell behind",
+		},
+	)
+)
+
+// Broker passes the newly ingested reports to the subscribers in the same process. Each subscriber has a bounded
+// buffer, a subscriber that falls behind misses reports rather than slowing the ingestion down.
+//
+// A nil Broker has no subscribers, so the reports published to it go nowhere.
+type Broker struct {
+	mut        sync.RWMutex
+	subs       map[*Subscription]struct{}
+	bufferSize int
+}
+
+// NewBroker returns a broker that buffers up to bufferSize reports for each subscriber.
+func NewBroker(bufferSize int) *Broker {
+	if bufferSize < 1 {
+		bufferSize = DefaultBufferSize
+	}
+
+	return &Broker{
+		subs:       make(map[*Subscription]struct{}),
+		bufferSize: bufferSize,
+	}
+}
+
+// Subscription receives the published reports that match its filter.
+type Subscription struct {
+	reports chan *models.Report
+	filter  *Filter
+	dropped atomic.Int64
+}
+
+// Reports returns the reports of the subscription, it is closed when the subscription is cancelled.
+func (s *Subscription) Reports() <-chan *models.Report {
+	return s.reports
+}
+
+// Dropped returns the number of reports dropped since the last call, as the subscriber had fallen behind.
+func (s *Subscription) Dropped() int64 {
+	return s.dropped.Swap(0)
+}
+
+// Subscribe returns a subscription to the reports that match the filter, every report if the filter is nil. It must
+// be cancelled with Unsubscribe once it is no longer read.
+func (b *Broker) Subscribe(filter *Filter) *Subscription {
+	sub := &Subscription{
+		reports: make(chan *models.Report, b.bufferSize),
+		filter:  filter,
+	}
+
+	b.mut.Lock()
+	b.subs[sub] = struct{}{}
+	b.mut.Unlock()
+
+	subscribers.Inc()
+
+	return sub
+}
+
+// Unsubscribe cancels the subscription and closes its reports.
+func (b *Broker) Unsubscribe(sub *Subscription) {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+
+	if _, ok := b.subs[sub]; !ok {
+		return
+	}
+
+	delete(b.subs, sub)
+	close(sub.reports)
+
+	subscribers.Dec()
+}
+
+// Publish passes the report to the subscribers whose filter it matches. It never blocks, the report is dropped for
+// the subscribers whose buffer is full.
+func (b *Broker) Publish(report *models.Report) {
+	if b == nil {
+		return
+	}
+
+	b.mut.RLock()
+	defer b.mut.RUnlock()
+
+	for sub := range b.subs {
+		if !sub.filter.Matches(report) {
+			continue
+		}
+
+		select {
+		case sub.reports <- report:
+		default:
+			sub.dropped.Add(1)
+			droppedReports.Inc()
+		}
+	}
+}
+
+// Filter is the reports a subscriber wants. The host and environment match any part of those of the report, as the
+// filters of the report lists do, and none of them are case-sensitive.
+type Filter struct {
+	Host        string
+	Environment string
+	State       string
+
+	// Access is the access of the subscriber, it only receives the reports of the environments it can read.
+	Access *auth.Access
+}
+
+// Matches returns true if the report matches the filter. A nil filter matches every report.
+func (f *Filter) Matches(report *models.Report) bool {
+	switch {
+	case f == nil:
+		return true
+	case f.Host != "" && !containsFold(report.Host, f.Host):
+		return false
+	case f.Environment != "" && !containsFold(report.Environment, f.Environment):
+		return false
+	case f.State != "" && !strings.EqualFold(string(report.State), f.State):
+		return false
+	case !f.Access.AllowsEnvironment(report.Environment):
+		return false
+	default:
+		return true
+	}
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
@@ -0,0 +1,109 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jacobbrewer1/puppet-reporter/pkg/auth"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter_Matches(t *testing.T) {
+	acl, err := auth.NewACL(map[string][]string{"ops": {"production"}}, map[string][]string{"alice": {"ops"}}, false)
+	require.NoError(t, err)
+
+	report := &models.Report{Host: "web-1.example.com", Environment: "PRODUCTION", State: models.ReportStateFailed}
+
+	tests := []struct {
+		name   string
+		filter *Filter
+		want   bool
+	}{
+		{name: "nil", filter: nil, want: true},
+		{name: "empty", filter: &Filter{}, want: true},
+		{name: "host", filter: &Filter{Host: "WEB-1"}, want: true},
+		{name: "other host", filter: &Filter{Host: "db-"}, want: false},
+		{name: "environment", filter: &Filter{Environment: "prod"}, want: true},
+		{name: "state", filter: &Filter{State: "failed"}, want: true},
+		{name: "other state", filter: &Filter{State: "changed"}, want: false},
+		{name: "allowed environment", filter: &Filter{Access: acl.Access("alice")}, want: true},
+		{name: "forbidden environment", filter: &Filter{Access: acl.Access("bob")}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.filter.Matches(report))
+		})
+	}
+}
+
+func TestBroker_Publish(t *testing.T) {
+	b := NewBroker(2)
+	sub := b.Subscribe(&Filter{Host: "web-"})
+
+	// A full buffer drops the reports rather than blocking the publisher.
+	for i := range 4 {
+		b.Publish(&models.Report{Id: i, Host: "web-1"})
+	}
+	b.Publish(&models.Report{Id: 5, Host: "db-1"})
+
+	require.Equal(t, 0, (<-sub.Reports()).Id)
+	require.Equal(t, 1, (<-sub.Reports()).Id)
+	require.Equal(t, int64(2), sub.Dropped())
+	require.Zero(t, sub.Dropped())
+
+	b.Unsubscribe(sub)
+	b.Unsubscribe(sub)
+	_, ok := <-sub.Reports()
+	require.False(t, ok)
+
+	// A nil broker has no subscribers.
+	var nilBroker *Broker
+	nilBroker.Publish(&models.Report{})
+}
+
+func TestBroker_Stream(t *testing.T) {
+	b := NewBroker(DefaultBufferSize)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w := &syncBuffer{}
+	done := make(chan error)
+	go func() {
+		done <- b.Stream(ctx, w, nil, func(report *models.Report) ([]byte, error) {
+			return []byte(report.Host + "\n" + report.Environment), nil
+		})
+	}()
+
+	// The report is only published once the stream has subscribed.
+	require.Eventually(t, func() bool { return w.String() != "" }, time.Second, time.Millisecond)
+	b.Publish(&models.Report{Id: 7, Host: "web-1", Environment: "PRODUCTION"})
+	require.Eventually(t, func() bool {
+		return w.String() == ": connected\n\nevent: report\nid: 7\ndata: web-1\ndata: PRODUCTION\n\n"
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+// syncBuffer is a buffer that is written by the stream while the test reads it.
+type syncBuffer struct {
+	mut sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+	return b.buf.String()
+}
@@ -0,0 +1,115 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+)
+
+const (
+	// ContentTypeEventStream is the content type of a stream of server-sent events.
+	ContentTypeEventStream = "text/event-stream"
+
+	// EventReport is the name of the events of the newly ingested reports.
+	EventReport = "report"
+
+	// EventDropped is the name of the events sent when reports were dropped as the client fell behind, the data is the
+	// number of reports that were dropped.
+	EventDropped = "dropped"
+
+	// keepAliveInterval is how often a comment is sent when there are no reports, so proxies do not close the
+	// connection.
+	keepAliveInterval = 30 * time.Second
+)
+
+// Stream subscribes to the reports that match the filter and writes them to w as server-sent events, until the
+// context is done. The data of each event is what data returns for the report.
+func (b *Broker) Stream(ctx context.Context, w io.Writer, filter *Filter, data func(*models.Report) ([]byte, error)) error {
+	sub := b.Subscribe(filter)
+	defer b.Unsubscribe(sub)
+
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+
+	// The comment is sent straight away, so the client knows the stream is open before the first report.
+	if err := writeComment(w, "connected"); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := writeComment(w, "keep-alive"); err != nil {
+				return err
+			}
+		case report, ok := <-sub.Reports():
+			if !ok {
+				return nil
+			}
+
+			if dropped := sub.Dropped(); dropped > 0 {
+				if err := writeEvent(w, EventDropped, "", []byte(strconv.FormatInt(dropped, 10))); err != nil {
+					return err
+				}
+			}
+
+			bts, err := data(report)
+			if err != nil {
+				return fmt.Errorf("error encoding report: %w", err)
+			}
+
+			if err := writeEvent(w, EventReport, strconv.Itoa(report.Id), bts); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// writeEvent writes a server-sent event, with each line of the data as a data field.
+func writeEvent(w io.Writer, name, id string, data []byte) error {
+	buf := new(bytes.Buffer)
+	buf.WriteString("event: " + name + "\n")
+	if id != "" {
+		buf.WriteString("id: " + id + "\n")
+	}
+	for _, line := range bytes.Split(bytes.TrimRight(data, "\n"), []byte("\n")) {
+		buf.WriteString("data: ")
+		buf.Write(bytes.TrimRight(line, "\r"))
+		buf.WriteString("\n")
+	}
+	buf.WriteString("\n")
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("error writing event: %w", err)
+	}
+	return flush(w)
+}
+
+// writeComment writes a comment, which the clients ignore.
+func writeComment(w io.Writer, comment string) error {
+	if _, err := io.WriteString(w, ": "+comment+"\n\n"); err != nil {
+		return fmt.Errorf("error writing comment: %w", err)
+	}
+	return flush(w)
+}
+
+// flush sends what has been written to the client, if w is a response that can be flushed.
+func flush(w io.Writer) error {
+	rw, ok := w.(http.ResponseWriter)
+	if !ok {
+		return nil
+	}
+
+	if err := http.NewResponseController(rw).Flush(); err != nil {
+		return fmt.Errorf("error flushing event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package flapping
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+)
+
+const (
+	// DefaultWindow is how many of the latest reports of each host are looked at.
+	DefaultWindow = 10
+
+	// MaxWindow is the most reports of each host that can be looked at.
+	MaxWindow = 100
+
+	// DefaultThreshold is the share of the runs a resource must change in, or a host must change state in, for it to
+	// be flapping.
+	DefaultThreshold = 0.5
+
+	// MinRuns is how many reports a host must have in the window before it is judged, as a couple of runs say
+	// nothing about whether it keeps changing.
+	MinRuns = 4
+)
+
+// ResourceChanges is how many of the latest reports of a host changed a resource.
+type ResourceChanges struct {
+	Host    string `db:"host"`
+	Type    string `db:"type"`
+	Name    string `db:"name"`
+	Changes int    `db:"changes"`
+}
+
+// Host is a host whose state alternates between its runs, such as a host that fails every other run.
+type Host struct {
+	Host        string
+	Environment string
+
+	// Runs is the number of reports of the host in the window.
+	Runs int
+
+	// Transitions is the number of runs that ended in a different state to the run before.
+	Transitions int
+
+	// Ratio is the share of the runs that changed state.
+	Ratio float64
+
+	// States are the states of the runs, the latest first.
+	States []string
+}
+
+// Resource is a resource that is changed by most of the runs of a host, as the resources that are not idempotent are.
+type Resource struct {
+	Host string
+	Type string
+	Name string
+
+	// Runs is the number of reports of the host in the window.
+	Runs int
+
+	// Changes is the number of the runs that changed the resource.
+	Changes int
+
+	// Ratio is the share of the runs that changed the resource.
+	Ratio float64
+}
+
+// Result is the flapping hosts and resources, the most flapping first.
+type Result struct {
+	Hosts     []*Host
+	Resources []*Resource
+}
+
+// Detect returns the hosts and resources that flap, from the latest reports of each host and the changes of the
+// resources in those reports. The reports of each host must be the latest first. Hosts with fewer than MinRuns
+// reports are not judged.
+func Detect(reports []*models.Report, changes []*ResourceChanges, threshold float64) *Result {
+	byHost := make(map[string][]*models.Report)
+	for _, report := range reports {
+		byHost[report.Host] = append(byHost[report.Host], report)
+	}
+
+	res := &Result{
+		Hosts:     make([]*Host, 0),
+		Resources: make([]*Resource, 0),
+	}
+
+	for host, runs := range byHost {
+		if len(runs) < MinRuns {
+			continue
+		}
+
+		states := make([]string, len(runs))
+		transitions := 0
+		for i, run := range runs {
+			states[i] = strings.ToLower(string(run.State))
+			if i > 0 && states[i] != states[i-1] {
+				transitions++
+			}
+		}
+
+		ratio := float64(transitions) / float64(len(runs)-1)
+		if ratio < threshold {
+			continue
+		}
+
+		res.Hosts = append(res.Hosts, &Host{
+			Host:        host,
+			Environment: runs[0].Environment,
+			Runs:        len(runs),
+			Transitions: transitions,
+			Ratio:       ratio,
+			States:      states,
+		})
+	}
+
+	for _, change := range changes {
+		runs := len(byHost[change.Host])
+		if runs < MinRuns {
+			continue
+		}
+
+		ratio := float64(change.Changes) / float64(runs)
+		if ratio < threshold {
+			continue
+		}
+
+		res.Resources = append(res.Resources, &Resource{
+			Host:    change.Host,
+			Type:    change.Type,
+			Name:    change.Name,
+			Runs:    runs,
+			Changes: change.Changes,
+			Ratio:   ratio,
+		})
+	}
+
+	slices.SortFunc(res.Hosts, func(a, b *Host) int {
+		return cmp.Or(cmp.Compare(b.Ratio, a.Ratio), strings.Compare(a.Host, b.Host))
+	})
+	slices.SortFunc(res.Resources, func(a, b *Resource) int {
+		return cmp.Or(
+			cmp.Compare(b.Ratio, a.Ratio),
+			strings.Compare(a.Host, b.Host),
+			strings.Compare(a.Type, b.Type),
+			strings.Compare(a.Name, b.Name),
+		)
+	})
+
+	return res
+}
@@ -0,0 +1,81 @@
+package flapping
+
+import (
+	"testing"
+
+	"github.com/jacobbrewer1/goschema/usql"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+	"github.com/stretchr/testify/require"
+)
+
+// testRuns returns the reports of the host with the states, the latest first.
+func testRuns(host string, states ...string) []*models.Report {
+	reports := make([]*models.Report, len(states))
+	for i, state := range states {
+		reports[i] = &models.Report{Host: host, Environment: "PRODUCTION", State: usql.Enum(state)}
+	}
+	return reports
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name          string
+		reports       []*models.Report
+		changes       []*ResourceChanges
+		wantHosts     []string
+		wantResources []string
+	}{
+		{
+			name:    "alternating state",
+			reports: testRuns("web-1", "failed", "changed", "failed", "changed", "failed"),
+			// A host that changes state every run flaps.
+			wantHosts:     []string{"web-1"},
+			wantResources: []string{},
+		},
+		{
+			name:          "steady state",
+			reports:       testRuns("web-1", "failed", "failed", "failed", "changed", "changed"),
+			wantHosts:     []string{},
+			wantResources: []string{},
+		},
+		{
+			name:    "too few runs",
+			reports: testRuns("web-1", "failed", "changed", "failed"),
+			changes: []*ResourceChanges{
+				{Host: "web-1", Type: "Exec", Name: "date", Changes: 3},
+			},
+			wantHosts:     []string{},
+			wantResources: []string{},
+		},
+		{
+			name:    "resources changed in most runs",
+			reports: testRuns("web-1", "changed", "changed", "changed", "changed"),
+			changes: []*ResourceChanges{
+				{Host: "web-1", Type: "File", Name: "/etc/motd", Changes: 1},
+				{Host: "web-1", Type: "Exec", Name: "date", Changes: 4},
+				{Host: "web-1", Type: "Service", Name: "nginx", Changes: 2},
+			},
+			// The state is the same every run, so only the resources flap.
+			wantHosts:     []string{},
+			wantResources: []string{"Exec[date]", "Service[nginx]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Detect(tt.reports, tt.changes, DefaultThreshold)
+
+			hosts := make([]string, 0, len(got.Hosts))
+			for _, h := range got.Hosts {
+				hosts = append(hosts, h.Host)
+			}
+			require.Equal(t, tt.wantHosts, hosts)
+
+			resources := make([]string, 0, len(got.Resources))
+			for _, r := range got.Resources {
+				resources = append(resources, r.Type+"["+r.Name+"]")
+			}
+			require.Equal(t, tt.wantResources, resources)
+		})
+	}
+}
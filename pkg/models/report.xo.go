@@ -22,18 +22,19 @@ const (
 
 // Report represents a row from 'report'.
 type Report struct {
-	Id            int       `db:"id,pk,autoinc"`
-	Hash          string    `db:"hash"`
-	Host          string    `db:"host"`
-	PuppetVersion float64   `db:"puppet_version"`
-	Environment   string    `db:"environment"`
-	State         usql.Enum `db:"state"`
-	ExecutedAt    time.Time `db:"executed_at"`
-	Runtime       int       `db:"runtime"`
-	Failed        int       `db:"failed"`
-	Changed       int       `db:"changed"`
-	Skipped       int       `db:"skipped"`
-	Total         int       `db:"total"`
+	Id                int       `db:"id,pk,autoinc"`
+	Hash              string    `db:"hash"`
+	Host              string    `db:"host"`
+	PuppetVersion     float64   `db:"puppet_version"`
+	Environment       string    `db:"environment"`
+	State             usql.Enum `db:"state"`
+	ExecutedAt        time.Time `db:"executed_at"`
+	Runtime           int       `db:"runtime"`
+	Failed            int       `db:"failed"`
+	Changed           int       `db:"changed"`
+	Skipped           int       `db:"skipped"`
+	Total             int       `db:"total"`
+	RuntimeRegression bool      `db:"runtime_regression"`
 }
 
 // Insert inserts the Report to the database.
@@ -42,13 +43,13 @@ func (m *Report) Insert(db DB) error {
 	defer t.ObserveDuration()
 
 	const sqlstr = "INSERT INTO report (" +
-		"`hash`, `host`, `puppet_version`, `environment`, `state`, `executed_at`, `runtime`, `failed`, `changed`, `skipped`, `total`" +
+		"`hash`, `host`, `puppet_version`, `environment`, `state`, `executed_at`, `runtime`, `failed`, `changed`, `skipped`, `total`, `runtime_regression`" +
 		") VALUES (" +
-		"?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?" +
+		"?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?" +
 		")"
 
-	DBLog(sqlstr, m.Hash, m.Host, m.PuppetVersion, m.Environment, m.State, m.ExecutedAt, m.Runtime, m.Failed, m.Changed, m.Skipped, m.Total)
-	res, err := db.Exec(sqlstr, m.Hash, m.Host, m.PuppetVersion, m.Environment, m.State, m.ExecutedAt, m.Runtime, m.Failed, m.Changed, m.Skipped, m.Total)
+	DBLog(sqlstr, m.Hash, m.Host, m.PuppetVersion, m.Environment, m.State, m.ExecutedAt, m.Runtime, m.Failed, m.Changed, m.Skipped, m.Total, m.RuntimeRegression)
+	res, err := db.Exec(sqlstr, m.Hash, m.Host, m.PuppetVersion, m.Environment, m.State, m.ExecutedAt, m.Runtime, m.Failed, m.Changed, m.Skipped, m.Total, m.RuntimeRegression)
 	if err != nil {
 		return err
 	}
@@ -110,11 +111,11 @@ func (m *Report) Update(db DB) error {
 	defer t.ObserveDuration()
 
 	const sqlstr = "UPDATE report " +
-		"SET `hash` = ?, `host` = ?, `puppet_version` = ?, `environment` = ?, `state` = ?, `executed_at` = ?, `runtime` = ?, `failed` = ?, `changed` = ?, `skipped` = ?, `total` = ? " +
+		"SET `hash` = ?, `host` = ?, `puppet_version` = ?, `environment` = ?, `state` = ?, `executed_at` = ?, `runtime` = ?, `failed` = ?, `changed` = ?, `skipped` = ?, `total` = ?, `runtime_regression` = ? " +
 		"WHERE `id` = ?"
 
-	DBLog(sqlstr, m.Hash, m.Host, m.PuppetVersion, m.Environment, m.State, m.ExecutedAt, m.Runtime, m.Failed, m.Changed, m.Skipped, m.Total, m.Id)
-	res, err := db.Exec(sqlstr, m.Hash, m.Host, m.PuppetVersion, m.Environment, m.State, m.ExecutedAt, m.Runtime, m.Failed, m.Changed, m.Skipped, m.Total, m.Id)
+	DBLog(sqlstr, m.Hash, m.Host, m.PuppetVersion, m.Environment, m.State, m.ExecutedAt, m.Runtime, m.Failed, m.Changed, m.Skipped, m.Total, m.RuntimeRegression, m.Id)
+	res, err := db.Exec(sqlstr, m.Hash, m.Host, m.PuppetVersion, m.Environment, m.State, m.ExecutedAt, m.Runtime, m.Failed, m.Changed, m.Skipped, m.Total, m.RuntimeRegression, m.Id)
 	if err != nil {
 		return err
 	}
@@ -136,14 +137,14 @@ func (m *Report) InsertWithUpdate(db DB) error {
 	defer t.ObserveDuration()
 
 	const sqlstr = "INSERT INTO report (" +
-		"`hash`, `host`, `puppet_version`, `environment`, `state`, `executed_at`, `runtime`, `failed`, `changed`, `skipped`, `total`" +
+		"`hash`, `host`, `puppet_version`, `environment`, `state`, `executed_at`, `runtime`, `failed`, `changed`, `skipped`, `total`, `runtime_regression`" +
 		") VALUES (" +
-		"?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?" +
+		"?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?" +
 		") ON DUPLICATE KEY UPDATE " +
-		"`hash` = VALUES(`hash`), `host` = VALUES(`host`), `puppet_version` = VALUES(`puppet_version`), `environment` = VALUES(`environment`), `state` = VALUES(`state`), `executed_at` = VALUES(`executed_at`), `runtime` = VALUES(`runtime`), `failed` = VALUES(`failed`), `changed` = VALUES(`changed`), `skipped` = VALUES(`skipped`), `total` = VALUES(`total`)"
+		"`hash` = VALUES(`hash`), `host` = VALUES(`host`), `puppet_version` = VALUES(`puppet_version`), `environment` = VALUES(`environment`), `state` = VALUES(`state`), `executed_at` = VALUES(`executed_at`), `runtime` = VALUES(`runtime`), `failed` = VALUES(`failed`), `changed` = VALUES(`changed`), `skipped` = VALUES(`skipped`), `total` = VALUES(`total`), `runtime_regression` = VALUES(`runtime_regression`)"
 
-	DBLog(sqlstr, m.Hash, m.Host, m.PuppetVersion, m.Environment, m.State, m.ExecutedAt, m.Runtime, m.Failed, m.Changed, m.Skipped, m.Total)
-	res, err := db.Exec(sqlstr, m.Hash, m.Host, m.PuppetVersion, m.Environment, m.State, m.ExecutedAt, m.Runtime, m.Failed, m.Changed, m.Skipped, m.Total)
+	DBLog(sqlstr, m.Hash, m.Host, m.PuppetVersion, m.Environment, m.State, m.ExecutedAt, m.Runtime, m.Failed, m.Changed, m.Skipped, m.Total, m.RuntimeRegression)
+	res, err := db.Exec(sqlstr, m.Hash, m.Host, m.PuppetVersion, m.Environment, m.State, m.ExecutedAt, m.Runtime, m.Failed, m.Changed, m.Skipped, m.Total, m.RuntimeRegression)
 	if err != nil {
 		return err
 	}
@@ -194,7 +195,7 @@ func ReportById(db DB, id int) (*Report, error) {
 	t := prometheus.NewTimer(DatabaseLatency.WithLabelValues("get_" + ReportTableName + "_by_id"))
 	defer t.ObserveDuration()
 
-	const sqlstr = "SELECT `id`, `hash`, `host`, `puppet_version`, `environment`, `state`, `executed_at`, `runtime`, `failed`, `changed`, `skipped`, `total` " +
+	const sqlstr = "SELECT `id`, `hash`, `host`, `puppet_version`, `environment`, `state`, `executed_at`, `runtime`, `failed`, `changed`, `skipped`, `total`, `runtime_regression` " +
 		"FROM report " +
 		"WHERE `id` = ?"
 
@@ -267,7 +268,7 @@ func ReportByHash(db DB, hash string) (*Report, error) {
 	t := prometheus.NewTimer(DatabaseLatency.WithLabelValues("get_" + ReportTableName + "_by_hash"))
 	defer t.ObserveDuration()
 
-	const sqlstr = "SELECT `id`, `hash`, `host`, `puppet_version`, `environment`, `state`, `executed_at`, `runtime`, `failed`, `changed`, `skipped`, `total` " +
+	const sqlstr = "SELECT `id`, `hash`, `host`, `puppet_version`, `environment`, `state`, `executed_at`, `runtime`, `failed`, `changed`, `skipped`, `total`, `runtime_regression` " +
 		"FROM report " +
 		"WHERE `hash` = ?"
 
@@ -289,7 +290,7 @@ func GetAllReports(db DB, filters ...any) ([]*Report, error) {
 
 	args := make([]any, 0)
 	builder := new(strings.Builder)
-	builder.WriteString("SELECT `t.id`, `t.hash`, `t.host`, `t.puppet_version`, `t.environment`, `t.state`, `t.executed_at`, `t.runtime`, `t.failed`, `t.changed`, `t.skipped`, `t.total`")
+	builder.WriteString("SELECT `t.id`, `t.hash`, `t.host`, `t.puppet_version`, `t.environment`, `t.state`, `t.executed_at`, `t.runtime`, `t.failed`, `t.changed`, `t.skipped`, `t.total`, `t.runtime_regression`")
 
 	if len(filters) > 0 {
 		for _, filter := range filters {
@@ -316,8 +316,8 @@ func GetAllResources(db DB, filters ...any) ([]*Resource, error) {
 
 // Valid values for the 'Status' enum column
 var (
-	ResourceStatusSkipped   = usql.NewEnum("SKIPPED")
-	ResourceStatusChanged   = usql.NewEnum("CHANGED")
-	ResourceStatusFailed    = usql.NewEnum("FAILED")
-	ResourceStatusUnchanged = usql.NewEnum("UNCHANGED")
+	ResourceStatusSkipped   = usql.NewEnum("skipped")
+	ResourceStatusChanged   = usql.NewEnum("changed")
+	ResourceStatusFailed    = usql.NewEnum("failed")
+	ResourceStatusUnchanged = usql.NewEnum("unchanged")
 )
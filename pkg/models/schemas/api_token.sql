@@ -0,0 +1,14 @@
+create table api_token
+(
+    id           int auto_increment,
+    name         varchar(255) not null,
+    token_hash   varchar(64)  not null,
+    scopes       varchar(255) not null,
+    created_at   datetime     not null,
+    expires_at   datetime     null,
+    last_used_at datetime     null,
+    revoked_at   datetime     null,
+    primary key (id),
+    constraint api_token_token_hash_unique
+        unique (token_hash)
+);
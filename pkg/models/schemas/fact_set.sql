@@ -0,0 +1,11 @@
+create table fact_set
+(
+    id          int auto_increment,
+    host        varchar(255) not null,
+    hash        varchar(64)  not null,
+    facts       longtext     not null,
+    changes     longtext     not null,
+    received_at datetime     not null,
+    primary key (id),
+    index fact_set_host_index (host)
+);
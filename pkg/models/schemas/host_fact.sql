@@ -0,0 +1,11 @@
+create table host_fact
+(
+    id    int auto_increment,
+    host  varchar(255) not null,
+    name  varchar(255) not null,
+    value text         not null,
+    primary key (id),
+    constraint host_fact_host_name_unique
+        unique (host, name),
+    index host_fact_name_index (name)
+);
@@ -0,0 +1,10 @@
+create table ingestion_failure
+(
+    id           int auto_increment,
+    stage        varchar(64) not null,
+    error        text        not null,
+    payload_hash text        not null,
+    payload_size int         not null,
+    received_at  datetime    not null,
+    primary key (id)
+);
@@ -0,0 +1,11 @@
+create table ingestion_failure_payload
+(
+    id                   int auto_increment,
+    ingestion_failure_id int      not null,
+    payload              longblob not null,
+    primary key (id),
+    constraint ingestion_failure_payload_ingestion_failure_id_unique
+        unique (ingestion_failure_id),
+    constraint ingestion_failure_payload_ingestion_failure_id_fk
+        foreign key (ingestion_failure_id) references ingestion_failure (id)
+);
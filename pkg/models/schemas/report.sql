@@ -1,19 +1,19 @@
-create table report
-(
-    id             int auto_increment,
-    hash           text           not null unique,
-    host           text           not null,
-    puppet_version decimal(10, 2) not null,
-    environment    text           not null,
-    state          enum ('changed', 'failed', 'unchanged') not null,
-    executed_at    datetime       not null,
-    runtime        int            not null,
-    failed         int            not null,
-    changed        int            not null,
-    skipped        int            not null,
-    total          int            not null,
-    primary key (id),
-    constraint report_hash_unique
-        unique (hash)
-);
-
+create table report
+(
+    id                 int auto_increment,
+    hash               text           not null unique,
+    host               text           not null,
+    puppet_version     decimal(10, 2) not null,
+    environment        text           not null,
+    state              enum ('changed', 'failed', 'unchanged') not null,
+    executed_at        datetime       not null,
+    runtime            int            not null,
+    failed             int            not null,
+    changed            int            not null,
+    skipped            int            not null,
+    total              int            not null,
+    runtime_regression boolean        not null default false,
+    primary key (id),
+    constraint report_hash_unique
+        unique (hash)
+);
@@ -2,7 +2,7 @@ create table resource
 (
     id        int auto_increment,
     report_id int  not null,
-    status    enum ('SKIPPED', 'CHANGED', 'FAILED', 'UNCHANGED') not null,
+    status    enum ('skipped', 'changed', 'failed', 'unchanged') not null,
     name      text not null,
     type      text not null,
     file      text not null,
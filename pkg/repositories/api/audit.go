@@ -0,0 +1,117 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jacobbrewer1/pagefilter"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api/filters"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/storage"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// ErrNoAuditEvents is returned when no audit events are found.
+	ErrNoAuditEvents = errors.New("no audit events found")
+)
+
+func (r *repository) SaveAuditEvent(event *models.AuditEvent) error {
+	t := prometheus.NewTimer(models.DatabaseLatency.WithLabelValues("save_audit_event"))
+	defer t.ObserveDuration()
+
+	if err := event.Insert(r.db); err != nil {
+		return fmt.Errorf("insert audit event: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repository) GetAuditEvents(paginationDetails *pagefilter.PaginatorDetails, filters *GetAuditEventsFilters) (*pagefilter.PaginatedResponse[models.AuditEvent], error) {
+	t := prometheus.NewTimer(models.DatabaseLatency.WithLabelValues("get_audit_events"))
+	defer t.ObserveDuration()
+
+	mf := r.getAuditEventsFilters(filters)
+	pg := pagefilter.NewPaginator(r.db, models.AuditEventTableName, "id", mf)
+
+	// The details are copied, as the first page changes them.
+	details := *paginationDetails
+	if err := pg.SetDetails(&details, "id", "occurred_at"); err != nil {
+		return nil, fmt.Errorf("set paginator details: %w", err)
+	}
+
+	pvt, err := pg.Pivot()
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrNoAuditEvents
+		default:
+			return nil, fmt.Errorf("set paginator details: %w", err)
+		}
+	}
+
+	storage.FirstPage(&details)
+
+	items := make([]*models.AuditEvent, 0)
+	err = pg.Retrieve(pvt, &items)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrNoAuditEvents
+		default:
+			return nil, fmt.Errorf("failed to retrieve: %w", err)
+		}
+	}
+
+	var total int64 = 0
+	err = pg.Counts(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get total: %w", err)
+	}
+
+	return &pagefilter.PaginatedResponse[models.AuditEvent]{
+		Items: items,
+		Total: total,
+	}, nil
+}
+
+func (r *repository) getAuditEventsFilters(f *GetAuditEventsFilters) *pagefilter.MultiFilter {
+	mf := pagefilter.NewMultiFilter()
+	if f == nil {
+		return mf
+	}
+
+	if f.Action != nil {
+		mf.Add(filters.NewAuditEventsAction(*f.Action))
+	}
+
+	if f.Outcome != nil {
+		mf.Add(filters.NewAuditEventsOutcome(*f.Outcome))
+	}
+
+	if f.Actor != nil {
+		mf.Add(filters.NewAuditEventsActor(*f.Actor))
+	}
+
+	if f.Target != nil {
+		mf.Add(filters.NewAuditEventsTarget(*f.Target))
+	}
+
+	if f.From != nil || f.To != nil {
+		from := time.Time{}
+		if f.From != nil {
+			from = *f.From
+		}
+
+		to := time.Time{}
+		if f.To != nil {
+			to = *f.To
+		}
+
+		mf.Add(filters.NewAuditEventsOccurredRange(from, to))
+	}
+
+	return mf
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"github.com/jacobbrewer1/pagefilter"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api/filters"
+)
+
+// Condition is a condition of a query language on the columns of a table, the repository turns it into the filters
+// of the database it is on.
+type Condition interface {
+	condition()
+}
+
+// AndCondition matches when every one of the conditions matches.
+type AndCondition []Condition
+
+// OrCondition matches when any of the conditions matches.
+type OrCondition []Condition
+
+// NotCondition matches when the condition does not.
+type NotCondition struct {
+	Condition Condition
+}
+
+// CompareCondition compares a column against a value.
+type CompareCondition struct {
+	// Column is written into the query as is, so it must never come from the user.
+	Column string
+	Op     filters.CompareOp
+	Value  any
+}
+
+// WildcardCondition matches a column against a value case-insensitively, where a * in the value matches any
+// characters.
+type WildcardCondition struct {
+	// Column is written into the query as is, so it must never come from the user.
+	Column string
+	Value  string
+}
+
+// MatchCondition matches a column against a regular expression.
+type MatchCondition struct {
+	// Column is written into the query as is, so it must never come from the user.
+	Column  string
+	Pattern string
+}
+
+func (AndCondition) condition()      {}
+func (OrCondition) condition()       {}
+func (NotCondition) condition()      {}
+func (CompareCondition) condition()  {}
+func (WildcardCondition) condition() {}
+func (MatchCondition) condition()    {}
+
+// conditionFilter returns the filter of the condition for the database of the repository.
+func (r *repository) conditionFilter(c Condition) pagefilter.Wherer {
+	switch c := c.(type) {
+	case AndCondition:
+		return filters.NewAnd(r.conditionFilters(c)...)
+	case OrCondition:
+		return filters.NewOr(r.conditionFilters(c)...)
+	case NotCondition:
+		return filters.NewNot(r.conditionFilter(c.Condition))
+	case CompareCondition:
+		return filters.NewCompare(c.Column, c.Op, c.Value)
+	case WildcardCondition:
+		return filters.NewWildcard(r.driver, c.Column, c.Value)
+	case MatchCondition:
+		return filters.NewRegexpMatch(r.driver, c.Column, c.Pattern)
+	default:
+		// Unknown conditions match nothing rather than everything.
+		return filters.NewOr()
+	}
+}
+
+func (r *repository) conditionFilters(cs []Condition) []pagefilter.Wherer {
+	ws := make([]pagefilter.Wherer, len(cs))
+	for i, c := range cs {
+		ws[i] = r.conditionFilter(c)
+	}
+	return ws
+}
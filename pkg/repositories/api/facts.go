@@ -0,0 +1,113 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jacobbrewer1/pagefilter"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api/filters"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/storage"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// ErrFactsNotFound is returned when a host has no facts.
+	ErrFactsNotFound = errors.New("facts not found")
+)
+
+func (r *repository) SaveFacts(set *models.FactSet, facts []*models.HostFact) error {
+	t := prometheus.NewTimer(models.DatabaseLatency.WithLabelValues("save_facts"))
+	defer t.ObserveDuration()
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	if err := set.Insert(tx); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("insert fact set: %w", err)
+	}
+
+	// The flattened facts are only kept for the latest set, they are what the reports are filtered by.
+	if _, err := tx.Exec("DELETE FROM host_fact WHERE host = ?", set.Host); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("delete host facts: %w", err)
+	}
+
+	if err := models.InsertManyHostFacts(tx, facts...); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("insert host facts: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (r *repository) GetLatestFactSet(host string) (*models.FactSet, error) {
+	t := prometheus.NewTimer(models.DatabaseLatency.WithLabelValues("get_latest_fact_set"))
+	defer t.ObserveDuration()
+
+	set := new(models.FactSet)
+	err := r.db.Get(set, "SELECT id, host, hash, facts, changes, received_at FROM fact_set WHERE host = ? ORDER BY id DESC LIMIT 1", host)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrFactsNotFound
+		default:
+			return nil, fmt.Errorf("get latest fact set: %w", err)
+		}
+	}
+
+	return set, nil
+}
+
+func (r *repository) GetFactHistory(paginationDetails *pagefilter.PaginatorDetails, host string) (*pagefilter.PaginatedResponse[models.FactSet], error) {
+	t := prometheus.NewTimer(models.DatabaseLatency.WithLabelValues("get_fact_history"))
+	defer t.ObserveDuration()
+
+	mf := pagefilter.NewMultiFilter()
+	mf.Add(filters.NewFactSetsHost(host))
+	pg := pagefilter.NewPaginator(r.db, models.FactSetTableName, "id", mf)
+
+	// The details are copied, as the first page changes them.
+	details := *paginationDetails
+	if err := pg.SetDetails(&details, "id", "received_at"); err != nil {
+		return nil, fmt.Errorf("set paginator details: %w", err)
+	}
+
+	pvt, err := pg.Pivot()
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrFactsNotFound
+		default:
+			return nil, fmt.Errorf("set paginator details: %w", err)
+		}
+	}
+
+	storage.FirstPage(&details)
+
+	items := make([]*models.FactSet, 0)
+	err = pg.Retrieve(pvt, &items)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrFactsNotFound
+		default:
+			return nil, fmt.Errorf("failed to retrieve: %w", err)
+		}
+	}
+
+	var total int64 = 0
+	err = pg.Counts(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get total: %w", err)
+	}
+
+	return &pagefilter.PaginatedResponse[models.FactSet]{
+		Items: items,
+		Total: total,
+	}, nil
+}
@@ -0,0 +1,20 @@
+package filters
+
+import (
+	"github.com/jacobbrewer1/pagefilter"
+)
+
+type and struct {
+	filters []pagefilter.Wherer
+}
+
+// NewAnd matches when every one of the filters matches, and always matches if there are none.
+func NewAnd(filters ...pagefilter.Wherer) pagefilter.Wherer {
+	return &and{
+		filters: filters,
+	}
+}
+
+func (a *and) Where() (string, []any) {
+	return group(a.filters, "AND", "1 = 1")
+}
@@ -0,0 +1,17 @@
+package filters
+
+import "github.com/jacobbrewer1/pagefilter"
+
+type auditEventsAction struct {
+	action string
+}
+
+func NewAuditEventsAction(action string) pagefilter.Wherer {
+	return &auditEventsAction{
+		action: action,
+	}
+}
+
+func (a *auditEventsAction) Where() (string, []any) {
+	return "t.action = ?", []any{a.action}
+}
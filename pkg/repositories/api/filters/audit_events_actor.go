@@ -0,0 +1,18 @@
+package filters
+
+import "github.com/jacobbrewer1/pagefilter"
+
+type auditEventsActor struct {
+	actor string
+}
+
+// NewAuditEventsActor filters the audit events by the actor, matching the IP address, the certificate or the token.
+func NewAuditEventsActor(actor string) pagefilter.Wherer {
+	return &auditEventsActor{
+		actor: actor,
+	}
+}
+
+func (a *auditEventsActor) Where() (string, []any) {
+	return "(t.actor_ip = ? OR t.actor_certificate = ? OR t.actor_token = ?)", []any{a.actor, a.actor, a.actor}
+}
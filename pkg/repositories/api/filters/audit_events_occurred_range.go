@@ -0,0 +1,44 @@
+package filters
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jacobbrewer1/pagefilter"
+)
+
+type auditEventsOccurredRange struct {
+	from time.Time
+	to   time.Time
+}
+
+func NewAuditEventsOccurredRange(from, to time.Time) pagefilter.Wherer {
+	return &auditEventsOccurredRange{
+		from: from,
+		to:   to,
+	}
+}
+
+func (a *auditEventsOccurredRange) Where() (string, []any) {
+	if !a.from.IsZero() && !a.to.IsZero() {
+		return "t.occurred_at BETWEEN ? AND ?", []any{a.from, a.to}
+	}
+
+	builder := new(strings.Builder)
+	args := make([]any, 0)
+
+	if !a.from.IsZero() {
+		builder.WriteString("t.occurred_at >= ?")
+		args = append(args, a.from)
+	}
+
+	if !a.to.IsZero() {
+		if builder.Len() > 0 {
+			builder.WriteString(" AND ")
+		}
+		builder.WriteString("t.occurred_at <= ?")
+		args = append(args, a.to)
+	}
+
+	return builder.String(), args
+}
@@ -0,0 +1,17 @@
+package filters
+
+import "github.com/jacobbrewer1/pagefilter"
+
+type auditEventsOutcome struct {
+	outcome string
+}
+
+func NewAuditEventsOutcome(outcome string) pagefilter.Wherer {
+	return &auditEventsOutcome{
+		outcome: outcome,
+	}
+}
+
+func (a *auditEventsOutcome) Where() (string, []any) {
+	return "t.outcome = ?", []any{a.outcome}
+}
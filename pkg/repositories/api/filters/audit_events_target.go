@@ -0,0 +1,17 @@
+package filters
+
+import "github.com/jacobbrewer1/pagefilter"
+
+type auditEventsTarget struct {
+	target string
+}
+
+func NewAuditEventsTarget(target string) pagefilter.Wherer {
+	return &auditEventsTarget{
+		target: target,
+	}
+}
+
+func (a *auditEventsTarget) Where() (string, []any) {
+	return "t.target = ?", []any{a.target}
+}
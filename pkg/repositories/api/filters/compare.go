@@ -0,0 +1,48 @@
+package filters
+
+import (
+	"github.com/jacobbrewer1/pagefilter"
+)
+
+// CompareOp is a comparison operator of a column against a value.
+type CompareOp string
+
+const (
+	CompareEqual          CompareOp = "="
+	CompareLess           CompareOp = "<"
+	CompareLessOrEqual    CompareOp = "<="
+	CompareGreater        CompareOp = ">"
+	CompareGreaterOrEqual CompareOp = ">="
+)
+
+// IsValid returns true if the operator is one of the known comparison operators.
+func (o CompareOp) IsValid() bool {
+	switch o {
+	case CompareEqual, CompareLess, CompareLessOrEqual, CompareGreater, CompareGreaterOrEqual:
+		return true
+	}
+	return false
+}
+
+type compare struct {
+	column string
+	op     CompareOp
+	value  any
+}
+
+// NewCompare compares the column against the value. The column is written into the query as is, so it must never
+// come from the user.
+func NewCompare(column string, op CompareOp, value any) pagefilter.Wherer {
+	return &compare{
+		column: column,
+		op:     op,
+		value:  value,
+	}
+}
+
+func (c *compare) Where() (string, []any) {
+	if !c.op.IsValid() {
+		return "1 = 0", nil
+	}
+	return c.column + " " + string(c.op) + " ?", []any{c.value}
+}
@@ -0,0 +1,17 @@
+package filters
+
+import "github.com/jacobbrewer1/pagefilter"
+
+type factSetsHost struct {
+	host string
+}
+
+func NewFactSetsHost(host string) pagefilter.Wherer {
+	return &factSetsHost{
+		host: host,
+	}
+}
+
+func (f *factSetsHost) Where() (string, []any) {
+	return "t.host = ?", []any{f.host}
+}
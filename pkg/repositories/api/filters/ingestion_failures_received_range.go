@@ -0,0 +1,44 @@
+package filters
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jacobbrewer1/pagefilter"
+)
+
+type ingestionFailuresReceivedRange struct {
+	from time.Time
+	to   time.Time
+}
+
+func NewIngestionFailuresReceivedRange(from, to time.Time) pagefilter.Wherer {
+	return &ingestionFailuresReceivedRange{
+		from: from,
+		to:   to,
+	}
+}
+
+func (i *ingestionFailuresReceivedRange) Where() (string, []any) {
+	if !i.from.IsZero() && !i.to.IsZero() {
+		return "t.received_at BETWEEN ? AND ?", []any{i.from, i.to}
+	}
+
+	builder := new(strings.Builder)
+	args := make([]any, 0)
+
+	if !i.from.IsZero() {
+		builder.WriteString("t.received_at >= ?")
+		args = append(args, i.from)
+	}
+
+	if !i.to.IsZero() {
+		if builder.Len() > 0 {
+			builder.WriteString(" AND ")
+		}
+		builder.WriteString("t.received_at <= ?")
+		args = append(args, i.to)
+	}
+
+	return builder.String(), args
+}
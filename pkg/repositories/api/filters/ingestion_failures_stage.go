@@ -0,0 +1,17 @@
+package filters
+
+import "github.com/jacobbrewer1/pagefilter"
+
+type ingestionFailuresStage struct {
+	stage string
+}
+
+func NewIngestionFailuresStage(stage string) pagefilter.Wherer {
+	return &ingestionFailuresStage{
+		stage: stage,
+	}
+}
+
+func (i *ingestionFailuresStage) Where() (string, []any) {
+	return "t.stage = ?", []any{i.stage}
+}
@@ -0,0 +1,23 @@
+package filters
+
+import (
+	"strings"
+
+	"github.com/jacobbrewer1/pagefilter"
+)
+
+type not struct {
+	filter pagefilter.Wherer
+}
+
+// NewNot matches when the filter does not.
+func NewNot(filter pagefilter.Wherer) pagefilter.Wherer {
+	return &not{
+		filter: filter,
+	}
+}
+
+func (n *not) Where() (string, []any) {
+	sqlStr, args := n.filter.Where()
+	return "NOT (" + strings.TrimSpace(sqlStr) + ")", args
+}
@@ -0,0 +1,40 @@
+package filters
+
+import (
+	"strings"
+
+	"github.com/jacobbrewer1/pagefilter"
+)
+
+type or struct {
+	filters []pagefilter.Wherer
+}
+
+// NewOr matches when any of the filters matches, and never matches if there are none.
+func NewOr(filters ...pagefilter.Wherer) pagefilter.Wherer {
+	return &or{
+		filters: filters,
+	}
+}
+
+func (o *or) Where() (string, []any) {
+	return group(o.filters, "OR", "1 = 0")
+}
+
+// group joins the conditions of the filters with the operator, each in parentheses so they keep their meaning. The
+// empty condition is used when there are no filters.
+func group(filters []pagefilter.Wherer, op, empty string) (string, []any) {
+	if len(filters) == 0 {
+		return empty, nil
+	}
+
+	conds := make([]string, len(filters))
+	args := make([]any, 0, len(filters))
+	for i, f := range filters {
+		sqlStr, fArgs := f.Where()
+		conds[i] = "(" + strings.TrimSpace(sqlStr) + ")"
+		args = append(args, fArgs...)
+	}
+
+	return "(" + strings.Join(conds, " "+op+" ") + ")", args
+}
@@ -0,0 +1,26 @@
+package filters
+
+import (
+	"github.com/jacobbrewer1/pagefilter"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/storage"
+)
+
+type regexpMatch struct {
+	driver  storage.Driver
+	column  string
+	pattern string
+}
+
+// NewRegexpMatch matches the column against the regular expression. The column is written into the query as is, so
+// it must never come from the user.
+func NewRegexpMatch(driver storage.Driver, column, pattern string) pagefilter.Wherer {
+	return &regexpMatch{
+		driver:  driver,
+		column:  column,
+		pattern: pattern,
+	}
+}
+
+func (r *regexpMatch) Where() (string, []any) {
+	return r.driver.Regexp(r.column), []any{r.pattern}
+}
@@ -0,0 +1,31 @@
+package filters
+
+import (
+	"strings"
+
+	"github.com/jacobbrewer1/pagefilter"
+)
+
+type reportsEnvironmentIn struct {
+	envs []string
+}
+
+// NewReportsEnvironmentIn restricts the reports to the environments, no reports match if there are none.
+func NewReportsEnvironmentIn(envs []string) pagefilter.Wherer {
+	return &reportsEnvironmentIn{
+		envs: envs,
+	}
+}
+
+func (r *reportsEnvironmentIn) Where() (string, []any) {
+	if len(r.envs) == 0 {
+		return "1 = 0", nil
+	}
+
+	args := make([]any, len(r.envs))
+	for i, env := range r.envs {
+		args[i] = strings.ToUpper(env)
+	}
+
+	return "UPPER(t.environment) IN (?" + strings.Repeat(", ?", len(r.envs)-1) + ")", args
+}
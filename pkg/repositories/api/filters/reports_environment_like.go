@@ -1,17 +1,22 @@
 package filters
 
-import "github.com/jacobbrewer1/pagefilter"
+import (
+	"github.com/jacobbrewer1/pagefilter"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/storage"
+)
 
 type reportsEnvironmentLike struct {
-	env string
+	driver storage.Driver
+	env    string
 }
 
-func NewReportsEnvironmentLike(env string) pagefilter.Wherer {
+func NewReportsEnvironmentLike(driver storage.Driver, env string) pagefilter.Wherer {
 	return &reportsEnvironmentLike{
-		env: env,
+		driver: driver,
+		env:    env,
 	}
 }
 
 func (r *reportsEnvironmentLike) Where() (string, []any) {
-	return "t.environment LIKE ?", []any{"%" + r.env + "%"}
+	return r.driver.Like("t.environment"), []any{"%" + r.env + "%"}
 }
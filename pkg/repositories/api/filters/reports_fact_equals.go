@@ -0,0 +1,20 @@
+package filters
+
+import "github.com/jacobbrewer1/pagefilter"
+
+type reportsFactEquals struct {
+	name  string
+	value string
+}
+
+// NewReportsFactEquals restricts the reports to the hosts whose latest facts have the fact with the value.
+func NewReportsFactEquals(name, value string) pagefilter.Wherer {
+	return &reportsFactEquals{
+		name:  name,
+		value: value,
+	}
+}
+
+func (r *reportsFactEquals) Where() (string, []any) {
+	return "EXISTS (SELECT 1 FROM host_fact f WHERE f.host = t.host AND f.name = ? AND f.value = ?)", []any{r.name, r.value}
+}
@@ -1,17 +1,22 @@
 package filters
 
-import "github.com/jacobbrewer1/pagefilter"
+import (
+	"github.com/jacobbrewer1/pagefilter"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/storage"
+)
 
 type reportsHostLike struct {
-	host string
+	driver storage.Driver
+	host   string
 }
 
-func NewReportsHostLike(host string) pagefilter.Wherer {
+func NewReportsHostLike(driver storage.Driver, host string) pagefilter.Wherer {
 	return &reportsHostLike{
-		host: host,
+		driver: driver,
+		host:   host,
 	}
 }
 
 func (r *reportsHostLike) Where() (string, []any) {
-	return "t.host LIKE ?", []any{"%" + r.host + "%"}
+	return r.driver.Like("t.host"), []any{"%" + r.host + "%"}
 }
@@ -0,0 +1,18 @@
+package filters
+
+import "github.com/jacobbrewer1/pagefilter"
+
+type reportsRuntimeRegression struct {
+	regression bool
+}
+
+// NewReportsRuntimeRegression restricts the reports to those whose runtime did, or did not, regress.
+func NewReportsRuntimeRegression(regression bool) pagefilter.Wherer {
+	return &reportsRuntimeRegression{
+		regression: regression,
+	}
+}
+
+func (r *reportsRuntimeRegression) Where() (string, []any) {
+	return "t.runtime_regression = ?", []any{r.regression}
+}
@@ -1,17 +1,22 @@
 package filters
 
-import "github.com/jacobbrewer1/pagefilter"
+import (
+	"github.com/jacobbrewer1/pagefilter"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/storage"
+)
 
 type reportsStateLike struct {
-	state string
+	driver storage.Driver
+	state  string
 }
 
-func NewReportsStateLike(state string) pagefilter.Wherer {
+func NewReportsStateLike(driver storage.Driver, state string) pagefilter.Wherer {
 	return &reportsStateLike{
-		state: state,
+		driver: driver,
+		state:  state,
 	}
 }
 
 func (r *reportsStateLike) Where() (string, []any) {
-	return "t.state LIKE ?", []any{"%" + r.state + "%"}
+	return r.driver.Like("t.state"), []any{"%" + r.state + "%"}
 }
@@ -0,0 +1,33 @@
+package filters
+
+import (
+	"strings"
+
+	"github.com/jacobbrewer1/pagefilter"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/storage"
+)
+
+// wildcardEscaper escapes the LIKE wildcards in a value with the escape character of wildcard, ! is used as a
+// backslash means different things to the string literals of the databases.
+var wildcardEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+type wildcard struct {
+	driver storage.Driver
+	column string
+	value  string
+}
+
+// NewWildcard matches the column against the value case-insensitively, where a * in the value matches any
+// characters. The column is written into the query as is, so it must never come from the user.
+func NewWildcard(driver storage.Driver, column, value string) pagefilter.Wherer {
+	return &wildcard{
+		driver: driver,
+		column: column,
+		value:  value,
+	}
+}
+
+func (w *wildcard) Where() (string, []any) {
+	pattern := strings.ReplaceAll(wildcardEscaper.Replace(w.value), "*", "%")
+	return w.driver.Like(w.column) + " ESCAPE '!'", []any{pattern}
+}
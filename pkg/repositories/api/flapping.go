@@ -0,0 +1,71 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/jacobbrewer1/puppet-reporter/pkg/flapping"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// recentReportsView numbers the reports of each host, the latest report of the host being run 1.
+const recentReportsView = `
+	SELECT id, hash, host, puppet_version, environment, state, executed_at, runtime, failed, changed, skipped, total,
+		runtime_regression, ROW_NUMBER() OVER (PARTITION BY host ORDER BY executed_at DESC, id DESC) AS run
+	FROM report`
+
+func (r *repository) GetRecentReports(window int, filters *GetReportsFilters) ([]*models.Report, error) {
+	t := prometheus.NewTimer(models.DatabaseLatency.WithLabelValues("get_recent_reports"))
+	defer t.ObserveDuration()
+
+	mf := r.getReportsFilters(filters)
+	joinSQL, joinArgs := mf.Join()
+	whereSQL, whereArgs := mf.Where()
+
+	sqlStr := `
+		SELECT t.id, t.hash, t.host, t.puppet_version, t.environment, t.state, t.executed_at, t.runtime, t.failed, t.changed, t.skipped, t.total, t.runtime_regression
+		FROM (` + recentReportsView + `) t
+		` + joinSQL + `
+		WHERE t.run <= ? ` + whereSQL + `
+		ORDER BY t.host, t.run
+	`
+
+	args := append(joinArgs, window)
+	args = append(args, whereArgs...)
+
+	reports := make([]*models.Report, 0)
+	if err := r.db.Select(&reports, sqlStr, args...); err != nil {
+		return nil, fmt.Errorf("get recent reports: %w", err)
+	}
+
+	return reports, nil
+}
+
+func (r *repository) GetResourceChanges(window int, filters *GetReportsFilters) ([]*flapping.ResourceChanges, error) {
+	t := prometheus.NewTimer(models.DatabaseLatency.WithLabelValues("get_resource_changes"))
+	defer t.ObserveDuration()
+
+	mf := r.getReportsFilters(filters)
+	joinSQL, joinArgs := mf.Join()
+	whereSQL, whereArgs := mf.Where()
+
+	sqlStr := `
+		SELECT t.host, res.type, res.name, COUNT(*) AS changes
+		FROM (` + recentReportsView + `) t
+		INNER JOIN resource res ON res.report_id = t.id
+		` + joinSQL + `
+		WHERE t.run <= ? AND res.status = ? ` + whereSQL + `
+		GROUP BY t.host, res.type, res.name
+		ORDER BY t.host, res.type, res.name
+	`
+
+	args := append(joinArgs, window, models.ResourceStatusChanged)
+	args = append(args, whereArgs...)
+
+	changes := make([]*flapping.ResourceChanges, 0)
+	if err := r.db.Select(&changes, sqlStr, args...); err != nil {
+		return nil, fmt.Errorf("get resource changes: %w", err)
+	}
+
+	return changes, nil
+}
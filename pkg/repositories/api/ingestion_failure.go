@@ -0,0 +1,187 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jacobbrewer1/pagefilter"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api/filters"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/storage"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// ErrNoIngestionFailures is returned when no ingestion failures are found.
+	ErrNoIngestionFailures = errors.New("no ingestion failures found")
+
+	// ErrIngestionFailureNotFound is returned when an ingestion failure is not found.
+	ErrIngestionFailureNotFound = errors.New("ingestion failure not found")
+)
+
+func (r *repository) SaveIngestionFailure(failure *models.IngestionFailure, payload []byte) error {
+	// The failure and its payload are saved together, so a failure is never listed without a payload to download.
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	if err := failure.Insert(tx); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("insert ingestion failure: %w", err)
+	}
+
+	p := &models.IngestionFailurePayload{
+		IngestionFailureId: failure.Id,
+		Payload:            payload,
+	}
+
+	if err := p.Insert(tx); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("insert ingestion failure payload: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (r *repository) DeleteIngestionFailures(before time.Time) (int64, error) {
+	t := prometheus.NewTimer(models.DatabaseLatency.WithLabelValues("delete_ingestion_failures"))
+	defer t.ObserveDuration()
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+
+	// The payloads reference the failures, so they are deleted first.
+	if _, err := tx.Exec(
+		"DELETE FROM ingestion_failure_payload WHERE ingestion_failure_id IN (SELECT id FROM ingestion_failure WHERE received_at < ?)",
+		before,
+	); err != nil {
+		_ = tx.Rollback()
+		return 0, fmt.Errorf("delete ingestion failure payloads: %w", err)
+	}
+
+	res, err := tx.Exec("DELETE FROM ingestion_failure WHERE received_at < ?", before)
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, fmt.Errorf("delete ingestion failures: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, fmt.Errorf("delete ingestion failures: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit deleted ingestion failures: %w", err)
+	}
+
+	return n, nil
+}
+
+func (r *repository) GetIngestionFailureByID(id int) (*models.IngestionFailure, error) {
+	failure, err := models.IngestionFailureById(r.db, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrIngestionFailureNotFound
+		default:
+			return nil, fmt.Errorf("get ingestion failure by id: %w", err)
+		}
+	}
+
+	return failure, nil
+}
+
+func (r *repository) GetIngestionFailurePayload(id int) ([]byte, error) {
+	p, err := models.IngestionFailurePayloadByIngestionFailureId(r.db, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrIngestionFailureNotFound
+		default:
+			return nil, fmt.Errorf("get ingestion failure payload: %w", err)
+		}
+	}
+
+	return p.Payload, nil
+}
+
+func (r *repository) GetIngestionFailures(paginationDetails *pagefilter.PaginatorDetails, filters *GetIngestionFailuresFilters) (*pagefilter.PaginatedResponse[models.IngestionFailure], error) {
+	t := prometheus.NewTimer(models.DatabaseLatency.WithLabelValues("get_ingestion_failures"))
+	defer t.ObserveDuration()
+
+	mf := r.getIngestionFailuresFilters(filters)
+	pg := pagefilter.NewPaginator(r.db, models.IngestionFailureTableName, "id", mf)
+
+	// The details are copied, as the first page changes them.
+	details := *paginationDetails
+	if err := pg.SetDetails(&details, "id", "received_at"); err != nil {
+		return nil, fmt.Errorf("set paginator details: %w", err)
+	}
+
+	pvt, err := pg.Pivot()
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrNoIngestionFailures
+		default:
+			return nil, fmt.Errorf("set paginator details: %w", err)
+		}
+	}
+
+	storage.FirstPage(&details)
+
+	items := make([]*models.IngestionFailure, 0)
+	err = pg.Retrieve(pvt, &items)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrNoIngestionFailures
+		default:
+			return nil, fmt.Errorf("failed to retrieve: %w", err)
+		}
+	}
+
+	var total int64 = 0
+	err = pg.Counts(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get total: %w", err)
+	}
+
+	return &pagefilter.PaginatedResponse[models.IngestionFailure]{
+		Items: items,
+		Total: total,
+	}, nil
+}
+
+func (r *repository) getIngestionFailuresFilters(f *GetIngestionFailuresFilters) *pagefilter.MultiFilter {
+	mf := pagefilter.NewMultiFilter()
+	if f == nil {
+		return mf
+	}
+
+	if f.Stage != nil {
+		mf.Add(filters.NewIngestionFailuresStage(*f.Stage))
+	}
+
+	if f.From != nil || f.To != nil {
+		from := time.Time{}
+		if f.From != nil {
+			from = *f.From
+		}
+
+		to := time.Time{}
+		if f.To != nil {
+			to = *f.To
+		}
+
+		mf.Add(filters.NewIngestionFailuresReceivedRange(from, to))
+	}
+
+	return mf
+}
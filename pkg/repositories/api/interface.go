@@ -1,7 +1,11 @@
 package api
 
 import (
+	"time"
+
+	"github.com/jacobbrewer1/goschema/usql"
 	"github.com/jacobbrewer1/pagefilter"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/flapping"
 	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
 )
 
@@ -12,6 +16,34 @@ type Repository interface {
 	// GetReportByHash gets a report from the database by hash
 	GetReportByHash(hash string) (*models.Report, error)
 
+	// StreamReports calls fn with each report matching the filters as it is read from the database, oldest first,
+	// stopping at the first error fn returns
+	StreamReports(filters *GetReportsFilters, fn func(*models.Report) error) error
+
+	// GetLatestReportByHost gets the latest report of a host from the database
+	GetLatestReportByHost(host string) (*models.Report, error)
+
+	// GetPreviousReport gets the report of the same host that was executed before the report from the database
+	GetPreviousReport(report *models.Report) (*models.Report, error)
+
+	// GetRecentRuntimes gets the runtimes of up to limit of the reports of the same host that were executed before the
+	// report from the database, the latest first
+	GetRecentRuntimes(report *models.Report, limit int) ([]int, error)
+
+	// GetLatestReports gets the latest report for each host from the database
+	GetLatestReports() ([]*models.Report, error)
+
+	// GetConsecutiveFailures gets the number of failed reports each host has had since its last successful report
+	GetConsecutiveFailures() (map[string]int, error)
+
+	// GetRecentReports gets up to window of the latest reports of each host matching the filters from the database,
+	// ordered by host and the latest report first
+	GetRecentReports(window int, filters *GetReportsFilters) ([]*models.Report, error)
+
+	// GetResourceChanges gets how many of up to window of the latest reports of each host matching the filters
+	// changed each resource from the database
+	GetResourceChanges(window int, filters *GetReportsFilters) ([]*flapping.ResourceChanges, error)
+
 	// SaveReport saves a report to the database
 	SaveReport(report *models.Report) error
 
@@ -24,6 +56,91 @@ type Repository interface {
 	// GetResourcesByReportID gets resources from the database by report ID
 	GetResourcesByReportID(reportID int) ([]*models.Resource, error)
 
+	// StreamResourcesByReportID calls fn with each resource of a report as it is read from the database, stopping at
+	// the first error fn returns
+	StreamResourcesByReportID(reportID int, fn func(*models.Resource) error) error
+
 	// GetLogsByReportID gets logs from the database by report ID
 	GetLogsByReportID(reportID int) ([]*models.LogMessage, error)
+
+	// SaveIngestionFailure saves a report that could not be ingested, and its payload, to the database
+	SaveIngestionFailure(failure *models.IngestionFailure, payload []byte) error
+
+	// DeleteIngestionFailures deletes the reports that could not be ingested, and their payloads, received before the
+	// given time
+	DeleteIngestionFailures(before time.Time) (int64, error)
+
+	// GetIngestionFailures gets ingestion failures from the database
+	GetIngestionFailures(paginationDetails *pagefilter.PaginatorDetails, filters *GetIngestionFailuresFilters) (*pagefilter.PaginatedResponse[models.IngestionFailure], error)
+
+	// GetIngestionFailureByID gets an ingestion failure from the database by ID
+	GetIngestionFailureByID(id int) (*models.IngestionFailure, error)
+
+	// GetIngestionFailurePayload gets the payload of an ingestion failure from the database by the failure ID
+	GetIngestionFailurePayload(id int) ([]byte, error)
+
+	// QueueReport adds a report to the ingestion queue
+	QueueReport(item *models.ReportQueue) error
+
+	// GetQueuedReport gets a report from the ingestion queue by ID
+	GetQueuedReport(id int) (*models.ReportQueue, error)
+
+	// ClaimQueuedReports marks up to limit pending reports as processing and returns them with their claim
+	ClaimQueuedReports(limit int) ([]*models.ReportQueue, error)
+
+	// StartQueuedReport marks a claimed report as started, so it is not stale while it waited for a worker
+	StartQueuedReport(id int, claim string) error
+
+	// UpdateQueuedReportStatus sets the status of a claimed report and releases the claim, removing the payload once it
+	// has left the queue
+	UpdateQueuedReportStatus(id int, claim string, status usql.Enum, errMsg string) error
+
+	// RequeueStaleReports returns reports that have been processing since before the given time to the queue, releasing
+	// their claims
+	RequeueStaleReports(before time.Time) (int64, error)
+
+	// GetQueueDepth gets the number of pending and processing reports in the ingestion queue
+	GetQueueDepth() (map[string]int, error)
+
+	// SaveAPIToken saves an API token to the database
+	SaveAPIToken(token *models.ApiToken) error
+
+	// GetAPITokenByHash gets an API token from the database by the hash of the token
+	GetAPITokenByHash(hash string) (*models.ApiToken, error)
+
+	// GetAPITokens gets all API tokens from the database, including the revoked and expired tokens
+	GetAPITokens() ([]*models.ApiToken, error)
+
+	// RevokeAPIToken marks an API token as revoked at the given time
+	RevokeAPIToken(id int, at time.Time) error
+
+	// TouchAPIToken records when an API token was last used
+	TouchAPIToken(id int, at time.Time) error
+
+	// SaveAuditEvent saves an audit event to the database
+	SaveAuditEvent(event *models.AuditEvent) error
+
+	// GetAuditEvents gets audit events from the database
+	GetAuditEvents(paginationDetails *pagefilter.PaginatorDetails, filters *GetAuditEventsFilters) (*pagefilter.PaginatedResponse[models.AuditEvent], error)
+
+	// SaveFacts saves a new set of facts of a host, replacing the latest facts the reports are filtered by
+	SaveFacts(set *models.FactSet, facts []*models.HostFact) error
+
+	// GetLatestFactSet gets the latest set of facts of a host from the database
+	GetLatestFactSet(host string) (*models.FactSet, error)
+
+	// GetFactHistory gets the sets of facts of a host from the database, one for each time the facts changed
+	GetFactHistory(paginationDetails *pagefilter.PaginatorDetails, host string) (*pagefilter.PaginatedResponse[models.FactSet], error)
+
+	// GetPDBNodes gets the nodes of the PuppetDB query from the database, the hosts with their latest report
+	GetPDBNodes(query *PDBQuery) ([]*PDBNode, error)
+
+	// GetPDBReports gets the reports of the PuppetDB query from the database
+	GetPDBReports(query *PDBQuery) ([]*PDBReport, error)
+
+	// GetPDBEvents gets the events of the PuppetDB query from the database, the resources of the reports that did not stay unchanged
+	GetPDBEvents(query *PDBQuery) ([]*PDBEvent, error)
+
+	// GetPDBResources gets the resources of the PuppetDB query from the database, the resources of the latest report of each host
+	GetPDBResources(query *PDBQuery) ([]*PDBResource, error)
 }
@@ -3,9 +3,12 @@
 package api
 
 import (
+	usql "github.com/jacobbrewer1/goschema/usql"
 	pagefilter "github.com/jacobbrewer1/pagefilter"
+	flapping "github.com/jacobbrewer1/puppet-reporter/pkg/flapping"
 	models "github.com/jacobbrewer1/puppet-reporter/pkg/models"
 	mock "github.com/stretchr/testify/mock"
+	time "time"
 )
 
 // MockRepository is an autogenerated mock type for the Repository type
@@ -13,29 +16,687 @@ type MockRepository struct {
 	mock.Mock
 }
 
+// ClaimQueuedReports provides a mock function with given fields: limit
+func (_m *MockRepository) ClaimQueuedReports(limit int) ([]*models.ReportQueue, error) {
+	ret := _m.Called(limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimQueuedReports")
+	}
+
+	var r0 []*models.ReportQueue
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) ([]*models.ReportQueue, error)); ok {
+		return rf(limit)
+	}
+	if rf, ok := ret.Get(0).(func(int) []*models.ReportQueue); ok {
+		r0 = rf(limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.ReportQueue)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteIngestionFailures provides a mock function with given fields: before
+func (_m *MockRepository) DeleteIngestionFailures(before time.Time) (int64, error) {
+	ret := _m.Called(before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteIngestionFailures")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) (int64, error)); ok {
+		return rf(before)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) int64); ok {
+		r0 = rf(before)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAPITokenByHash provides a mock function with given fields: hash
+func (_m *MockRepository) GetAPITokenByHash(hash string) (*models.ApiToken, error) {
+	ret := _m.Called(hash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAPITokenByHash")
+	}
+
+	var r0 *models.ApiToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*models.ApiToken, error)); ok {
+		return rf(hash)
+	}
+	if rf, ok := ret.Get(0).(func(string) *models.ApiToken); ok {
+		r0 = rf(hash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ApiToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAPITokens provides a mock function with no fields
+func (_m *MockRepository) GetAPITokens() ([]*models.ApiToken, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAPITokens")
+	}
+
+	var r0 []*models.ApiToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]*models.ApiToken, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []*models.ApiToken); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.ApiToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAuditEvents provides a mock function with given fields: paginationDetails, filters
+func (_m *MockRepository) GetAuditEvents(paginationDetails *pagefilter.PaginatorDetails, filters *GetAuditEventsFilters) (*pagefilter.PaginatedResponse[models.AuditEvent], error) {
+	ret := _m.Called(paginationDetails, filters)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAuditEvents")
+	}
+
+	var r0 *pagefilter.PaginatedResponse[models.AuditEvent]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*pagefilter.PaginatorDetails, *GetAuditEventsFilters) (*pagefilter.PaginatedResponse[models.AuditEvent], error)); ok {
+		return rf(paginationDetails, filters)
+	}
+	if rf, ok := ret.Get(0).(func(*pagefilter.PaginatorDetails, *GetAuditEventsFilters) *pagefilter.PaginatedResponse[models.AuditEvent]); ok {
+		r0 = rf(paginationDetails, filters)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pagefilter.PaginatedResponse[models.AuditEvent])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*pagefilter.PaginatorDetails, *GetAuditEventsFilters) error); ok {
+		r1 = rf(paginationDetails, filters)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetConsecutiveFailures provides a mock function with no fields
+func (_m *MockRepository) GetConsecutiveFailures() (map[string]int, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetConsecutiveFailures")
+	}
+
+	var r0 map[string]int
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (map[string]int, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() map[string]int); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFactHistory provides a mock function with given fields: paginationDetails, host
+func (_m *MockRepository) GetFactHistory(paginationDetails *pagefilter.PaginatorDetails, host string) (*pagefilter.PaginatedResponse[models.FactSet], error) {
+	ret := _m.Called(paginationDetails, host)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFactHistory")
+	}
+
+	var r0 *pagefilter.PaginatedResponse[models.FactSet]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*pagefilter.PaginatorDetails, string) (*pagefilter.PaginatedResponse[models.FactSet], error)); ok {
+		return rf(paginationDetails, host)
+	}
+	if rf, ok := ret.Get(0).(func(*pagefilter.PaginatorDetails, string) *pagefilter.PaginatedResponse[models.FactSet]); ok {
+		r0 = rf(paginationDetails, host)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pagefilter.PaginatedResponse[models.FactSet])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*pagefilter.PaginatorDetails, string) error); ok {
+		r1 = rf(paginationDetails, host)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetIngestionFailureByID provides a mock function with given fields: id
+func (_m *MockRepository) GetIngestionFailureByID(id int) (*models.IngestionFailure, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIngestionFailureByID")
+	}
+
+	var r0 *models.IngestionFailure
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) (*models.IngestionFailure, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(int) *models.IngestionFailure); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.IngestionFailure)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetIngestionFailurePayload provides a mock function with given fields: id
+func (_m *MockRepository) GetIngestionFailurePayload(id int) ([]byte, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIngestionFailurePayload")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) ([]byte, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(int) []byte); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetIngestionFailures provides a mock function with given fields: paginationDetails, filters
+func (_m *MockRepository) GetIngestionFailures(paginationDetails *pagefilter.PaginatorDetails, filters *GetIngestionFailuresFilters) (*pagefilter.PaginatedResponse[models.IngestionFailure], error) {
+	ret := _m.Called(paginationDetails, filters)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIngestionFailures")
+	}
+
+	var r0 *pagefilter.PaginatedResponse[models.IngestionFailure]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*pagefilter.PaginatorDetails, *GetIngestionFailuresFilters) (*pagefilter.PaginatedResponse[models.IngestionFailure], error)); ok {
+		return rf(paginationDetails, filters)
+	}
+	if rf, ok := ret.Get(0).(func(*pagefilter.PaginatorDetails, *GetIngestionFailuresFilters) *pagefilter.PaginatedResponse[models.IngestionFailure]); ok {
+		r0 = rf(paginationDetails, filters)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pagefilter.PaginatedResponse[models.IngestionFailure])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*pagefilter.PaginatorDetails, *GetIngestionFailuresFilters) error); ok {
+		r1 = rf(paginationDetails, filters)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLatestFactSet provides a mock function with given fields: host
+func (_m *MockRepository) GetLatestFactSet(host string) (*models.FactSet, error) {
+	ret := _m.Called(host)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLatestFactSet")
+	}
+
+	var r0 *models.FactSet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*models.FactSet, error)); ok {
+		return rf(host)
+	}
+	if rf, ok := ret.Get(0).(func(string) *models.FactSet); ok {
+		r0 = rf(host)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.FactSet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(host)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLatestReportByHost provides a mock function with given fields: host
+func (_m *MockRepository) GetLatestReportByHost(host string) (*models.Report, error) {
+	ret := _m.Called(host)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLatestReportByHost")
+	}
+
+	var r0 *models.Report
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*models.Report, error)); ok {
+		return rf(host)
+	}
+	if rf, ok := ret.Get(0).(func(string) *models.Report); ok {
+		r0 = rf(host)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Report)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(host)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLatestReports provides a mock function with no fields
+func (_m *MockRepository) GetLatestReports() ([]*models.Report, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLatestReports")
+	}
+
+	var r0 []*models.Report
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]*models.Report, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []*models.Report); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Report)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLogsByReportID provides a mock function with given fields: reportID
 func (_m *MockRepository) GetLogsByReportID(reportID int) ([]*models.LogMessage, error) {
 	ret := _m.Called(reportID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetLogsByReportID")
+		panic("no return value specified for GetLogsByReportID")
+	}
+
+	var r0 []*models.LogMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) ([]*models.LogMessage, error)); ok {
+		return rf(reportID)
+	}
+	if rf, ok := ret.Get(0).(func(int) []*models.LogMessage); ok {
+		r0 = rf(reportID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.LogMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(reportID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPDBEvents provides a mock function with given fields: query
+func (_m *MockRepository) GetPDBEvents(query *PDBQuery) ([]*PDBEvent, error) {
+	ret := _m.Called(query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPDBEvents")
+	}
+
+	var r0 []*PDBEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*PDBQuery) ([]*PDBEvent, error)); ok {
+		return rf(query)
+	}
+	if rf, ok := ret.Get(0).(func(*PDBQuery) []*PDBEvent); ok {
+		r0 = rf(query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*PDBEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*PDBQuery) error); ok {
+		r1 = rf(query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPDBNodes provides a mock function with given fields: query
+func (_m *MockRepository) GetPDBNodes(query *PDBQuery) ([]*PDBNode, error) {
+	ret := _m.Called(query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPDBNodes")
+	}
+
+	var r0 []*PDBNode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*PDBQuery) ([]*PDBNode, error)); ok {
+		return rf(query)
+	}
+	if rf, ok := ret.Get(0).(func(*PDBQuery) []*PDBNode); ok {
+		r0 = rf(query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*PDBNode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*PDBQuery) error); ok {
+		r1 = rf(query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPDBReports provides a mock function with given fields: query
+func (_m *MockRepository) GetPDBReports(query *PDBQuery) ([]*PDBReport, error) {
+	ret := _m.Called(query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPDBReports")
+	}
+
+	var r0 []*PDBReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*PDBQuery) ([]*PDBReport, error)); ok {
+		return rf(query)
+	}
+	if rf, ok := ret.Get(0).(func(*PDBQuery) []*PDBReport); ok {
+		r0 = rf(query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*PDBReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*PDBQuery) error); ok {
+		r1 = rf(query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPDBResources provides a mock function with given fields: query
+func (_m *MockRepository) GetPDBResources(query *PDBQuery) ([]*PDBResource, error) {
+	ret := _m.Called(query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPDBResources")
+	}
+
+	var r0 []*PDBResource
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*PDBQuery) ([]*PDBResource, error)); ok {
+		return rf(query)
+	}
+	if rf, ok := ret.Get(0).(func(*PDBQuery) []*PDBResource); ok {
+		r0 = rf(query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*PDBResource)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*PDBQuery) error); ok {
+		r1 = rf(query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPreviousReport provides a mock function with given fields: report
+func (_m *MockRepository) GetPreviousReport(report *models.Report) (*models.Report, error) {
+	ret := _m.Called(report)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPreviousReport")
 	}
 
-	var r0 []*models.LogMessage
+	var r0 *models.Report
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int) ([]*models.LogMessage, error)); ok {
-		return rf(reportID)
+	if rf, ok := ret.Get(0).(func(*models.Report) (*models.Report, error)); ok {
+		return rf(report)
 	}
-	if rf, ok := ret.Get(0).(func(int) []*models.LogMessage); ok {
-		r0 = rf(reportID)
+	if rf, ok := ret.Get(0).(func(*models.Report) *models.Report); ok {
+		r0 = rf(report)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.LogMessage)
+			r0 = ret.Get(0).(*models.Report)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*models.Report) error); ok {
+		r1 = rf(report)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetQueueDepth provides a mock function with no fields
+func (_m *MockRepository) GetQueueDepth() (map[string]int, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetQueueDepth")
+	}
+
+	var r0 map[string]int
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (map[string]int, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() map[string]int); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetQueuedReport provides a mock function with given fields: id
+func (_m *MockRepository) GetQueuedReport(id int) (*models.ReportQueue, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetQueuedReport")
+	}
+
+	var r0 *models.ReportQueue
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) (*models.ReportQueue, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(int) *models.ReportQueue); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ReportQueue)
 		}
 	}
 
 	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(reportID)
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRecentReports provides a mock function with given fields: window, filters
+func (_m *MockRepository) GetRecentReports(window int, filters *GetReportsFilters) ([]*models.Report, error) {
+	ret := _m.Called(window, filters)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRecentReports")
+	}
+
+	var r0 []*models.Report
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, *GetReportsFilters) ([]*models.Report, error)); ok {
+		return rf(window, filters)
+	}
+	if rf, ok := ret.Get(0).(func(int, *GetReportsFilters) []*models.Report); ok {
+		r0 = rf(window, filters)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Report)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, *GetReportsFilters) error); ok {
+		r1 = rf(window, filters)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRecentRuntimes provides a mock function with given fields: report, limit
+func (_m *MockRepository) GetRecentRuntimes(report *models.Report, limit int) ([]int, error) {
+	ret := _m.Called(report, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRecentRuntimes")
+	}
+
+	var r0 []int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*models.Report, int) ([]int, error)); ok {
+		return rf(report, limit)
+	}
+	if rf, ok := ret.Get(0).(func(*models.Report, int) []int); ok {
+		r0 = rf(report, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*models.Report, int) error); ok {
+		r1 = rf(report, limit)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -103,6 +764,36 @@ func (_m *MockRepository) GetReports(paginationDetails *pagefilter.PaginatorDeta
 	return r0, r1
 }
 
+// GetResourceChanges provides a mock function with given fields: window, filters
+func (_m *MockRepository) GetResourceChanges(window int, filters *GetReportsFilters) ([]*flapping.ResourceChanges, error) {
+	ret := _m.Called(window, filters)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetResourceChanges")
+	}
+
+	var r0 []*flapping.ResourceChanges
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, *GetReportsFilters) ([]*flapping.ResourceChanges, error)); ok {
+		return rf(window, filters)
+	}
+	if rf, ok := ret.Get(0).(func(int, *GetReportsFilters) []*flapping.ResourceChanges); ok {
+		r0 = rf(window, filters)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*flapping.ResourceChanges)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, *GetReportsFilters) error); ok {
+		r1 = rf(window, filters)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetResourcesByReportID provides a mock function with given fields: reportID
 func (_m *MockRepository) GetResourcesByReportID(reportID int) ([]*models.Resource, error) {
 	ret := _m.Called(reportID)
@@ -133,6 +824,142 @@ func (_m *MockRepository) GetResourcesByReportID(reportID int) ([]*models.Resour
 	return r0, r1
 }
 
+// QueueReport provides a mock function with given fields: item
+func (_m *MockRepository) QueueReport(item *models.ReportQueue) error {
+	ret := _m.Called(item)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueueReport")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.ReportQueue) error); ok {
+		r0 = rf(item)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RequeueStaleReports provides a mock function with given fields: before
+func (_m *MockRepository) RequeueStaleReports(before time.Time) (int64, error) {
+	ret := _m.Called(before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequeueStaleReports")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) (int64, error)); ok {
+		return rf(before)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) int64); ok {
+		r0 = rf(before)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeAPIToken provides a mock function with given fields: id, at
+func (_m *MockRepository) RevokeAPIToken(id int, at time.Time) error {
+	ret := _m.Called(id, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeAPIToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, time.Time) error); ok {
+		r0 = rf(id, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveAPIToken provides a mock function with given fields: token
+func (_m *MockRepository) SaveAPIToken(token *models.ApiToken) error {
+	ret := _m.Called(token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveAPIToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.ApiToken) error); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveAuditEvent provides a mock function with given fields: event
+func (_m *MockRepository) SaveAuditEvent(event *models.AuditEvent) error {
+	ret := _m.Called(event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveAuditEvent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.AuditEvent) error); ok {
+		r0 = rf(event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveFacts provides a mock function with given fields: set, facts
+func (_m *MockRepository) SaveFacts(set *models.FactSet, facts []*models.HostFact) error {
+	ret := _m.Called(set, facts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveFacts")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.FactSet, []*models.HostFact) error); ok {
+		r0 = rf(set, facts)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveIngestionFailure provides a mock function with given fields: failure, payload
+func (_m *MockRepository) SaveIngestionFailure(failure *models.IngestionFailure, payload []byte) error {
+	ret := _m.Called(failure, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveIngestionFailure")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.IngestionFailure, []byte) error); ok {
+		r0 = rf(failure, payload)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SaveLogs provides a mock function with given fields: logs
 func (_m *MockRepository) SaveLogs(logs []*models.LogMessage) error {
 	ret := _m.Called(logs)
@@ -187,6 +1014,96 @@ func (_m *MockRepository) SaveResources(resources []*models.Resource) error {
 	return r0
 }
 
+// StartQueuedReport provides a mock function with given fields: id, claim
+func (_m *MockRepository) StartQueuedReport(id int, claim string) error {
+	ret := _m.Called(id, claim)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartQueuedReport")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, string) error); ok {
+		r0 = rf(id, claim)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StreamReports provides a mock function with given fields: filters, fn
+func (_m *MockRepository) StreamReports(filters *GetReportsFilters, fn func(*models.Report) error) error {
+	ret := _m.Called(filters, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StreamReports")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*GetReportsFilters, func(*models.Report) error) error); ok {
+		r0 = rf(filters, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StreamResourcesByReportID provides a mock function with given fields: reportID, fn
+func (_m *MockRepository) StreamResourcesByReportID(reportID int, fn func(*models.Resource) error) error {
+	ret := _m.Called(reportID, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StreamResourcesByReportID")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, func(*models.Resource) error) error); ok {
+		r0 = rf(reportID, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TouchAPIToken provides a mock function with given fields: id, at
+func (_m *MockRepository) TouchAPIToken(id int, at time.Time) error {
+	ret := _m.Called(id, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TouchAPIToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, time.Time) error); ok {
+		r0 = rf(id, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateQueuedReportStatus provides a mock function with given fields: id, claim, status, errMsg
+func (_m *MockRepository) UpdateQueuedReportStatus(id int, claim string, status usql.Enum, errMsg string) error {
+	ret := _m.Called(id, claim, status, errMsg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateQueuedReportStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, string, usql.Enum, string) error); ok {
+		r0 = rf(id, claim, status, errMsg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewMockRepository creates a new instance of MockRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockRepository(t interface {
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jacobbrewer1/pagefilter"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api/filters"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The PuppetDB entities are views over the tables, their columns are named after the fields of the entities in
+// PuppetDB so the queries can filter and order by the fields as they are. Each view has an environment column for the
+// environment restrictions, whether or not PuppetDB has the field.
+const (
+	// latestReportCondition restricts r to the latest report of its host.
+	latestReportCondition = `r.id = (
+		SELECT r2.id FROM report r2 WHERE r2.host = r.host ORDER BY r2.executed_at DESC, r2.id DESC LIMIT 1
+	)`
+
+	pdbNodesView = `
+		SELECT r.host AS certname, r.environment, r.environment AS report_environment, r.hash AS latest_report_hash,
+			r.state AS latest_report_status, r.executed_at AS report_timestamp, f.received_at AS facts_timestamp
+		FROM report r
+		LEFT JOIN fact_set f ON f.id = (SELECT MAX(f2.id) FROM fact_set f2 WHERE f2.host = r.host)
+		WHERE ` + latestReportCondition
+
+	pdbReportsView = `
+		SELECT r.id, r.hash, r.host AS certname, r.environment, r.state AS status, r.puppet_version,
+			r.executed_at AS start_time, r.executed_at AS producer_timestamp, r.executed_at AS receive_time,
+			r.runtime, r.failed, r.changed, r.skipped, r.total
+		FROM report r`
+
+	// Unchanged resources have no events, and the statuses of the events are named differently to the resources.
+	pdbEventsView = `
+		SELECT res.id, r.host AS certname, r.hash AS report, r.environment,
+			CASE res.status WHEN 'changed' THEN 'success' WHEN 'failed' THEN 'failure' ELSE res.status END AS status,
+			r.executed_at AS timestamp, r.executed_at AS run_start_time, r.runtime,
+			res.type AS resource_type, res.name AS resource_title, res.file, res.line
+		FROM resource res
+		INNER JOIN report r ON r.id = res.report_id
+		WHERE res.status <> 'unchanged'`
+
+	// The resources of the latest report of each host stand in for the catalog.
+	pdbResourcesView = `
+		SELECT res.id, r.host AS certname, r.environment, res.type, res.name AS title, res.file, res.line
+		FROM resource res
+		INNER JOIN report r ON r.id = res.report_id
+		WHERE ` + latestReportCondition
+)
+
+// PDBNode is a node of the PuppetDB nodes entity.
+type PDBNode struct {
+	Certname           string     `db:"certname"`
+	Environment        string     `db:"environment"`
+	ReportEnvironment  string     `db:"report_environment"`
+	LatestReportHash   string     `db:"latest_report_hash"`
+	LatestReportStatus string     `db:"latest_report_status"`
+	ReportTimestamp    time.Time  `db:"report_timestamp"`
+	FactsTimestamp     *time.Time `db:"facts_timestamp"`
+}
+
+// PDBReport is a report of the PuppetDB reports entity.
+type PDBReport struct {
+	Id                int       `db:"id"`
+	Hash              string    `db:"hash"`
+	Certname          string    `db:"certname"`
+	Environment       string    `db:"environment"`
+	Status            string    `db:"status"`
+	PuppetVersion     float64   `db:"puppet_version"`
+	StartTime         time.Time `db:"start_time"`
+	ProducerTimestamp time.Time `db:"producer_timestamp"`
+	ReceiveTime       time.Time `db:"receive_time"`
+	Runtime           int       `db:"runtime"`
+	Failed            int       `db:"failed"`
+	Changed           int       `db:"changed"`
+	Skipped           int       `db:"skipped"`
+	Total             int       `db:"total"`
+}
+
+// PDBEvent is an event of the PuppetDB events entity.
+type PDBEvent struct {
+	Id            int       `db:"id"`
+	Certname      string    `db:"certname"`
+	Report        string    `db:"report"`
+	Environment   string    `db:"environment"`
+	Status        string    `db:"status"`
+	Timestamp     time.Time `db:"timestamp"`
+	RunStartTime  time.Time `db:"run_start_time"`
+	Runtime       int       `db:"runtime"`
+	ResourceType  string    `db:"resource_type"`
+	ResourceTitle string    `db:"resource_title"`
+	File          string    `db:"file"`
+	Line          int       `db:"line"`
+}
+
+// PDBResource is a resource of the PuppetDB resources entity.
+type PDBResource struct {
+	Id          int    `db:"id"`
+	Certname    string `db:"certname"`
+	Environment string `db:"environment"`
+	Type        string `db:"type"`
+	Title       string `db:"title"`
+	File        string `db:"file"`
+	Line        int    `db:"line"`
+}
+
+func (r *repository) GetPDBNodes(query *PDBQuery) ([]*PDBNode, error) {
+	t := prometheus.NewTimer(models.DatabaseLatency.WithLabelValues("get_pdb_nodes"))
+	defer t.ObserveDuration()
+
+	items := make([]*PDBNode, 0)
+	if err := r.queryPDB(&items, pdbNodesView, "certname", query); err != nil {
+		return nil, fmt.Errorf("query nodes: %w", err)
+	}
+
+	return items, nil
+}
+
+func (r *repository) GetPDBReports(query *PDBQuery) ([]*PDBReport, error) {
+	t := prometheus.NewTimer(models.DatabaseLatency.WithLabelValues("get_pdb_reports"))
+	defer t.ObserveDuration()
+
+	items := make([]*PDBReport, 0)
+	if err := r.queryPDB(&items, pdbReportsView, "id", query); err != nil {
+		return nil, fmt.Errorf("query reports: %w", err)
+	}
+
+	return items, nil
+}
+
+func (r *repository) GetPDBEvents(query *PDBQuery) ([]*PDBEvent, error) {
+	t := prometheus.NewTimer(models.DatabaseLatency.WithLabelValues("get_pdb_events"))
+	defer t.ObserveDuration()
+
+	items := make([]*PDBEvent, 0)
+	if err := r.queryPDB(&items, pdbEventsView, "id", query); err != nil {
+		return nil, fmt.Errorf("query events: %w", err)
+	}
+
+	return items, nil
+}
+
+func (r *repository) GetPDBResources(query *PDBQuery) ([]*PDBResource, error) {
+	t := prometheus.NewTimer(models.DatabaseLatency.WithLabelValues("get_pdb_resources"))
+	defer t.ObserveDuration()
+
+	items := make([]*PDBResource, 0)
+	if err := r.queryPDB(&items, pdbResourcesView, "id", query); err != nil {
+		return nil, fmt.Errorf("query resources: %w", err)
+	}
+
+	return items, nil
+}
+
+// queryPDB selects the rows of the view matching the query into dest. The rows are ordered by the key after the
+// ordering of the query, so the pages are stable.
+func (r *repository) queryPDB(dest any, view, key string, query *PDBQuery) error {
+	mf := pagefilter.NewMultiFilter()
+	if query.Condition != nil {
+		mf.Add(r.conditionFilter(query.Condition))
+	}
+	if query.Environments != nil {
+		mf.Add(filters.NewReportsEnvironmentIn(query.Environments))
+	}
+
+	whereSQL, args := mf.Where()
+
+	orderBy := make([]string, 0, len(query.OrderBy)+1)
+	for _, o := range query.OrderBy {
+		dir := "ASC"
+		if o.Descending {
+			dir = "DESC"
+		}
+		orderBy = append(orderBy, "t."+o.Field+" "+dir)
+	}
+	orderBy = append(orderBy, "t."+key+" ASC")
+
+	sqlStr := "SELECT t.* FROM (" + view + ") t WHERE 1 = 1 " + whereSQL + " ORDER BY " + strings.Join(orderBy, ", ")
+	if query.Limit > 0 {
+		sqlStr += " LIMIT ? OFFSET ?"
+		args = append(args, query.Limit, query.Offset)
+	} else if query.Offset > 0 {
+		// MySQL has no OFFSET without a LIMIT, the largest LIMIT every database takes stands in for none.
+		sqlStr += " LIMIT 9223372036854775807 OFFSET ?"
+		args = append(args, query.Offset)
+	}
+
+	return r.db.Select(dest, sqlStr, args...)
+}
@@ -0,0 +1,225 @@
+package api
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jacobbrewer1/goschema/usql"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/storage"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// ErrQueuedReportNotFound is returned when a queued report is not found.
+	ErrQueuedReportNotFound = errors.New("queued report not found")
+
+	// ErrQueuedReportNotClaimed is returned when a queued report is no longer held by the claim it was claimed with,
+	// as it was queued again after it was thought to be stale.
+	ErrQueuedReportNotClaimed = errors.New("queued report not claimed")
+)
+
+// claimBytes is the number of random bytes in the claim of a queued report.
+const claimBytes = 16
+
+func (r *repository) QueueReport(item *models.ReportQueue) error {
+	return item.Insert(r.db)
+}
+
+func (r *repository) GetQueuedReport(id int) (*models.ReportQueue, error) {
+	item, err := models.ReportQueueById(r.db, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrQueuedReportNotFound
+		default:
+			return nil, fmt.Errorf("get queued report by id: %w", err)
+		}
+	}
+
+	return item, nil
+}
+
+func (r *repository) ClaimQueuedReports(limit int) ([]*models.ReportQueue, error) {
+	t := prometheus.NewTimer(models.DatabaseLatency.WithLabelValues("claim_queued_reports"))
+	defer t.ObserveDuration()
+
+	// The reports are selected and claimed in one transaction. MySQL and PostgreSQL lock the selected rows and skip
+	// the rows other workers have locked, so each worker claims its own reports without waiting on the others. SQLite
+	// has a single writer, so the transaction alone keeps the workers apart.
+	sqlStr := "SELECT id FROM report_queue WHERE status = ? ORDER BY id LIMIT ?"
+	if r.driver != storage.DriverSQLite {
+		sqlStr += " FOR UPDATE SKIP LOCKED"
+	}
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+
+	ids := make([]int, 0)
+	if err := tx.Select(&ids, sqlStr, models.ReportQueueStatusPending, limit); err != nil {
+		_ = tx.Rollback()
+		return nil, fmt.Errorf("get pending reports: %w", err)
+	}
+
+	now := time.Now().UTC()
+	claimed := make([]*models.ReportQueue, 0, len(ids))
+	for _, id := range ids {
+		claim, err := newClaim()
+		if err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+
+		if _, err := tx.Exec(
+			"UPDATE report_queue SET status = ?, claim = ?, updated_at = ? WHERE id = ?",
+			models.ReportQueueStatusProcessing, claim, now, id,
+		); err != nil {
+			_ = tx.Rollback()
+			return nil, fmt.Errorf("claim queued report: %w", err)
+		}
+
+		item, err := models.ReportQueueById(tx, id)
+		if err != nil {
+			_ = tx.Rollback()
+			return nil, fmt.Errorf("get claimed report: %w", err)
+		}
+
+		claimed = append(claimed, item)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit claimed reports: %w", err)
+	}
+
+	return claimed, nil
+}
+
+func (r *repository) StartQueuedReport(id int, claim string) error {
+	t := prometheus.NewTimer(models.DatabaseLatency.WithLabelValues("start_queued_report"))
+	defer t.ObserveDuration()
+
+	res, err := r.db.Exec(
+		"UPDATE report_queue SET updated_at = ? WHERE id = ? AND claim = ? AND status = ?",
+		time.Now().UTC(), id, claim, models.ReportQueueStatusProcessing,
+	)
+	if err != nil {
+		return fmt.Errorf("start queued report: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("start queued report: %w", err)
+	} else if n > 0 {
+		return nil
+	}
+
+	// MySQL counts the rows changed rather than the rows found, and the time is stored to the second, so a report
+	// started in the second it was claimed in is not counted.
+	held := 0
+	if err := r.db.Get(
+		&held,
+		"SELECT COUNT(*) FROM report_queue WHERE id = ? AND claim = ? AND status = ?",
+		id, claim, models.ReportQueueStatusProcessing,
+	); err != nil {
+		return fmt.Errorf("start queued report: %w", err)
+	} else if held == 0 {
+		return ErrQueuedReportNotClaimed
+	}
+
+	return nil
+}
+
+func (r *repository) UpdateQueuedReportStatus(id int, claim string, status usql.Enum, errMsg string) error {
+	t := prometheus.NewTimer(models.DatabaseLatency.WithLabelValues("update_queued_report_status"))
+	defer t.ObserveDuration()
+
+	// The claim is released with the status, a report returned to the queue is claimed again by the next worker.
+	sqlStr := "UPDATE report_queue SET status = ?, error = ?, updated_at = ?, claim = '' WHERE id = ? AND claim = ?"
+
+	// The payload is no longer needed once the report has left the queue.
+	if status == models.ReportQueueStatusDone || status == models.ReportQueueStatusFailed {
+		sqlStr = "UPDATE report_queue SET status = ?, error = ?, updated_at = ?, claim = '', payload = '' WHERE id = ? AND claim = ?"
+	}
+
+	res, err := r.db.Exec(sqlStr, status, errMsg, time.Now().UTC(), id, claim)
+	if err != nil {
+		return fmt.Errorf("update queued report status: %w", err)
+	}
+
+	// The claim always changes, so the row is counted by every database.
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("update queued report status: %w", err)
+	} else if n == 0 {
+		return ErrQueuedReportNotClaimed
+	}
+
+	return nil
+}
+
+func (r *repository) RequeueStaleReports(before time.Time) (int64, error) {
+	t := prometheus.NewTimer(models.DatabaseLatency.WithLabelValues("requeue_stale_reports"))
+	defer t.ObserveDuration()
+
+	res, err := r.db.Exec(
+		"UPDATE report_queue SET status = ?, claim = '', updated_at = ? WHERE status = ? AND updated_at < ?",
+		models.ReportQueueStatusPending, time.Now().UTC(), models.ReportQueueStatusProcessing, before,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("requeue stale reports: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("requeue stale reports: %w", err)
+	}
+
+	return n, nil
+}
+
+func (r *repository) GetQueueDepth() (map[string]int, error) {
+	t := prometheus.NewTimer(models.DatabaseLatency.WithLabelValues("get_queue_depth"))
+	defer t.ObserveDuration()
+
+	sqlStr := `
+		SELECT status, COUNT(*) AS depth
+		FROM report_queue
+		WHERE status IN (?, ?)
+		GROUP BY status
+	`
+
+	rows := make([]struct {
+		Status string `db:"status"`
+		Depth  int    `db:"depth"`
+	}, 0)
+	if err := r.db.Select(&rows, sqlStr, models.ReportQueueStatusPending, models.ReportQueueStatusProcessing); err != nil {
+		return nil, fmt.Errorf("get queue depth: %w", err)
+	}
+
+	depth := map[string]int{
+		string(models.ReportQueueStatusPending):    0,
+		string(models.ReportQueueStatusProcessing): 0,
+	}
+	for _, row := range rows {
+		depth[row.Status] = row.Depth
+	}
+
+	return depth, nil
+}
+
+// newClaim returns a random claim of a queued report. Only the worker holding the claim can update the report, so a
+// worker that took too long cannot overwrite the result of the worker that processed the report again.
+func newClaim() (string, error) {
+	b := make([]byte, claimBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate claim: %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
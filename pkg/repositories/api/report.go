@@ -4,11 +4,14 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"time"
 
 	"github.com/jacobbrewer1/pagefilter"
 	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
 	"github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api/filters"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/storage"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -38,6 +41,143 @@ func (r *repository) GetReportByHash(hash string) (*models.Report, error) {
 	return rep, nil
 }
 
+func (r *repository) GetLatestReportByHost(host string) (*models.Report, error) {
+	t := prometheus.NewTimer(models.DatabaseLatency.WithLabelValues("get_latest_report_by_host"))
+	defer t.ObserveDuration()
+
+	rep := new(models.Report)
+	err := r.db.Get(rep, `
+		SELECT id, hash, host, puppet_version, environment, state, executed_at, runtime, failed, changed, skipped, total, runtime_regression
+		FROM report
+		WHERE host = ?
+		ORDER BY executed_at DESC, id DESC
+		LIMIT 1
+	`, host)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrReportNotFound
+		default:
+			return nil, fmt.Errorf("get latest report by host: %w", err)
+		}
+	}
+
+	return rep, nil
+}
+
+func (r *repository) GetPreviousReport(report *models.Report) (*models.Report, error) {
+	t := prometheus.NewTimer(models.DatabaseLatency.WithLabelValues("get_previous_report"))
+	defer t.ObserveDuration()
+
+	// Reports executed at the same time are ordered by when they were saved, as the latest report is.
+	rep := new(models.Report)
+	err := r.db.Get(rep, `
+		SELECT id, hash, host, puppet_version, environment, state, executed_at, runtime, failed, changed, skipped, total, runtime_regression
+		FROM report
+		WHERE host = ?
+		AND (executed_at < ? OR (executed_at = ? AND id < ?))
+		ORDER BY executed_at DESC, id DESC
+		LIMIT 1
+	`, report.Host, report.ExecutedAt, report.ExecutedAt, report.Id)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrReportNotFound
+		default:
+			return nil, fmt.Errorf("get previous report: %w", err)
+		}
+	}
+
+	return rep, nil
+}
+
+func (r *repository) GetRecentRuntimes(report *models.Report, limit int) ([]int, error) {
+	t := prometheus.NewTimer(models.DatabaseLatency.WithLabelValues("get_recent_runtimes"))
+	defer t.ObserveDuration()
+
+	runtimes := make([]int, 0, limit)
+	err := r.db.Select(&runtimes, `
+		SELECT runtime
+		FROM report
+		WHERE host = ?
+		AND (executed_at < ? OR (executed_at = ? AND id < ?))
+		ORDER BY executed_at DESC, id DESC
+		LIMIT ?
+	`, report.Host, report.ExecutedAt, report.ExecutedAt, report.Id, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get recent runtimes: %w", err)
+	}
+
+	return runtimes, nil
+}
+
+func (r *repository) GetLatestReports() ([]*models.Report, error) {
+	t := prometheus.NewTimer(models.DatabaseLatency.WithLabelValues("get_latest_reports"))
+	defer t.ObserveDuration()
+
+	sqlStr := `
+		SELECT r.id, r.hash, r.host, r.puppet_version, r.environment, r.state, r.executed_at, r.runtime, r.failed, r.changed, r.skipped, r.total, r.runtime_regression
+		FROM report r
+		INNER JOIN (
+			SELECT host, MAX(executed_at) AS executed_at
+			FROM report
+			GROUP BY host
+		) latest ON latest.host = r.host AND latest.executed_at = r.executed_at
+		ORDER BY r.host, r.id
+	`
+
+	reports := make([]*models.Report, 0)
+	if err := r.db.Select(&reports, sqlStr); err != nil {
+		return nil, fmt.Errorf("get latest reports: %w", err)
+	}
+
+	// Two reports for the same host can share an execution time, keep the last one saved.
+	latest := make([]*models.Report, 0, len(reports))
+	for _, rep := range reports {
+		if n := len(latest); n > 0 && latest[n-1].Host == rep.Host {
+			latest[n-1] = rep
+			continue
+		}
+		latest = append(latest, rep)
+	}
+
+	return latest, nil
+}
+
+func (r *repository) GetConsecutiveFailures() (map[string]int, error) {
+	t := prometheus.NewTimer(models.DatabaseLatency.WithLabelValues("get_consecutive_failures"))
+	defer t.ObserveDuration()
+
+	sqlStr := `
+		SELECT r.host, COUNT(*) AS failures
+		FROM report r
+		LEFT JOIN (
+			SELECT host, MAX(executed_at) AS executed_at
+			FROM report
+			WHERE state <> ?
+			GROUP BY host
+		) success ON success.host = r.host
+		WHERE r.state = ?
+		AND (success.executed_at IS NULL OR r.executed_at > success.executed_at)
+		GROUP BY r.host
+	`
+
+	rows := make([]struct {
+		Host     string `db:"host"`
+		Failures int    `db:"failures"`
+	}, 0)
+	if err := r.db.Select(&rows, sqlStr, models.ReportStateFailed, models.ReportStateFailed); err != nil {
+		return nil, fmt.Errorf("get consecutive failures: %w", err)
+	}
+
+	failures := make(map[string]int, len(rows))
+	for _, row := range rows {
+		failures[row.Host] = row.Failures
+	}
+
+	return failures, nil
+}
+
 func (r *repository) GetReports(paginationDetails *pagefilter.PaginatorDetails, filters *GetReportsFilters) (*pagefilter.PaginatedResponse[models.Report], error) {
 	t := prometheus.NewTimer(models.DatabaseLatency.WithLabelValues("get_reports"))
 	defer t.ObserveDuration()
@@ -45,7 +185,9 @@ func (r *repository) GetReports(paginationDetails *pagefilter.PaginatorDetails,
 	mf := r.getReportsFilters(filters)
 	pg := pagefilter.NewPaginator(r.db, "report", "id", mf)
 
-	if err := pg.SetDetails(paginationDetails, "id", "timestamp"); err != nil {
+	// The details are copied, as the first page changes them.
+	details := *paginationDetails
+	if err := pg.SetDetails(&details, "id", "timestamp"); err != nil {
 		return nil, fmt.Errorf("set paginator details: %w", err)
 	}
 
@@ -59,6 +201,8 @@ func (r *repository) GetReports(paginationDetails *pagefilter.PaginatorDetails,
 		}
 	}
 
+	storage.FirstPage(&details)
+
 	items := make([]*models.Report, 0)
 	err = pg.Retrieve(pvt, &items)
 	if err != nil {
@@ -82,6 +226,42 @@ func (r *repository) GetReports(paginationDetails *pagefilter.PaginatorDetails,
 	}, nil
 }
 
+func (r *repository) StreamReports(filters *GetReportsFilters, fn func(*models.Report) error) error {
+	t := prometheus.NewTimer(models.DatabaseLatency.WithLabelValues("stream_reports"))
+	defer t.ObserveDuration()
+
+	mf := r.getReportsFilters(filters)
+	joinSQL, joinArgs := mf.Join()
+	whereSQL, whereArgs := mf.Where()
+
+	sqlStr := `
+		SELECT t.id, t.hash, t.host, t.puppet_version, t.environment, t.state, t.executed_at, t.runtime, t.failed, t.changed, t.skipped, t.total, t.runtime_regression
+		FROM report t
+		` + joinSQL + `
+		WHERE 1 = 1 ` + whereSQL + `
+		ORDER BY t.executed_at, t.id
+	`
+
+	rows, err := r.db.Queryx(sqlStr, append(joinArgs, whereArgs...)...)
+	if err != nil {
+		return fmt.Errorf("stream reports: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		rep := new(models.Report)
+		if err := rows.StructScan(rep); err != nil {
+			return fmt.Errorf("scan report: %w", err)
+		}
+
+		if err := fn(rep); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
 func (r *repository) getReportsFilters(f *GetReportsFilters) *pagefilter.MultiFilter {
 	mf := pagefilter.NewMultiFilter()
 	if f == nil {
@@ -89,15 +269,31 @@ func (r *repository) getReportsFilters(f *GetReportsFilters) *pagefilter.MultiFi
 	}
 
 	if f.Environment != nil {
-		mf.Add(filters.NewReportsEnvironmentLike(*f.Environment))
+		mf.Add(filters.NewReportsEnvironmentLike(r.driver, *f.Environment))
+	}
+
+	if f.Environments != nil {
+		mf.Add(filters.NewReportsEnvironmentIn(f.Environments))
 	}
 
 	if f.Host != nil {
-		mf.Add(filters.NewReportsHostLike(*f.Host))
+		mf.Add(filters.NewReportsHostLike(r.driver, *f.Host))
 	}
 
 	if f.State != nil {
-		mf.Add(filters.NewReportsStateLike(*f.State))
+		mf.Add(filters.NewReportsStateLike(r.driver, *f.State))
+	}
+
+	if f.RuntimeRegression != nil {
+		mf.Add(filters.NewReportsRuntimeRegression(*f.RuntimeRegression))
+	}
+
+	if f.Query != nil {
+		mf.Add(r.conditionFilter(f.Query))
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(f.Facts)) {
+		mf.Add(filters.NewReportsFactEquals(name, f.Facts[name]))
 	}
 
 	if f.From != nil || f.To != nil {
@@ -3,18 +3,23 @@ package api
 import (
 	"time"
 
+	"github.com/jacobbrewer1/puppet-reporter/pkg/storage"
 	"github.com/jacobbrewer1/vaulty/repositories"
 )
 
 type repository struct {
 	// db is the database used by the repository.
 	db *repositories.Database
+
+	// driver is the driver of the database, the filters differ between the databases.
+	driver storage.Driver
 }
 
 // NewRepository creates a new repository.
 func NewRepository(db *repositories.Database) Repository {
 	return &repository{
-		db: db,
+		db:     db,
+		driver: storage.DriverOf(db),
 	}
 }
 
@@ -24,4 +29,61 @@ type GetReportsFilters struct {
 	State       *string
 	From        *time.Time
 	To          *time.Time
+
+	// RuntimeRegression restricts the reports to those whose runtime did, or did not, regress from the baseline of
+	// the host.
+	RuntimeRegression *bool
+
+	// Facts restricts the reports to the hosts whose latest facts have each of the values, keyed by the dotted name
+	// of the fact.
+	Facts map[string]string
+
+	// Environments restricts the reports to the environments the reader can read, nil if it can read every
+	// environment.
+	Environments []string
+
+	// Query is the condition of a search query on the columns of the reports, nil to match every report.
+	Query Condition
+}
+
+type GetIngestionFailuresFilters struct {
+	Stage *string
+	From  *time.Time
+	To    *time.Time
+}
+
+type GetAuditEventsFilters struct {
+	Action  *string
+	Outcome *string
+	Actor   *string
+	Target  *string
+	From    *time.Time
+	To      *time.Time
+}
+
+// PDBQuery is a query of one of the PuppetDB entities.
+type PDBQuery struct {
+	// Condition is the condition on the fields of the entity, nil to match every row. The columns of the views are
+	// named after the fields, with the t alias.
+	Condition Condition
+
+	// OrderBy is the ordering of the rows by the fields of the entity.
+	OrderBy []PDBOrder
+
+	// Limit is the largest number of rows returned, zero for no limit.
+	Limit int
+
+	// Offset is the number of rows skipped.
+	Offset int
+
+	// Environments restricts the rows to the environments the reader can read, nil if it can read every
+	// environment.
+	Environments []string
+}
+
+// PDBOrder orders the rows of a PuppetDB query by a field.
+type PDBOrder struct {
+	// Field is the field of the entity, it is written into the query as is so it must never come from the user.
+	Field      string
+	Descending bool
 }
@@ -0,0 +1,416 @@
+package api
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jacobbrewer1/goschema/usql"
+	"github.com/jacobbrewer1/pagefilter"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/flapping"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/storage/storagetest"
+	"github.com/jacobbrewer1/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// forEachDatabase runs the test against a repository on each database that can be tested.
+func forEachDatabase(t *testing.T, test func(t *testing.T, r *repository)) {
+	for driver, db := range storagetest.Databases(t) {
+		t.Run(string(driver), func(t *testing.T) {
+			test(t, NewRepository(db).(*repository))
+		})
+	}
+}
+
+// saveTestReport saves a report for the host executed at the given time.
+func saveTestReport(t *testing.T, r *repository, host string, state usql.Enum, executedAt time.Time) *models.Report {
+	t.Helper()
+
+	rep := &models.Report{
+		Hash:          utils.Sha256([]byte(fmt.Sprintf("%s-%s", host, executedAt))),
+		Host:          host,
+		PuppetVersion: 8.1,
+		Environment:   "production",
+		State:         state,
+		ExecutedAt:    executedAt,
+		Runtime:       30,
+		Total:         10,
+	}
+	require.NoError(t, r.SaveReport(rep))
+	require.NotZero(t, rep.Id)
+
+	return rep
+}
+
+func TestRepository_Reports(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, r *repository) {
+
+		base := time.Date(2024, 12, 24, 13, 0, 0, 0, time.UTC)
+
+		saveTestReport(t, r, "web-1", "changed", base)
+		saveTestReport(t, r, "web-1", "failed", base.Add(time.Hour))
+		latestWeb := saveTestReport(t, r, "web-1", "failed", base.Add(2*time.Hour))
+		saveTestReport(t, r, "db-1", "failed", base)
+		latestDB := saveTestReport(t, r, "db-1", "unchanged", base.Add(time.Hour))
+
+		got, err := r.GetReportByHash(latestWeb.Hash)
+		require.NoError(t, err)
+		require.Equal(t, latestWeb.Id, got.Id)
+		require.True(t, latestWeb.ExecutedAt.Equal(got.ExecutedAt))
+
+		_, err = r.GetReportByHash("unknown")
+		require.ErrorIs(t, err, ErrReportNotFound)
+
+		latest, err := r.GetLatestReports()
+		require.NoError(t, err)
+		require.Len(t, latest, 2)
+		require.Equal(t, latestDB.Id, latest[0].Id)
+		require.Equal(t, latestWeb.Id, latest[1].Id)
+
+		prev, err := r.GetPreviousReport(latestWeb)
+		require.NoError(t, err)
+		require.True(t, base.Add(time.Hour).Equal(prev.ExecutedAt))
+
+		first, err := r.GetPreviousReport(prev)
+		require.NoError(t, err)
+		require.Equal(t, "changed", string(first.State))
+
+		_, err = r.GetPreviousReport(first)
+		require.ErrorIs(t, err, ErrReportNotFound)
+
+		runtimes, err := r.GetRecentRuntimes(latestWeb, 5)
+		require.NoError(t, err)
+		require.Equal(t, []int{30, 30}, runtimes)
+
+		failures, err := r.GetConsecutiveFailures()
+		require.NoError(t, err)
+		require.Equal(t, map[string]int{"web-1": 2}, failures)
+
+		host := "web"
+		state := "failed"
+		from := base.Add(30 * time.Minute)
+		reports, err := r.GetReports(pagefilter.GetPaginatorDetails(nil, nil, nil, nil, nil), &GetReportsFilters{
+			Host:  &host,
+			State: &state,
+			From:  &from,
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, 2, reports.Total)
+		require.Len(t, reports.Items, 2)
+
+		streamed := make([]string, 0)
+		require.NoError(t, r.StreamReports(&GetReportsFilters{Host: &host}, func(rep *models.Report) error {
+			streamed = append(streamed, string(rep.State))
+			return nil
+		}))
+		require.Equal(t, []string{"changed", "failed", "failed"}, streamed)
+
+		// The wildcards of LIKE in a search value match themselves, only * matches any characters.
+		reports, err = r.GetReports(pagefilter.GetPaginatorDetails(nil, nil, nil, nil, nil), &GetReportsFilters{
+			Query: AndCondition{
+				WildcardCondition{Column: "t.host", Value: "WEB-*"},
+				NotCondition{Condition: WildcardCondition{Column: "t.state", Value: "chan_ed"}},
+			},
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, 3, reports.Total)
+	})
+}
+
+func TestRepository_ReportDetails(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, r *repository) {
+
+		rep := saveTestReport(t, r, "web-1", "changed", time.Date(2024, 12, 24, 13, 0, 0, 0, time.UTC))
+
+		require.NoError(t, r.SaveResources([]*models.Resource{
+			{ReportId: rep.Id, Status: "changed", Name: "nginx", Type: "Package", File: "init.pp", Line: 1},
+			{ReportId: rep.Id, Status: "skipped", Name: "nginx", Type: "Service", File: "init.pp", Line: 5},
+		}))
+		require.NoError(t, r.SaveLogs([]*models.LogMessage{
+			{ReportId: rep.Id, Message: "Applied catalog"},
+		}))
+
+		resources, err := r.GetResourcesByReportID(rep.Id)
+		require.NoError(t, err)
+		require.Len(t, resources, 2)
+
+		logs, err := r.GetLogsByReportID(rep.Id)
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+
+		// Resources must belong to a report.
+		require.Error(t, r.SaveResources([]*models.Resource{
+			{ReportId: rep.Id + 1, Status: "changed", Name: "nginx", Type: "Package", File: "init.pp", Line: 1},
+		}))
+	})
+}
+
+func TestRepository_Flapping(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, r *repository) {
+
+		base := time.Date(2024, 12, 24, 13, 0, 0, 0, time.UTC)
+
+		for i, state := range []string{"changed", "failed", "changed"} {
+			rep := saveTestReport(t, r, "web-1", usql.Enum(state), base.Add(time.Duration(i)*time.Hour))
+			require.NoError(t, r.SaveResources([]*models.Resource{
+				{ReportId: rep.Id, Status: "changed", Name: "date", Type: "Exec", File: "init.pp", Line: 1},
+				{ReportId: rep.Id, Status: "unchanged", Name: "nginx", Type: "Package", File: "init.pp", Line: 5},
+			}))
+		}
+		saveTestReport(t, r, "db-1", "unchanged", base)
+
+		// Only the latest two reports of each host are in the window.
+		reports, err := r.GetRecentReports(2, nil)
+		require.NoError(t, err)
+
+		states := make([]string, 0, len(reports))
+		for _, rep := range reports {
+			states = append(states, rep.Host+" "+string(rep.State))
+		}
+		require.Equal(t, []string{"db-1 unchanged", "web-1 changed", "web-1 failed"}, states)
+
+		host := "web"
+		changes, err := r.GetResourceChanges(2, &GetReportsFilters{Host: &host})
+		require.NoError(t, err)
+		require.Equal(t, []*flapping.ResourceChanges{{Host: "web-1", Type: "Exec", Name: "date", Changes: 2}}, changes)
+	})
+}
+
+func TestRepository_Queue(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, r *repository) {
+		now := time.Now().UTC()
+		for i := 0; i < 3; i++ {
+			require.NoError(t, r.QueueReport(&models.ReportQueue{
+				Hash:      fmt.Sprintf("hash-%d", i),
+				Status:    models.ReportQueueStatusPending,
+				Payload:   []byte("report"),
+				CreatedAt: now,
+				UpdatedAt: now,
+			}))
+		}
+
+		claimed, err := r.ClaimQueuedReports(2)
+		require.NoError(t, err)
+		require.Len(t, claimed, 2)
+		require.NotEmpty(t, claimed[0].Claim)
+		require.NotEqual(t, claimed[0].Claim, claimed[1].Claim)
+
+		depth, err := r.GetQueueDepth()
+		require.NoError(t, err)
+		require.Equal(t, map[string]int{"pending": 1, "processing": 2}, depth)
+
+		require.NoError(t, r.StartQueuedReport(claimed[0].Id, claimed[0].Claim))
+		require.ErrorIs(t, r.StartQueuedReport(claimed[0].Id, claimed[1].Claim), ErrQueuedReportNotClaimed)
+		require.ErrorIs(t, r.UpdateQueuedReportStatus(claimed[0].Id, claimed[1].Claim, models.ReportQueueStatusDone, ""), ErrQueuedReportNotClaimed)
+		require.NoError(t, r.UpdateQueuedReportStatus(claimed[0].Id, claimed[0].Claim, models.ReportQueueStatusDone, ""))
+
+		item, err := r.GetQueuedReport(claimed[0].Id)
+		require.NoError(t, err)
+		require.Equal(t, models.ReportQueueStatusDone, item.Status)
+		require.Empty(t, item.Payload)
+		require.Empty(t, item.Claim)
+
+		n, err := r.RequeueStaleReports(time.Now().UTC().Add(time.Minute))
+		require.NoError(t, err)
+		require.EqualValues(t, 1, n)
+
+		// The worker the report was taken from can no longer finish it.
+		require.ErrorIs(t, r.UpdateQueuedReportStatus(claimed[1].Id, claimed[1].Claim, models.ReportQueueStatusDone, ""), ErrQueuedReportNotClaimed)
+
+		_, err = r.GetQueuedReport(100)
+		require.ErrorIs(t, err, ErrQueuedReportNotFound)
+	})
+}
+
+func TestRepository_IngestionFailures(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, r *repository) {
+		now := time.Now().UTC().Truncate(time.Second)
+		for i, receivedAt := range []time.Time{now.Add(-48 * time.Hour), now} {
+			require.NoError(t, r.SaveIngestionFailure(&models.IngestionFailure{
+				Stage:       "parse",
+				Error:       "boom",
+				PayloadHash: fmt.Sprintf("hash-%d", i),
+				PayloadSize: 4,
+				ReceivedAt:  receivedAt,
+			}, []byte("body")))
+		}
+
+		n, err := r.DeleteIngestionFailures(now.Add(-24 * time.Hour))
+		require.NoError(t, err)
+		require.EqualValues(t, 1, n)
+
+		failures, err := r.GetIngestionFailures(pagefilter.GetPaginatorDetails(nil, nil, nil, nil, nil), nil)
+		require.NoError(t, err)
+		require.Len(t, failures.Items, 1)
+
+		payload, err := r.GetIngestionFailurePayload(failures.Items[0].Id)
+		require.NoError(t, err)
+		require.Equal(t, []byte("body"), payload)
+	})
+}
+
+func TestRepository_APITokens(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, r *repository) {
+
+		now := time.Now().UTC().Truncate(time.Second)
+		token := &models.ApiToken{
+			Name:      "puppet-agents",
+			TokenHash: "hash",
+			Scopes:    "reports:write",
+			CreatedAt: now,
+		}
+		require.NoError(t, r.SaveAPIToken(token))
+		require.NotZero(t, token.Id)
+
+		got, err := r.GetAPITokenByHash("hash")
+		require.NoError(t, err)
+		require.Equal(t, "puppet-agents", got.Name)
+		require.False(t, got.LastUsedAt.Valid)
+		require.False(t, got.RevokedAt.Valid)
+
+		require.NoError(t, r.TouchAPIToken(token.Id, now))
+		require.NoError(t, r.RevokeAPIToken(token.Id, now))
+
+		tokens, err := r.GetAPITokens()
+		require.NoError(t, err)
+		require.Len(t, tokens, 1)
+		require.True(t, tokens[0].LastUsedAt.Valid)
+		require.True(t, tokens[0].RevokedAt.Valid)
+
+		_, err = r.GetAPITokenByHash("unknown")
+		require.ErrorIs(t, err, ErrAPITokenNotFound)
+		require.ErrorIs(t, r.RevokeAPIToken(100, now), ErrAPITokenNotFound)
+	})
+}
+
+func TestRepository_AuditEvents(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, r *repository) {
+
+		base := time.Date(2024, 12, 24, 13, 0, 0, 0, time.UTC)
+		events := []*models.AuditEvent{
+			{OccurredAt: base, ActorIp: "10.0.0.1", ActorToken: "ci", Action: "report.upload", Target: "web-1", Outcome: "success", StatusCode: 201},
+			{OccurredAt: base.Add(time.Hour), ActorIp: "10.0.0.2", ActorCertificate: "web-1", Action: "report.upload", Target: "web-1", Outcome: "conflict", StatusCode: 409},
+			{OccurredAt: base.Add(2 * time.Hour), ActorIp: "10.0.0.1", Action: "report.queue", Target: "db-1", Outcome: "denied", StatusCode: 401},
+		}
+		for _, event := range events {
+			require.NoError(t, r.SaveAuditEvent(event))
+			require.NotZero(t, event.Id)
+		}
+
+		details := pagefilter.GetPaginatorDetails(nil, nil, nil, nil, nil)
+
+		got, err := r.GetAuditEvents(details, nil)
+		require.NoError(t, err)
+		require.EqualValues(t, 3, got.Total)
+
+		actor := "web-1"
+		got, err = r.GetAuditEvents(details, &GetAuditEventsFilters{Actor: &actor})
+		require.NoError(t, err)
+		require.EqualValues(t, 1, got.Total)
+		require.Equal(t, events[1].Id, got.Items[0].Id)
+
+		action := "report.upload"
+		from := base.Add(30 * time.Minute)
+		got, err = r.GetAuditEvents(details, &GetAuditEventsFilters{Action: &action, From: &from})
+		require.NoError(t, err)
+		require.EqualValues(t, 1, got.Total)
+		require.Equal(t, "conflict", got.Items[0].Outcome)
+
+		outcome := "error"
+		_, err = r.GetAuditEvents(details, &GetAuditEventsFilters{Outcome: &outcome})
+		require.ErrorIs(t, err, ErrNoAuditEvents)
+	})
+}
+
+func TestRepository_Facts(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, r *repository) {
+
+		base := time.Date(2024, 12, 24, 13, 0, 0, 0, time.UTC)
+		saveTestReport(t, r, "web-1", "changed", base)
+		saveTestReport(t, r, "db-1", "changed", base)
+
+		_, err := r.GetLatestFactSet("web-1")
+		require.ErrorIs(t, err, ErrFactsNotFound)
+
+		require.NoError(t, r.SaveFacts(
+			&models.FactSet{Host: "web-1", Hash: "first", Facts: "{}", Changes: "[]", ReceivedAt: base},
+			[]*models.HostFact{{Host: "web-1", Name: "os.family", Value: "Debian"}},
+		))
+		require.NoError(t, r.SaveFacts(
+			&models.FactSet{Host: "web-1", Hash: "second", Facts: "{}", Changes: "[]", ReceivedAt: base.Add(time.Hour)},
+			[]*models.HostFact{{Host: "web-1", Name: "os.family", Value: "RedHat"}},
+		))
+		require.NoError(t, r.SaveFacts(
+			&models.FactSet{Host: "db-1", Hash: "db", Facts: "{}", Changes: "[]", ReceivedAt: base},
+			[]*models.HostFact{{Host: "db-1", Name: "os.family", Value: "Debian"}},
+		))
+
+		latest, err := r.GetLatestFactSet("web-1")
+		require.NoError(t, err)
+		require.Equal(t, "second", latest.Hash)
+
+		history, err := r.GetFactHistory(pagefilter.GetPaginatorDetails(nil, nil, nil, nil, nil), "web-1")
+		require.NoError(t, err)
+		require.EqualValues(t, 2, history.Total)
+
+		// Only the latest facts of each host are filtered on.
+		reports, err := r.GetReports(pagefilter.GetPaginatorDetails(nil, nil, nil, nil, nil), &GetReportsFilters{
+			Facts: map[string]string{"os.family": "Debian"},
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, 1, reports.Total)
+		require.Equal(t, "db-1", reports.Items[0].Host)
+
+		rep, err := r.GetLatestReportByHost("web-1")
+		require.NoError(t, err)
+		require.Equal(t, "web-1", rep.Host)
+	})
+}
+
+func TestRepository_PuppetDB(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, r *repository) {
+
+		base := time.Date(2024, 12, 24, 13, 0, 0, 0, time.UTC)
+		saveTestReport(t, r, "web-1", "changed", base)
+		latest := saveTestReport(t, r, "web-1", "failed", base.Add(time.Hour))
+		saveTestReport(t, r, "build-1", "unchanged", base)
+
+		require.NoError(t, r.SaveResources([]*models.Resource{
+			{ReportId: latest.Id, Status: "failed", Name: "httpd", Type: "Service", File: "site.pp", Line: 3},
+			{ReportId: latest.Id, Status: "unchanged", Name: "/etc/motd", Type: "File", File: "site.pp", Line: 7},
+		}))
+
+		// Only the latest report of each host is a node.
+		nodes, err := r.GetPDBNodes(&PDBQuery{
+			Condition: NotCondition{Condition: MatchCondition{Column: "t.certname", Pattern: "^build-"}},
+		})
+		require.NoError(t, err)
+		require.Len(t, nodes, 1)
+		require.Equal(t, latest.Hash, nodes[0].LatestReportHash)
+		require.Nil(t, nodes[0].FactsTimestamp)
+
+		reports, err := r.GetPDBReports(&PDBQuery{
+			Condition: OrCondition{
+				CompareCondition{Column: "t.status", Op: "=", Value: "unchanged"},
+				CompareCondition{Column: "t.start_time", Op: ">", Value: base},
+			},
+			OrderBy: []PDBOrder{{Field: "certname", Descending: true}},
+			Limit:   1,
+			Offset:  1,
+		})
+		require.NoError(t, err)
+		require.Len(t, reports, 1)
+		require.Equal(t, "build-1", reports[0].Certname)
+
+		// Unchanged resources have no events.
+		events, err := r.GetPDBEvents(new(PDBQuery))
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		require.Equal(t, "failure", events[0].Status)
+
+		resources, err := r.GetPDBResources(&PDBQuery{Environments: []string{"staging"}})
+		require.NoError(t, err)
+		require.Empty(t, resources)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 func (r *repository) SaveResources(resources []*models.Resource) error {
@@ -30,3 +31,27 @@ func (r *repository) GetResourcesByReportID(reportID int) ([]*models.Resource, e
 
 	return resources, nil
 }
+
+func (r *repository) StreamResourcesByReportID(reportID int, fn func(*models.Resource) error) error {
+	t := prometheus.NewTimer(models.DatabaseLatency.WithLabelValues("stream_resources_by_report_id"))
+	defer t.ObserveDuration()
+
+	rows, err := r.db.Queryx(`SELECT id, report_id, status, name, type, file, line FROM resource WHERE report_id = ? ORDER BY id`, reportID)
+	if err != nil {
+		return fmt.Errorf("stream resources by report id: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		resource := new(models.Resource)
+		if err := rows.StructScan(resource); err != nil {
+			return fmt.Errorf("scan resource: %w", err)
+		}
+
+		if err := fn(resource); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// ErrAPITokenNotFound is returned when an API token is not found.
+	ErrAPITokenNotFound = errors.New("api token not found")
+)
+
+func (r *repository) SaveAPIToken(token *models.ApiToken) error {
+	return token.Insert(r.db)
+}
+
+func (r *repository) GetAPITokenByHash(hash string) (*models.ApiToken, error) {
+	token, err := models.ApiTokenByTokenHash(r.db, hash)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrAPITokenNotFound
+		default:
+			return nil, fmt.Errorf("get api token by hash: %w", err)
+		}
+	}
+
+	return token, nil
+}
+
+func (r *repository) GetAPITokens() ([]*models.ApiToken, error) {
+	t := prometheus.NewTimer(models.DatabaseLatency.WithLabelValues("get_api_tokens"))
+	defer t.ObserveDuration()
+
+	tokens := make([]*models.ApiToken, 0)
+	if err := r.db.Select(&tokens, "SELECT id, name, token_hash, scopes, created_at, expires_at, last_used_at, revoked_at FROM api_token ORDER BY id"); err != nil {
+		return nil, fmt.Errorf("get api tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+func (r *repository) RevokeAPIToken(id int, at time.Time) error {
+	token, err := models.ApiTokenById(r.db, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrAPITokenNotFound
+		default:
+			return fmt.Errorf("get api token by id: %w", err)
+		}
+	}
+
+	// Revoking a token again keeps the time it was first revoked.
+	if token.RevokedAt.Valid {
+		return nil
+	}
+
+	if _, err := r.db.Exec("UPDATE api_token SET revoked_at = ? WHERE id = ?", at, id); err != nil {
+		return fmt.Errorf("revoke api token: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repository) TouchAPIToken(id int, at time.Time) error {
+	if _, err := r.db.Exec("UPDATE api_token SET last_used_at = ? WHERE id = ?", at, id); err != nil {
+		return fmt.Errorf("touch api token: %w", err)
+	}
+
+	return nil
+}
@@ -1,15 +1,19 @@
 package filters
 
-import "github.com/jacobbrewer1/pagefilter"
+import (
+	"github.com/jacobbrewer1/pagefilter"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/storage"
+)
 
 type reportsEnvironmentLike struct {
+	driver      storage.Driver
 	environment string
 }
 
-func NewReportsEnvironmentLike(environment string) pagefilter.Wherer {
-	return &reportsEnvironmentLike{environment: environment}
+func NewReportsEnvironmentLike(driver storage.Driver, environment string) pagefilter.Wherer {
+	return &reportsEnvironmentLike{driver: driver, environment: environment}
 }
 
 func (f *reportsEnvironmentLike) Where() (string, []interface{}) {
-	return "t.environment LIKE ?", []interface{}{"%" + f.environment + "%"}
+	return f.driver.Like("t.environment"), []interface{}{"%" + f.environment + "%"}
 }
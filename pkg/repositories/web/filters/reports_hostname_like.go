@@ -1,15 +1,19 @@
 package filters
 
-import "github.com/jacobbrewer1/pagefilter"
+import (
+	"github.com/jacobbrewer1/pagefilter"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/storage"
+)
 
 type reportsHostnameLike struct {
+	driver   storage.Driver
 	hostname string
 }
 
-func NewReportHostnameLike(hostname string) pagefilter.Wherer {
-	return &reportsHostnameLike{hostname: hostname}
+func NewReportHostnameLike(driver storage.Driver, hostname string) pagefilter.Wherer {
+	return &reportsHostnameLike{driver: driver, hostname: hostname}
 }
 
 func (f *reportsHostnameLike) Where() (string, []interface{}) {
-	return "t.host LIKE ?", []interface{}{"%" + f.hostname + "%"}
+	return f.driver.Like("t.host"), []interface{}{"%" + f.hostname + "%"}
 }
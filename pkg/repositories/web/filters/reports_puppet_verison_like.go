@@ -1,15 +1,25 @@
 package filters
 
-import "github.com/jacobbrewer1/pagefilter"
+import (
+	"github.com/jacobbrewer1/pagefilter"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/storage"
+)
 
 type reportsPuppetVersionLike struct {
+	driver        storage.Driver
 	puppetVersion string
 }
 
-func NewReportsPuppetVersionLike(puppetVersion string) pagefilter.Wherer {
-	return &reportsPuppetVersionLike{puppetVersion: puppetVersion}
+func NewReportsPuppetVersionLike(driver storage.Driver, puppetVersion string) pagefilter.Wherer {
+	return &reportsPuppetVersionLike{driver: driver, puppetVersion: puppetVersion}
 }
 
 func (f *reportsPuppetVersionLike) Where() (string, []interface{}) {
-	return "t.puppet_version LIKE ?", []interface{}{"%" + f.puppetVersion + "%"}
+	column := "t.puppet_version"
+	if f.driver == storage.DriverPostgres {
+		// PostgreSQL does not compare numbers with LIKE.
+		column = "CAST(t.puppet_version AS TEXT)"
+	}
+
+	return f.driver.Like(column), []interface{}{"%" + f.puppetVersion + "%"}
 }
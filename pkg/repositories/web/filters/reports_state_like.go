@@ -1,15 +1,19 @@
 package filters
 
-import "github.com/jacobbrewer1/pagefilter"
+import (
+	"github.com/jacobbrewer1/pagefilter"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/storage"
+)
 
 type reportsStateLike struct {
-	state string
+	driver storage.Driver
+	state  string
 }
 
-func NewReportsStateLike(state string) pagefilter.Wherer {
-	return &reportsStateLike{state: state}
+func NewReportsStateLike(driver storage.Driver, state string) pagefilter.Wherer {
+	return &reportsStateLike{driver: driver, state: state}
 }
 
 func (f *reportsStateLike) Where() (string, []interface{}) {
-	return "t.state LIKE ?", []interface{}{"%" + f.state + "%"}
+	return f.driver.Like("t.state"), []interface{}{"%" + f.state + "%"}
 }
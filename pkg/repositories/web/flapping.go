@@ -0,0 +1,76 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jacobbrewer1/puppet-reporter/pkg/flapping"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// recentReportsView numbers the reports of each host, the latest report of the host being run 1.
+const recentReportsView = `
+	SELECT id, hash, host, puppet_version, environment, state, executed_at, runtime, failed, changed, skipped, total,
+		runtime_regression, ROW_NUMBER() OVER (PARTITION BY host ORDER BY executed_at DESC, id DESC) AS run
+	FROM report`
+
+func (r *repository) GetRecentReports(window int, hosts []string) ([]*models.Report, error) {
+	if len(hosts) == 0 {
+		return make([]*models.Report, 0), nil
+	}
+
+	t := prometheus.NewTimer(models.DatabaseLatency.WithLabelValues("get_recent_reports"))
+	defer t.ObserveDuration()
+
+	inSQL, args := hostsIn(hosts)
+	sqlStr := `
+		SELECT t.id, t.hash, t.host, t.puppet_version, t.environment, t.state, t.executed_at, t.runtime, t.failed, t.changed, t.skipped, t.total, t.runtime_regression
+		FROM (` + recentReportsView + `) t
+		WHERE t.run <= ? AND ` + inSQL + `
+		ORDER BY t.host, t.run
+	`
+
+	reports := make([]*models.Report, 0)
+	if err := r.db.Select(&reports, sqlStr, append([]any{window}, args...)...); err != nil {
+		return nil, fmt.Errorf("get recent reports: %w", err)
+	}
+
+	return reports, nil
+}
+
+func (r *repository) GetResourceChanges(window int, hosts []string) ([]*flapping.ResourceChanges, error) {
+	if len(hosts) == 0 {
+		return make([]*flapping.ResourceChanges, 0), nil
+	}
+
+	t := prometheus.NewTimer(models.DatabaseLatency.WithLabelValues("get_resource_changes"))
+	defer t.ObserveDuration()
+
+	inSQL, args := hostsIn(hosts)
+	sqlStr := `
+		SELECT t.host, res.type, res.name, COUNT(*) AS changes
+		FROM (` + recentReportsView + `) t
+		INNER JOIN resource res ON res.report_id = t.id
+		WHERE t.run <= ? AND res.status = ? AND ` + inSQL + `
+		GROUP BY t.host, res.type, res.name
+		ORDER BY t.host, res.type, res.name
+	`
+
+	changes := make([]*flapping.ResourceChanges, 0)
+	if err := r.db.Select(&changes, sqlStr, append([]any{window, models.ResourceStatusChanged}, args...)...); err != nil {
+		return nil, fmt.Errorf("get resource changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+// hostsIn returns the condition of the reports of the hosts, there must be at least one host.
+func hostsIn(hosts []string) (string, []any) {
+	args := make([]any, len(hosts))
+	for i, host := range hosts {
+		args[i] = host
+	}
+
+	return "t.host IN (?" + strings.Repeat(", ?", len(hosts)-1) + ")", args
+}
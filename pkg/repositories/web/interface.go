@@ -2,12 +2,21 @@ package api
 
 import (
 	"github.com/jacobbrewer1/pagefilter"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/flapping"
 	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
 )
 
 type Repository interface {
 	// ListLatestHosts returns a list of the latest reports for each unique host.
 	ListLatestHosts(details *pagefilter.PaginatorDetails, filters *ListLatestHostsFilters) (*pagefilter.PaginatedResponse[models.Report], error)
+
+	// GetRecentReports returns up to window of the latest reports of each of the hosts, ordered by host and the latest
+	// report first.
+	GetRecentReports(window int, hosts []string) ([]*models.Report, error)
+
+	// GetResourceChanges returns how many of up to window of the latest reports of each of the hosts changed each
+	// resource.
+	GetResourceChanges(window int, hosts []string) ([]*flapping.ResourceChanges, error)
 }
 
 type ListLatestHostsFilters struct {
@@ -20,6 +29,14 @@ type ListLatestHostsFilters struct {
 	// Environment is the environment to filter by.
 	Environment *string
 
+	// Environments restricts the reports to the environments the reader can read, nil if it can read every
+	// environment.
+	Environments []string
+
 	// Status is the status to filter by.
 	Status *string
+
+	// Facts restricts the reports to the hosts whose latest facts have each of the values, keyed by the dotted name
+	// of the fact.
+	Facts map[string]string
 }
@@ -4,6 +4,7 @@ package api
 
 import (
 	pagefilter "github.com/jacobbrewer1/pagefilter"
+	flapping "github.com/jacobbrewer1/puppet-reporter/pkg/flapping"
 	models "github.com/jacobbrewer1/puppet-reporter/pkg/models"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -13,6 +14,66 @@ type MockRepository struct {
 	mock.Mock
 }
 
+// GetRecentReports provides a mock function with given fields: window, hosts
+func (_m *MockRepository) GetRecentReports(window int, hosts []string) ([]*models.Report, error) {
+	ret := _m.Called(window, hosts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRecentReports")
+	}
+
+	var r0 []*models.Report
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, []string) ([]*models.Report, error)); ok {
+		return rf(window, hosts)
+	}
+	if rf, ok := ret.Get(0).(func(int, []string) []*models.Report); ok {
+		r0 = rf(window, hosts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Report)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, []string) error); ok {
+		r1 = rf(window, hosts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetResourceChanges provides a mock function with given fields: window, hosts
+func (_m *MockRepository) GetResourceChanges(window int, hosts []string) ([]*flapping.ResourceChanges, error) {
+	ret := _m.Called(window, hosts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetResourceChanges")
+	}
+
+	var r0 []*flapping.ResourceChanges
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, []string) ([]*flapping.ResourceChanges, error)); ok {
+		return rf(window, hosts)
+	}
+	if rf, ok := ret.Get(0).(func(int, []string) []*flapping.ResourceChanges); ok {
+		r0 = rf(window, hosts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*flapping.ResourceChanges)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, []string) error); ok {
+		r1 = rf(window, hosts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListLatestHosts provides a mock function with given fields: details, filters
 func (_m *MockRepository) ListLatestHosts(details *pagefilter.PaginatorDetails, filters *ListLatestHostsFilters) (*pagefilter.PaginatedResponse[models.Report], error) {
 	ret := _m.Called(details, filters)
@@ -4,10 +4,13 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 
 	"github.com/jacobbrewer1/pagefilter"
 	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
 	"github.com/jacobbrewer1/puppet-reporter/pkg/repositories/web/filters"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/storage"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -23,7 +26,9 @@ func (r *repository) ListLatestHosts(details *pagefilter.PaginatorDetails, filte
 	mf := r.getListLatestHostFilters(filters)
 	pg := pagefilter.NewPaginator(r.db, models.ReportTableName, "id", mf)
 
-	if err := pg.SetDetails(details, "host", "executed_at"); err != nil {
+	// The details are copied, as the first page changes them.
+	pd := *details
+	if err := pg.SetDetails(&pd, "host", "executed_at"); err != nil {
 		return nil, fmt.Errorf("set paginator details: %w", err)
 	}
 
@@ -37,6 +42,8 @@ func (r *repository) ListLatestHosts(details *pagefilter.PaginatorDetails, filte
 		}
 	}
 
+	storage.FirstPage(&pd)
+
 	reports := make([]*models.Report, 0)
 	if err := pg.Retrieve(pvt, &reports); err != nil {
 		return nil, fmt.Errorf("retrieve reports: %w", err)
@@ -60,19 +67,27 @@ func (r *repository) getListLatestHostFilters(f *ListLatestHostsFilters) *pagefi
 	}
 
 	if f.Hostname != nil {
-		mf.Add(filters.NewReportHostnameLike(*f.Hostname))
+		mf.Add(filters.NewReportHostnameLike(r.driver, *f.Hostname))
 	}
 
 	if f.PuppetVersion != nil {
-		mf.Add(filters.NewReportsPuppetVersionLike(*f.PuppetVersion))
+		mf.Add(filters.NewReportsPuppetVersionLike(r.driver, *f.PuppetVersion))
 	}
 
 	if f.Environment != nil {
-		mf.Add(filters.NewReportsEnvironmentLike(*f.Environment))
+		mf.Add(filters.NewReportsEnvironmentLike(r.driver, *f.Environment))
+	}
+
+	if f.Environments != nil {
+		mf.Add(filters.NewReportsEnvironmentIn(f.Environments))
 	}
 
 	if f.Status != nil {
-		mf.Add(filters.NewReportsStateLike(*f.Status))
+		mf.Add(filters.NewReportsStateLike(r.driver, *f.Status))
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(f.Facts)) {
+		mf.Add(filters.NewReportsFactEquals(name, f.Facts[name]))
 	}
 
 	return mf
@@ -1,17 +1,22 @@
 package api
 
 import (
+	"github.com/jacobbrewer1/puppet-reporter/pkg/storage"
 	"github.com/jacobbrewer1/vaulty/repositories"
 )
 
 type repository struct {
 	// db is the database used by the repository.
 	db *repositories.Database
+
+	// driver is the driver of the database, the filters differ between the databases.
+	driver storage.Driver
 }
 
 // NewRepository creates a new repository.
 func NewRepository(db *repositories.Database) Repository {
 	return &repository{
-		db: db,
+		db:     db,
+		driver: storage.DriverOf(db),
 	}
 }
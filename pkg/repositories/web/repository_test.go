@@ -0,0 +1,40 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jacobbrewer1/pagefilter"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/storage/storagetest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_ListLatestHosts(t *testing.T) {
+	for driver, db := range storagetest.Databases(t) {
+		t.Run(string(driver), func(t *testing.T) {
+			r := NewRepository(db).(*repository)
+
+			executedAt := time.Date(2024, 12, 24, 13, 0, 0, 0, time.UTC)
+			for _, rep := range []*models.Report{
+				{Hash: "a", Host: "web-1", PuppetVersion: 8.1, Environment: "production", State: "changed", ExecutedAt: executedAt},
+				{Hash: "b", Host: "web-2", PuppetVersion: 7.2, Environment: "staging", State: "failed", ExecutedAt: executedAt},
+				{Hash: "c", Host: "db-1", PuppetVersion: 8.1, Environment: "production", State: "unchanged", ExecutedAt: executedAt},
+			} {
+				require.NoError(t, rep.Insert(r.db))
+			}
+
+			// The host search is case-insensitive on every database.
+			hostname := "WEB"
+			status := "changed"
+			got, err := r.ListLatestHosts(pagefilter.GetPaginatorDetails(nil, nil, nil, nil, nil), &ListLatestHostsFilters{
+				Hostname: &hostname,
+				Status:   &status,
+			})
+			require.NoError(t, err)
+			require.EqualValues(t, 1, got.Total)
+			require.Len(t, got.Items, 1)
+			require.Equal(t, "web-1", got.Items[0].Host)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/apis/specs/api"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/auth"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/events"
+	repo "github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api"
+	svc "github.com/jacobbrewer1/puppet-reporter/pkg/services/api"
+	"github.com/jacobbrewer1/vaulty/repositories"
+	"github.com/spf13/viper"
+)
+
+// setupAPI registers the API routes, starting the ingestion workers if the ingestion is asynchronous. The tokens can
+// only read the environments the access control allows, if it is set. The ingested reports are published to the broker.
+func (s *ServeCmd) setupAPI(
+	ctx context.Context,
+	r *mux.Router,
+	db *repositories.Database,
+	v *viper.Viper,
+	acl *auth.ACL,
+	broker *events.Broker,
+	metrics *httpMetrics,
+) error {
+	repository := repo.NewRepository(db)
+
+	hostFilter, err := svc.NewHostFilter(v.GetStringSlice("metrics.hosts"), v.GetString("metrics.host_pattern"))
+	if err != nil {
+		return fmt.Errorf("error creating host metrics filter: %w", err)
+	}
+
+	if err := svc.RebuildHostMetrics(repository, hostFilter); err != nil {
+		return fmt.Errorf("error rebuilding host metrics: %w", err)
+	}
+
+	serviceOpts := []svc.ServiceOption{
+		svc.WithHostFilter(hostFilter),
+		svc.WithBroker(broker),
+	}
+
+	v.SetDefault("regression.window", svc.DefaultRegressionWindow)
+	v.SetDefault("regression.factor", svc.DefaultRegressionFactor)
+	serviceOpts = append(serviceOpts, svc.WithRuntimeRegression(v.GetInt("regression.window"), v.GetFloat64("regression.factor")))
+
+	authzOpts := make([]svc.AuthzOption, 0)
+	if s.tlsConfig != nil && s.tlsConfig.ClientCAs != nil {
+		serviceOpts = append(serviceOpts, svc.WithClientCertificates())
+		authzOpts = append(authzOpts, svc.WithCertificateUploads())
+
+		slog.Info("Client certificates are required for uploads")
+	}
+
+	if acl != nil {
+		authzOpts = append(authzOpts, svc.WithACL(acl))
+	}
+
+	s.asyncIngestion = v.GetBool("ingestion.async")
+	if s.asyncIngestion {
+		serviceOpts = append(serviceOpts, svc.WithAsyncIngestion())
+
+		v.SetDefault("ingestion.workers", runtime.NumCPU())
+		v.SetDefault("ingestion.poll_interval", time.Second)
+		v.SetDefault("ingestion.stale_after", 10*time.Minute)
+
+		pool, err := svc.NewWorkerPool(
+			repository,
+			v.GetInt("ingestion.workers"),
+			v.GetDuration("ingestion.poll_interval"),
+			v.GetDuration("ingestion.stale_after"),
+			serviceOpts...,
+		)
+		if err != nil {
+			return fmt.Errorf("error creating ingestion worker pool: %w", err)
+		}
+
+		go pool.Run(ctx)
+
+		slog.Info("Asynchronous ingestion enabled", slog.Int("workers", v.GetInt("ingestion.workers")))
+	}
+
+	// A retention of zero keeps the ingestion failures forever.
+	v.SetDefault("ingestion.failure_retention", svc.DefaultIngestionFailureRetention)
+	if retention := v.GetDuration("ingestion.failure_retention"); retention > 0 {
+		go svc.PruneIngestionFailures(ctx, repository, retention)
+	}
+
+	service := svc.NewService(repository, serviceOpts...)
+
+	serverOpts := []api.ServerOption{
+		api.WithMetricsMiddleware(metrics.middleware),
+	}
+
+	auditOpts := make([]svc.AuditOption, 0)
+	if v.GetBool("audit.trust_forwarded_for") {
+		auditOpts = append(auditOpts, svc.WithAuditForwardedFor())
+	}
+
+	// The uploads over the limits are answered before they reach the audit log of the API, so they are recorded on the
+	// way in.
+	r.Use(svc.AuditRejected(repository, auditOpts...))
+
+	v.SetDefault("upload.max_body_bytes", svc.DefaultMaxBodyBytes)
+	r.Use(svc.LimitRequestBody(v.GetInt64("upload.max_body_bytes")))
+
+	globalRate := svc.RateLimit{
+		RPS:   v.GetFloat64("upload.rate_limit.global_rps"),
+		Burst: v.GetInt("upload.rate_limit.global_burst"),
+	}
+	clientRate := svc.RateLimit{
+		RPS:   v.GetFloat64("upload.rate_limit.client_rps"),
+		Burst: v.GetInt("upload.rate_limit.client_burst"),
+	}
+	if globalRate.RPS > 0 || clientRate.RPS > 0 {
+		limiter := svc.NewRateLimiter(globalRate, clientRate, v.GetBool("upload.rate_limit.trust_forwarded_for"))
+		serverOpts = append(serverOpts, api.WithRateLimiter(limiter.Limit))
+
+		slog.Info(
+			"Upload rate limit enabled",
+			slog.Float64("global_rps", globalRate.RPS),
+			slog.Float64("client_rps", clientRate.RPS),
+		)
+	}
+
+	// The authentication is opt-in, as the agents of existing deployments upload without a token. Tokens are created
+	// with the token command before it is enabled.
+	handler := service
+	if v.GetBool("auth.enabled") {
+		handler = svc.NewAuthz(repository, service, authzOpts...)
+
+		slog.Info("API authentication enabled, requests need a token")
+	} else {
+		slog.Warn("API authentication is disabled, anyone who can reach the API can upload and read reports, set auth.enabled to require tokens")
+	}
+
+	// The audit log wraps the authorisation, so the uploads that are not authorised are recorded too.
+	api.RegisterHandlers(r, svc.NewAudit(repository, handler, auditOpts...), serverOpts...)
+
+	return nil
+}
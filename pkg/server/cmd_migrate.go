@@ -0,0 +1,182 @@
+package server
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/subcommands"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/logging"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/storage"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/utils"
+)
+
+const (
+	migrateUp       = "up"
+	migrateDown     = "down"
+	migrateStatus   = "status"
+	migrateBaseline = "baseline"
+)
+
+// MigrateCmd applies, rolls back or shows the database migrations.
+type MigrateCmd struct {
+	// configLocation is the location of the config file
+	configLocation string
+
+	// steps is the number of migrations to roll back, it can be given before or after down
+	steps int
+}
+
+func (m *MigrateCmd) Name() string {
+	return "migrate"
+}
+
+func (m *MigrateCmd) Synopsis() string {
+	return "Apply, roll back or show the database migrations"
+}
+
+func (m *MigrateCmd) Usage() string {
+	return `migrate [-config config.json] up|down|status|baseline <version>:
+  Apply, roll back or show the database migrations that are built into the binary.
+
+  up                  Apply the migrations that have not been applied.
+  down [-steps n]     Roll back the most recently applied migrations, one unless -steps is given.
+  status              Show which migrations have been applied.
+  baseline <version>  Record the migrations up to the version as applied without running them, for databases
+                      migrated before the migrations were recorded. Databases migrated by golang-migrate are
+                      imported from its schema_migrations table without a baseline.
+`
+}
+
+func (m *MigrateCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&m.configLocation, "config", "config.json", "The location of the config file")
+	f.IntVar(&m.steps, "steps", 1, "The number of migrations to roll back")
+}
+
+func (m *MigrateCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	args := f.Args()
+	if len(args) == 0 {
+		f.Usage()
+		return subcommands.ExitUsageError
+	}
+
+	// The arguments are checked before connecting, so a typo does not wait for the database.
+	if args[0] == migrateDown {
+		if err := m.parseDownFlags(args[1:]); err != nil {
+			slog.Error("Error parsing down flags", slog.String(logging.KeyError, err.Error()))
+			return subcommands.ExitUsageError
+		}
+
+		if m.steps < 1 {
+			slog.Error("Steps must be at least 1")
+			return subcommands.ExitUsageError
+		}
+	}
+
+	v, err := utils.LoadConfig(m.configLocation)
+	if err != nil {
+		slog.Error("Error loading config", slog.String(logging.KeyError, err.Error()))
+		return subcommands.ExitFailure
+	}
+
+	db, err := storage.Connect(ctx, v)
+	if err != nil {
+		slog.Error("Error connecting to database", slog.String(logging.KeyError, err.Error()))
+		return subcommands.ExitFailure
+	}
+	defer db.Close()
+
+	migrator, err := storage.NewMigrator(db)
+	if err != nil {
+		slog.Error("Error creating migrator", slog.String(logging.KeyError, err.Error()))
+		return subcommands.ExitFailure
+	}
+
+	switch args[0] {
+	case migrateUp:
+		n, err := migrator.Up()
+		if err != nil {
+			slog.Error("Error applying migrations", slog.String(logging.KeyError, err.Error()))
+			return subcommands.ExitFailure
+		}
+		slog.Info("Migrations applied", slog.Int("count", n))
+	case migrateDown:
+		n, err := migrator.Down(m.steps)
+		if err != nil {
+			slog.Error("Error rolling back migrations", slog.String(logging.KeyError, err.Error()))
+			return subcommands.ExitFailure
+		}
+		slog.Info("Migrations rolled back", slog.Int("count", n))
+	case migrateStatus:
+		statuses, err := migrator.Status()
+		if err != nil {
+			slog.Error("Error getting migration status", slog.String(logging.KeyError, err.Error()))
+			return subcommands.ExitFailure
+		}
+
+		if err := printMigrationStatus(statuses); err != nil {
+			slog.Error("Error printing migration status", slog.String(logging.KeyError, err.Error()))
+			return subcommands.ExitFailure
+		}
+	case migrateBaseline:
+		if len(args) != 2 {
+			f.Usage()
+			return subcommands.ExitUsageError
+		}
+
+		n, err := migrator.Baseline(args[1])
+		if err != nil {
+			slog.Error("Error recording migrations", slog.String(logging.KeyError, err.Error()))
+			return subcommands.ExitFailure
+		}
+		slog.Info("Migrations recorded as applied", slog.Int("count", n))
+	default:
+		f.Usage()
+		return subcommands.ExitUsageError
+	}
+
+	return subcommands.ExitSuccess
+}
+
+// parseDownFlags parses the flags given after down. The flags of the command stop at the first argument, so
+// "migrate down -steps 2" would otherwise roll back a single migration.
+func (m *MigrateCmd) parseDownFlags(args []string) error {
+	f := flag.NewFlagSet(migrateDown, flag.ContinueOnError)
+	f.IntVar(&m.steps, "steps", m.steps, "The number of migrations to roll back")
+	if err := f.Parse(args); err != nil {
+		return err
+	}
+
+	if f.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments %s", strings.Join(f.Args(), " "))
+	}
+
+	return nil
+}
+
+// printMigrationStatus prints the state of each migration as a table.
+func printMigrationStatus(statuses []*storage.MigrationStatus) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tSTATE\tAPPLIED AT")
+
+	for _, s := range statuses {
+		state := "pending"
+		appliedAt := ""
+		if s.AppliedAt != nil {
+			state = "applied"
+			appliedAt = s.AppliedAt.UTC().Format(time.RFC3339)
+		}
+		if s.Unknown {
+			state = "unknown"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\n", s.Version, state, appliedAt)
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateCmd_ParseDownFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "no flags",
+			args: []string{"down"},
+			want: 1,
+		},
+		{
+			name: "flag before down",
+			args: []string{"-steps", "3", "down"},
+			want: 3,
+		},
+		{
+			name: "flag after down",
+			args: []string{"down", "-steps", "2"},
+			want: 2,
+		},
+		{
+			name:    "unexpected argument",
+			args:    []string{"down", "2"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(MigrateCmd)
+			f := flag.NewFlagSet("migrate", flag.ContinueOnError)
+			m.SetFlags(f)
+			require.NoError(t, f.Parse(tt.args))
+
+			err := m.parseDownFlags(f.Args()[1:])
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, m.steps)
+		})
+	}
+}
@@ -0,0 +1,236 @@
+// Package server is the HTTP server of the API and the web UI, and the commands of the binaries serving them.
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"runtime"
+
+	"github.com/google/subcommands"
+	"github.com/gorilla/mux"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/auth"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/events"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/logging"
+	webRepo "github.com/jacobbrewer1/puppet-reporter/pkg/repositories/web"
+	svc "github.com/jacobbrewer1/puppet-reporter/pkg/services/api"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/services/web"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/storage"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/utils"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/version"
+	"github.com/jacobbrewer1/uhttp"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// errNothingToServe is returned when both the API and the web UI are disabled.
+var errNothingToServe = errors.New("nothing to serve, enable at least one of -api and -web")
+
+// ServeCmd starts the server of the API, the web UI or both.
+type ServeCmd struct {
+	// appName is the name of the binary, the metrics of the http requests are in its namespace
+	appName string
+
+	// port is the port to listen on
+	port string
+
+	// configLocation is the location of the config file
+	configLocation string
+
+	// autoMigrate is true if the pending database migrations are applied on start up
+	autoMigrate bool
+
+	// serveAPI is true if the API is served
+	serveAPI bool
+
+	// serveWeb is true if the web UI is served
+	serveWeb bool
+
+	// selectable is true if the binary serves both, and either can be turned off with the -api and -web flags
+	selectable bool
+
+	// asyncIngestion is true if uploaded reports are queued rather than ingested in the request
+	asyncIngestion bool
+
+	// tlsConfig is the TLS configuration of the server, nil if it serves plain HTTP
+	tlsConfig *tls.Config
+}
+
+// NewServeCmd returns the serve command of the binary, serving the API, the web UI or both. When it serves both,
+// either can be turned off with the -api and -web flags.
+func NewServeCmd(appName string, serveAPI, serveWeb bool) *ServeCmd {
+	return &ServeCmd{
+		appName:    appName,
+		serveAPI:   serveAPI,
+		serveWeb:   serveWeb,
+		selectable: serveAPI && serveWeb,
+	}
+}
+
+func (s *ServeCmd) Name() string {
+	return "serve"
+}
+
+func (s *ServeCmd) Synopsis() string {
+	if s.selectable {
+		return "Start the API and the web UI on one server"
+	}
+	return "Start the web server"
+}
+
+func (s *ServeCmd) Usage() string {
+	if s.selectable {
+		return `serve [-api=false] [-web=false]:
+  Start the API and the web UI on one server, sharing the database connections.
+`
+	}
+	return `serve:
+  Start the web server.
+`
+}
+
+func (s *ServeCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&s.port, "port", "8080", "The port to listen on")
+	f.StringVar(&s.configLocation, "config", "config.json", "The location of the config file")
+	f.BoolVar(&s.autoMigrate, "auto-migrate", false, "Apply the pending database migrations on start up")
+	if s.selectable {
+		f.BoolVar(&s.serveAPI, "api", true, "Serve the API")
+		f.BoolVar(&s.serveWeb, "web", true, "Serve the web UI")
+	}
+}
+
+func (s *ServeCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if !s.serveAPI && !s.serveWeb {
+		slog.Error("Error setting up server", slog.String(logging.KeyError, errNothingToServe.Error()))
+		return subcommands.ExitUsageError
+	}
+
+	r := mux.NewRouter()
+	err := s.setup(ctx, r)
+	if err != nil {
+		slog.Error("Error setting up server", slog.String(logging.KeyError, err.Error()))
+		return subcommands.ExitFailure
+	}
+
+	slog.Info(
+		"Starting application",
+		slog.String("version", version.Commit),
+		slog.String("runtime", fmt.Sprintf("%s %s/%s", runtime.Version(), runtime.GOOS, runtime.GOARCH)),
+		slog.String("build_date", version.Date),
+		slog.Bool("api", s.serveAPI),
+		slog.Bool("web", s.serveWeb),
+	)
+
+	var handler http.Handler = r
+	if s.asyncIngestion {
+		handler = svc.QueueUploads(r)
+	}
+
+	srv := &http.Server{
+		Addr:      ":" + s.port,
+		Handler:   handler,
+		TLSConfig: s.tlsConfig,
+	}
+
+	// Start the server in a goroutine, so we can listen for the context to be done.
+	go func(srv *http.Server) {
+		var err error
+		if srv.TLSConfig != nil {
+			// The certificate is already loaded into the TLS configuration.
+			err = srv.ListenAndServeTLS("", "")
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if errors.Is(err, http.ErrServerClosed) {
+			slog.Info("Server closed gracefully")
+			os.Exit(0)
+		} else if err != nil {
+			slog.Error("Error serving requests", slog.String(logging.KeyError, err.Error()))
+			os.Exit(1)
+		}
+	}(srv)
+
+	<-ctx.Done()
+	slog.Info("Shutting down application")
+	if err := srv.Shutdown(ctx); err != nil {
+		slog.Error("Error shutting down application", slog.String(logging.KeyError, err.Error()))
+		return subcommands.ExitFailure
+	}
+
+	return subcommands.ExitSuccess
+}
+
+func (s *ServeCmd) setup(ctx context.Context, r *mux.Router) (err error) {
+	v, err := utils.LoadConfig(s.configLocation)
+	if err != nil {
+		return err
+	}
+
+	db, err := storage.Connect(ctx, v)
+	if err != nil {
+		return fmt.Errorf("error connecting to database: %w", err)
+	}
+
+	if err := storage.EnsureSchema(db, s.autoMigrate); err != nil {
+		return fmt.Errorf("error checking database schema, run migrate up or serve with -auto-migrate, or run migrate baseline <version> if the schema was migrated by other means: %w", err)
+	}
+
+	s.tlsConfig, err = auth.TLSConfig(v)
+	if err != nil {
+		return fmt.Errorf("error creating TLS configuration: %w", err)
+	}
+
+	metrics := newHTTPMetrics(s.appName)
+
+	r.HandleFunc("/metrics", uhttp.InternalOnly(promhttp.Handler())).Methods(http.MethodGet)
+	r.HandleFunc("/health", uhttp.InternalOnly(healthHandler(db))).Methods(http.MethodGet)
+
+	r.NotFoundHandler = uhttp.NotFoundHandler()
+	r.MethodNotAllowedHandler = uhttp.MethodNotAllowedHandler()
+
+	webOpts := make([]web.ServiceOption, 0)
+	if s.serveWeb {
+		login, err := auth.OIDCFromConfig(ctx, v)
+		if err != nil {
+			return fmt.Errorf("error creating oidc login: %w", err)
+		} else if login != nil {
+			// The session is read before the access control, so the access is of the logged-in user.
+			r.Use(login.Middleware())
+			webOpts = append(webOpts, web.WithLogin(login))
+
+			slog.Info("OIDC login enabled for the web UI")
+		}
+	}
+
+	acl, err := auth.ACLFromConfig(v)
+	if err != nil {
+		return fmt.Errorf("error creating access control: %w", err)
+	} else if acl != nil {
+		r.Use(acl.Middleware())
+
+		slog.Info("Access control enabled, reads are restricted to the environments of each identity")
+	}
+
+	// The reports are only ingested by the API, so the web can only show them live when it is served with the API.
+	var broker *events.Broker
+	if s.serveAPI {
+		broker = events.NewBroker(events.DefaultBufferSize)
+		webOpts = append(webOpts, web.WithBroker(broker))
+	}
+
+	if s.serveWeb {
+		web.NewService(webRepo.NewRepository(db), webOpts...).Register(r, metrics.middleware)
+	}
+
+	if s.serveAPI {
+		if err := s.setupAPI(ctx, r, db, v, acl, broker, metrics); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,19 @@
+package server
+
+import (
+	"context"
+	"flag"
+	"testing"
+
+	"github.com/google/subcommands"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeCmd_NothingToServe(t *testing.T) {
+	s := NewServeCmd("test", true, true)
+	f := flag.NewFlagSet("serve", flag.ContinueOnError)
+	s.SetFlags(f)
+	require.NoError(t, f.Parse([]string{"-api=false", "-web=false"}))
+
+	require.Equal(t, subcommands.ExitUsageError, s.Execute(context.Background(), f))
+}
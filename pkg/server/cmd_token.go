@@ -0,0 +1,232 @@
+package server
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/subcommands"
+	"github.com/jacobbrewer1/goschema/usql"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/auth"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/logging"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+	repo "github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/storage"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/utils"
+)
+
+const (
+	tokenCreate = "create"
+	tokenList   = "list"
+	tokenRevoke = "revoke"
+)
+
+// TokenCmd creates, lists or revokes the API tokens.
+type TokenCmd struct {
+	// configLocation is the location of the config file
+	configLocation string
+
+	// name is the name of the token that is created
+	name string
+
+	// scopes are the scopes of the token that is created
+	scopes string
+
+	// expiresIn is how long the created token is valid for, forever if zero
+	expiresIn time.Duration
+}
+
+func (t *TokenCmd) Name() string {
+	return "token"
+}
+
+func (t *TokenCmd) Synopsis() string {
+	return "Create, list or revoke the API tokens"
+}
+
+func (t *TokenCmd) Usage() string {
+	return `token [-config config.json] create|list|revoke <id>:
+  Create, list or revoke the API tokens.
+
+  create -name <name> -scopes <scopes> [-expires-in 720h]
+                Create a token and print it. The token is only shown once.
+  list          Show the tokens.
+  revoke <id>   Revoke the token, it can no longer be used.
+
+  The scopes are reports:read, reports:write and admin, separated by commas. The tokens are only required once
+  auth.enabled is set in the config, so the agents can be given their tokens before it is.
+`
+}
+
+func (t *TokenCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&t.configLocation, "config", "config.json", "The location of the config file")
+	f.StringVar(&t.name, "name", "", "The name of the token to create")
+	f.StringVar(&t.scopes, "scopes", "", "The scopes of the token to create, separated by commas")
+	f.DurationVar(&t.expiresIn, "expires-in", 0, "How long the token is valid for, it does not expire if not set")
+}
+
+func (t *TokenCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	args := f.Args()
+	if len(args) == 0 {
+		f.Usage()
+		return subcommands.ExitUsageError
+	}
+
+	// The flags of the command stop at the first argument, so the flags documented after create are parsed here.
+	if args[0] == tokenCreate {
+		if err := t.parseCreateFlags(args[1:]); err != nil {
+			slog.Error("Error parsing create flags", slog.String(logging.KeyError, err.Error()))
+			return subcommands.ExitUsageError
+		}
+	}
+
+	v, err := utils.LoadConfig(t.configLocation)
+	if err != nil {
+		slog.Error("Error loading config", slog.String(logging.KeyError, err.Error()))
+		return subcommands.ExitFailure
+	}
+
+	db, err := storage.Connect(ctx, v)
+	if err != nil {
+		slog.Error("Error connecting to database", slog.String(logging.KeyError, err.Error()))
+		return subcommands.ExitFailure
+	}
+	defer db.Close()
+
+	r := repo.NewRepository(db)
+
+	switch args[0] {
+	case tokenCreate:
+		return t.create(r)
+	case tokenList:
+		tokens, err := r.GetAPITokens()
+		if err != nil {
+			slog.Error("Error getting tokens", slog.String(logging.KeyError, err.Error()))
+			return subcommands.ExitFailure
+		}
+
+		if err := printTokens(tokens); err != nil {
+			slog.Error("Error printing tokens", slog.String(logging.KeyError, err.Error()))
+			return subcommands.ExitFailure
+		}
+	case tokenRevoke:
+		if len(args) != 2 {
+			f.Usage()
+			return subcommands.ExitUsageError
+		}
+
+		id, err := strconv.Atoi(args[1])
+		if err != nil {
+			slog.Error("Token id must be a number", slog.String("id", args[1]))
+			return subcommands.ExitUsageError
+		}
+
+		if err := r.RevokeAPIToken(id, time.Now().UTC()); err != nil {
+			slog.Error("Error revoking token", slog.String(logging.KeyError, err.Error()))
+			return subcommands.ExitFailure
+		}
+		slog.Info("Token revoked", slog.Int("id", id))
+	default:
+		f.Usage()
+		return subcommands.ExitUsageError
+	}
+
+	return subcommands.ExitSuccess
+}
+
+// parseCreateFlags parses the flags given after create, they can also be given before it.
+func (t *TokenCmd) parseCreateFlags(args []string) error {
+	f := flag.NewFlagSet(tokenCreate, flag.ContinueOnError)
+	f.StringVar(&t.name, "name", t.name, "The name of the token to create")
+	f.StringVar(&t.scopes, "scopes", t.scopes, "The scopes of the token to create, separated by commas")
+	f.DurationVar(&t.expiresIn, "expires-in", t.expiresIn, "How long the token is valid for, it does not expire if not set")
+	if err := f.Parse(args); err != nil {
+		return err
+	}
+
+	if f.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments %s", strings.Join(f.Args(), " "))
+	}
+
+	return nil
+}
+
+// create creates a token and prints it.
+func (t *TokenCmd) create(r repo.Repository) subcommands.ExitStatus {
+	if t.name == "" {
+		slog.Error("A name is required to create a token")
+		return subcommands.ExitUsageError
+	}
+
+	scopes, err := auth.ParseScopes(t.scopes)
+	if err != nil {
+		slog.Error("Error parsing scopes", slog.String(logging.KeyError, err.Error()))
+		return subcommands.ExitUsageError
+	} else if len(scopes) == 0 {
+		slog.Error("At least one scope is required to create a token")
+		return subcommands.ExitUsageError
+	}
+
+	token, hash, err := auth.GenerateToken()
+	if err != nil {
+		slog.Error("Error generating token", slog.String(logging.KeyError, err.Error()))
+		return subcommands.ExitFailure
+	}
+
+	now := time.Now().UTC()
+	m := &models.ApiToken{
+		Name:      t.name,
+		TokenHash: hash,
+		Scopes:    scopes.String(),
+		CreatedAt: now,
+	}
+	if t.expiresIn > 0 {
+		m.ExpiresAt = *usql.NewNullTime(now.Add(t.expiresIn))
+	}
+
+	if err := r.SaveAPIToken(m); err != nil {
+		slog.Error("Error saving token", slog.String(logging.KeyError, err.Error()))
+		return subcommands.ExitFailure
+	}
+
+	slog.Info("Token created, it will not be shown again", slog.Int("id", m.Id), slog.String("name", m.Name))
+	fmt.Println(token)
+
+	return subcommands.ExitSuccess
+}
+
+// printTokens prints the tokens as a table.
+func printTokens(tokens []*models.ApiToken) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tSCOPES\tCREATED AT\tEXPIRES AT\tLAST USED AT\tREVOKED AT")
+
+	for _, t := range tokens {
+		fmt.Fprintf(
+			w,
+			"%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			t.Id,
+			t.Name,
+			t.Scopes,
+			t.CreatedAt.UTC().Format(time.RFC3339),
+			formatNullTime(t.ExpiresAt),
+			formatNullTime(t.LastUsedAt),
+			formatNullTime(t.RevokedAt),
+		)
+	}
+
+	return w.Flush()
+}
+
+// formatNullTime formats the time, or returns an empty string if it is not set.
+func formatNullTime(t usql.NullTime) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,23 @@
+package server
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenCmd_ParseCreateFlags(t *testing.T) {
+	tok := new(TokenCmd)
+	f := flag.NewFlagSet("token", flag.ContinueOnError)
+	tok.SetFlags(f)
+	require.NoError(t, f.Parse([]string{"-scopes", "reports:write", "create", "-name", "agent", "-expires-in", "24h"}))
+
+	require.NoError(t, tok.parseCreateFlags(f.Args()[1:]))
+	require.Equal(t, "agent", tok.name)
+	require.Equal(t, "reports:write", tok.scopes)
+	require.Equal(t, 24*time.Hour, tok.expiresIn)
+
+	require.Error(t, tok.parseCreateFlags([]string{"extra"}))
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/logging"
+	"github.com/jacobbrewer1/uhttp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	// metricHTTPRequestsTotal is the name of the counter of http requests.
+	metricHTTPRequestsTotal = "http_requests_total"
+)
+
+// httpMetrics are the metrics of the http requests. They are in the namespace of the binary serving them, so they are
+// created with the server rather than when the package is loaded.
+type httpMetrics struct {
+	// totalRequests is the total number of http requests.
+	totalRequests *prometheus.CounterVec
+
+	// requestDuration is the duration of the http request.
+	requestDuration *prometheus.HistogramVec
+
+	// requestSize is the size of the http request.
+	requestSize *prometheus.HistogramVec
+}
+
+// newHTTPMetrics registers the metrics of the http requests in the namespace. It panics if they are already registered,
+// so it is called once for each binary.
+func newHTTPMetrics(namespace string) *httpMetrics {
+	return &httpMetrics{
+		totalRequests: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:      metricHTTPRequestsTotal,
+				Namespace: namespace,
+				Help:      "Total number of http requests",
+			},
+			[]string{"path", "method", "status_code"},
+		),
+		requestDuration: promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:      "http_request_duration_seconds",
+				Namespace: namespace,
+				Help:      "Duration of the http request",
+			},
+			[]string{"path", "method", "status_code"},
+		),
+		requestSize: promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:      "http_request_size",
+				Namespace: namespace,
+				Help:      "Size of the http request",
+			},
+			[]string{"path", "method", "status_code"},
+		),
+	}
+}
+
+// middleware is run after the request is completed
+func (m *httpMetrics) middleware(w http.ResponseWriter, r *http.Request) {
+	cw, ok := w.(*uhttp.ResponseWriter)
+	if !ok {
+		cw = uhttp.NewResponseWriter(w)
+	}
+
+	path := ""
+	route := mux.CurrentRoute(r)
+	if route != nil { // The route may be nil if the request is not routed.
+		var err error
+		path, err = route.GetPathTemplate()
+		if err != nil {
+			// An error here is only returned if the route does not define a path.
+			slog.Error("Error getting path template", slog.String(logging.KeyError, err.Error()))
+			path = r.URL.Path // If the route does not define a path, use the URL path.
+		}
+	} else {
+		path = r.URL.Path // If the route is nil, use the URL path.
+	}
+
+	// Record the total number of requests.
+	m.totalRequests.WithLabelValues(path, r.Method, strconv.Itoa(cw.StatusCode())).Inc()
+
+	// Record the request duration.
+	m.requestDuration.WithLabelValues(path, r.Method, strconv.Itoa(cw.StatusCode())).Observe(cw.GetRequestDuration().Seconds())
+
+	// Record the request size.
+	m.requestSize.WithLabelValues(path, r.Method, strconv.Itoa(cw.StatusCode())).Observe(float64(r.ContentLength))
+}
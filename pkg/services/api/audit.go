@@ -0,0 +1,346 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jacobbrewer1/pagefilter"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/apis/specs/api"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/auth"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/logging"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+	repo "github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api"
+	"github.com/jacobbrewer1/uhttp"
+	"github.com/jacobbrewer1/utils"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+const (
+	// auditActionUpload is the action of a report uploaded to be ingested in the request.
+	auditActionUpload = "report.upload"
+
+	// auditActionQueue is the action of a report uploaded to be ingested by the workers.
+	auditActionQueue = "report.queue"
+
+	// auditActionFactsUpload is the action of the facts of a host uploaded.
+	auditActionFactsUpload = "facts.upload"
+)
+
+// auditRoutes are the actions of the state-changing routes, keyed by the method and the path template of the route.
+var auditRoutes = map[string]string{
+	http.MethodPost + " /reports":       auditActionUpload,
+	http.MethodPost + " /reports/queue": auditActionQueue,
+	http.MethodPost + " /facts/{host}":  auditActionFactsUpload,
+}
+
+// recordedKey is the context key of the flag set once the audit event of a request is recorded.
+type recordedKey struct{}
+
+// audit records the state-changing requests in the audit log before returning the response. It wraps the
+// authorisation, so the requests that are not authorised are recorded too. Every state-changing operation must be
+// recorded here, the read-only operations are passed on by the embedded interface.
+type audit struct {
+	api.ServerInterface
+
+	// r is the repository the audit events are stored in.
+	r repo.Repository
+
+	// trustForwardedFor is true if the IP address of the client is taken from the X-Forwarded-For header.
+	trustForwardedFor bool
+}
+
+type AuditOption func(a *audit)
+
+// WithAuditForwardedFor records the IP address of the client from the X-Forwarded-For header. It should only be used
+// behind a proxy that sets the header.
+func WithAuditForwardedFor() AuditOption {
+	return func(a *audit) {
+		a.trustForwardedFor = true
+	}
+}
+
+// NewAudit creates the audit log of the API, next is the service or its authorisation.
+func NewAudit(r repo.Repository, next api.ServerInterface, opts ...AuditOption) api.ServerInterface {
+	a := &audit{
+		ServerInterface: next,
+		r:               r,
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a
+}
+
+// AuditRejected records the state-changing requests that are answered before they reach the audit log of the API, such
+// as the uploads over the rate limit or the size limit of the body. It is used on the router before the middleware
+// rejecting them, the requests recorded by the audit log of the API are passed on.
+func AuditRejected(r repo.Repository, opts ...AuditOption) mux.MiddlewareFunc {
+	a := &audit{
+		r: r,
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			action := auditRouteAction(r)
+			if action == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			recorded := new(bool)
+			r, actor := a.withActor(r.WithContext(context.WithValue(r.Context(), recordedKey{}, recorded)))
+
+			cw := uhttp.NewResponseWriter(w)
+			next.ServeHTTP(cw, r)
+			if *recorded {
+				return
+			}
+
+			// The body is not read, so the target of an upload is not known.
+			event := a.event(r, actor, action, mux.Vars(r)["host"])
+			event.StatusCode = cw.StatusCode()
+			if event.StatusCode >= http.StatusBadRequest {
+				event.Detail = http.StatusText(event.StatusCode)
+			}
+
+			a.save(logging.LoggerFromRequest(r), event)
+		})
+	}
+}
+
+// auditRouteAction returns the action of the route of the request, empty if the route is not audited.
+func auditRouteAction(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return ""
+	}
+
+	path, err := route.GetPathTemplate()
+	if err != nil {
+		return ""
+	}
+
+	return auditRoutes[r.Method+" "+path]
+}
+
+func (a *audit) UploadReport(l *slog.Logger, r *http.Request, params api.UploadReportParams, body0 *api.UploadReportRequestBody) (*api.ReportUpload, error) {
+	r, actor := a.withActor(r)
+
+	resp, err := a.ServerInterface.UploadReport(l, r, params, body0)
+
+	target := ""
+	switch {
+	case resp != nil && resp.Report != nil:
+		target = resp.Report.Host
+	case resp != nil && resp.Summary != nil:
+		target = resp.Summary.Host
+	case body0 != nil:
+		target = reportTarget(body0.File)
+	}
+
+	a.record(l, r, actor, auditActionUpload, target, http.StatusCreated, err)
+
+	return resp, err
+}
+
+func (a *audit) QueueReport(l *slog.Logger, r *http.Request, body0 *api.QueueReportRequestBody) (*api.ReportQueueStatus, error) {
+	r, actor := a.withActor(r)
+
+	resp, err := a.ServerInterface.QueueReport(l, r, body0)
+
+	target := ""
+	if body0 != nil {
+		target = reportTarget(body0.File)
+	}
+
+	a.record(l, r, actor, auditActionQueue, target, http.StatusAccepted, err)
+
+	return resp, err
+}
+
+func (a *audit) UploadFacts(l *slog.Logger, r *http.Request, host string, body0 *api.UploadFactsRequestBody) (*api.HostFacts, error) {
+	r, actor := a.withActor(r)
+
+	resp, err := a.ServerInterface.UploadFacts(l, r, host, body0)
+
+	a.record(l, r, actor, auditActionFactsUpload, host, http.StatusOK, err)
+
+	return resp, err
+}
+
+// withActor returns the request with the actor in its context, the token is added to the actor once it is validated.
+func (a *audit) withActor(r *http.Request) (*http.Request, *auth.Actor) {
+	actor := &auth.Actor{
+		IP: clientIP(r, a.trustForwardedFor),
+	}
+
+	if cert := auth.ClientCertificate(r); cert != nil {
+		actor.Certificate = cert.Subject.CommonName
+	}
+
+	return r.WithContext(auth.WithActor(r.Context(), actor)), actor
+}
+
+// record saves the audit event of a request. The response is still returned if the event cannot be saved.
+func (a *audit) record(l *slog.Logger, r *http.Request, actor *auth.Actor, action, target string, successStatus int, err error) {
+	if recorded, ok := r.Context().Value(recordedKey{}).(*bool); ok {
+		*recorded = true
+	}
+
+	event := a.event(r, actor, action, target)
+	event.StatusCode = successStatus
+
+	if err != nil {
+		event.StatusCode = http.StatusInternalServerError
+		httpErr := new(uhttp.HTTPError)
+		if errors.As(err, &httpErr) {
+			event.StatusCode = httpErr.StatusCode()
+		}
+		event.Detail = err.Error()
+	}
+
+	a.save(l, event)
+}
+
+// event returns the audit event of a request, without its outcome.
+func (a *audit) event(r *http.Request, actor *auth.Actor, action, target string) *models.AuditEvent {
+	return &models.AuditEvent{
+		OccurredAt:       time.Now().UTC(),
+		RequestId:        uhttp.RequestIDFromContext(r.Context()),
+		ActorIp:          actor.IP,
+		ActorCertificate: actor.Certificate,
+		ActorToken:       actor.Token,
+		Action:           action,
+		Target:           target,
+	}
+}
+
+// save saves the audit event with the outcome of its status code.
+func (a *audit) save(l *slog.Logger, event *models.AuditEvent) {
+	event.Outcome = string(auditOutcome(event.StatusCode))
+
+	if err := a.r.SaveAuditEvent(event); err != nil {
+		l.Error("Error saving audit event",
+			slog.String(logging.KeyRequestID, event.RequestId),
+			slog.String("action", event.Action),
+			slog.String(logging.KeyError, err.Error()),
+		)
+	}
+}
+
+// reportTarget returns the host of the uploaded report, or the hash of the payload if it is not a report.
+func reportTarget(file *openapi_types.File) string {
+	if file == nil {
+		return ""
+	}
+
+	bts, err := file.Bytes()
+	if err != nil {
+		return ""
+	}
+
+	if host, err := parseReportHost(bts); err == nil {
+		return host
+	}
+
+	return utils.Sha256(bts)
+}
+
+// auditOutcome returns the outcome of a request with the status code.
+func auditOutcome(status int) api.AuditOutcome {
+	switch {
+	case status < http.StatusBadRequest:
+		return api.AuditOutcomesuccess
+	case status == http.StatusUnauthorized, status == http.StatusForbidden:
+		return api.AuditOutcomedenied
+	case status == http.StatusConflict:
+		return api.AuditOutcomeconflict
+	case status < http.StatusInternalServerError:
+		return api.AuditOutcomeinvalid
+	default:
+		return api.AuditOutcomeerror
+	}
+}
+
+func (s *service) GetAuditEvents(l *slog.Logger, r *http.Request, params api.GetAuditEventsParams) (*api.AuditEventResponse, error) {
+	paginationDetails, err := pagefilter.DetailsFromRequest(r)
+	if err != nil {
+		return nil, uhttp.NewHTTPError(http.StatusBadRequest, err, "failed to get pagination details")
+	}
+
+	filts := &repo.GetAuditEventsFilters{
+		Action: params.Action,
+		Actor:  params.Actor,
+		Target: params.Target,
+		From:   params.From,
+		To:     params.To,
+	}
+
+	if params.Outcome != nil {
+		filts.Outcome = utils.Ptr(string(*params.Outcome))
+	}
+
+	events, err := s.r.GetAuditEvents(paginationDetails, filts)
+	if err != nil {
+		switch {
+		case errors.Is(err, repo.ErrNoAuditEvents):
+			events = &pagefilter.PaginatedResponse[models.AuditEvent]{
+				Items: make([]*models.AuditEvent, 0),
+				Total: 0,
+			}
+		default:
+			return nil, uhttp.NewHTTPError(http.StatusInternalServerError, err, "error getting audit events")
+		}
+	}
+
+	respArray := make([]api.AuditEvent, len(events.Items))
+	for i, event := range events.Items {
+		respArray[i] = *s.modelAsApiAuditEvent(event)
+	}
+
+	resp := &api.AuditEventResponse{
+		Events: respArray,
+		Total:  events.Total,
+	}
+
+	return resp, nil
+}
+
+func (s *service) modelAsApiAuditEvent(event *models.AuditEvent) *api.AuditEvent {
+	resp := &api.AuditEvent{
+		Action: event.Action,
+		Actor: api.AuditActor{
+			Ip: event.ActorIp,
+		},
+		Id:         int64(event.Id),
+		OccurredAt: event.OccurredAt,
+		Outcome:    api.AuditOutcome(event.Outcome),
+		RequestId:  event.RequestId,
+		StatusCode: event.StatusCode,
+		Target:     event.Target,
+	}
+
+	if event.ActorCertificate != "" {
+		resp.Actor.Certificate = utils.Ptr(event.ActorCertificate)
+	}
+
+	if event.ActorToken != "" {
+		resp.Actor.Token = utils.Ptr(event.ActorToken)
+	}
+
+	if event.Detail != "" {
+		resp.Detail = utils.Ptr(event.Detail)
+	}
+
+	return resp
+}
@@ -0,0 +1,280 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/apis/specs/api"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/auth"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+	repo "github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api"
+	"github.com/jacobbrewer1/uhttp"
+	"github.com/jacobbrewer1/utils"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// uploadStub returns the response and error of an upload.
+type uploadStub struct {
+	api.ServerInterface
+
+	resp *api.ReportUpload
+	err  error
+}
+
+func (s *uploadStub) UploadReport(l *slog.Logger, r *http.Request, params api.UploadReportParams, body0 *api.UploadReportRequestBody) (*api.ReportUpload, error) {
+	return s.resp, s.err
+}
+
+func TestAudit_UploadReport(t *testing.T) {
+	const report = "host: web-1\nstatus: changed\n"
+
+	tests := []struct {
+		name        string
+		body        string
+		cert        string
+		resp        *api.ReportUpload
+		err         error
+		wantOutcome api.AuditOutcome
+		wantStatus  int
+		wantTarget  string
+		wantDetail  string
+	}{
+		{
+			name:        "uploaded",
+			body:        report,
+			resp:        &api.ReportUpload{Report: &api.Report{Host: "web-1"}},
+			wantOutcome: api.AuditOutcomesuccess,
+			wantStatus:  http.StatusCreated,
+			wantTarget:  "web-1",
+		},
+		{
+			name:        "duplicate",
+			body:        report,
+			cert:        "web-1",
+			err:         uhttp.NewHTTPError(http.StatusConflict, errors.New("report already exists")),
+			wantOutcome: api.AuditOutcomeconflict,
+			wantStatus:  http.StatusConflict,
+			wantTarget:  "web-1",
+			wantDetail:  "report already exists",
+		},
+		{
+			name:        "parse error",
+			body:        "status: changed\n",
+			err:         uhttp.NewHTTPError(http.StatusBadRequest, errors.New("failed to get 'host' from YAML")),
+			wantOutcome: api.AuditOutcomeinvalid,
+			wantStatus:  http.StatusBadRequest,
+			wantTarget:  utils.Sha256([]byte("status: changed\n")),
+			wantDetail:  "failed to get 'host' from YAML",
+		},
+		{
+			name:        "unexpected error",
+			body:        report,
+			err:         errors.New("connection refused"),
+			wantOutcome: api.AuditOutcomeerror,
+			wantStatus:  http.StatusInternalServerError,
+			wantTarget:  "web-1",
+			wantDetail:  "connection refused",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *models.AuditEvent
+			r := repo.NewMockRepository(t)
+			r.On("SaveAuditEvent", mock.AnythingOfType("*models.AuditEvent")).Run(func(args mock.Arguments) {
+				got = args.Get(0).(*models.AuditEvent)
+			}).Return(nil).Once()
+
+			a := NewAudit(r, &uploadStub{resp: tt.resp, err: tt.err})
+
+			req := httptest.NewRequest(http.MethodPost, "/reports", nil)
+			req.RemoteAddr = "10.0.0.1:1234"
+			if tt.cert != "" {
+				req.TLS = &tls.ConnectionState{
+					VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: tt.cert}}}},
+				}
+			}
+
+			body := &api.UploadReportRequestBody{File: new(openapi_types.File)}
+			body.File.InitFromBytes([]byte(tt.body), "report.yaml")
+
+			_, err := a.UploadReport(slog.Default(), req, api.UploadReportParams{}, body)
+			require.Equal(t, tt.err, err)
+
+			require.NotNil(t, got)
+			require.Equal(t, auditActionUpload, got.Action)
+			require.Equal(t, string(tt.wantOutcome), got.Outcome)
+			require.Equal(t, tt.wantStatus, got.StatusCode)
+			require.Equal(t, tt.wantTarget, got.Target)
+			require.Equal(t, tt.wantDetail, got.Detail)
+			require.Equal(t, "10.0.0.1", got.ActorIp)
+			require.Equal(t, tt.cert, got.ActorCertificate)
+		})
+	}
+}
+
+func TestAudit_Token(t *testing.T) {
+	const token = auth.TokenPrefix + "test"
+
+	tests := []struct {
+		name        string
+		scopes      string
+		wantOutcome api.AuditOutcome
+	}{
+		{
+			name:        "authorised",
+			scopes:      "reports:write",
+			wantOutcome: api.AuditOutcomesuccess,
+		},
+		{
+			name:        "missing scope",
+			scopes:      "reports:read",
+			wantOutcome: api.AuditOutcomedenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *models.AuditEvent
+			r := repo.NewMockRepository(t)
+			r.On("GetAPITokenByHash", auth.HashToken(token)).Return(&models.ApiToken{Id: 1, Name: "ci", Scopes: tt.scopes}, nil).Once()
+			r.On("TouchAPIToken", 1, mock.AnythingOfType("time.Time")).Return(nil).Maybe()
+			r.On("SaveAuditEvent", mock.AnythingOfType("*models.AuditEvent")).Run(func(args mock.Arguments) {
+				got = args.Get(0).(*models.AuditEvent)
+			}).Return(nil).Once()
+
+			stub := &uploadStub{resp: &api.ReportUpload{Report: &api.Report{Host: "web-1"}}}
+			a := NewAudit(r, NewAuthz(r, stub))
+
+			req := httptest.NewRequest(http.MethodPost, "/reports", nil)
+			req = req.WithContext(uhttp.AuthToContext(req.Context(), "Bearer "+token))
+
+			body := &api.UploadReportRequestBody{File: new(openapi_types.File)}
+			body.File.InitFromBytes([]byte("host: web-1\n"), "report.yaml")
+
+			_, _ = a.UploadReport(slog.Default(), req, api.UploadReportParams{}, body)
+
+			require.NotNil(t, got)
+			require.Equal(t, "ci", got.ActorToken)
+			require.Equal(t, string(tt.wantOutcome), got.Outcome)
+		})
+	}
+}
+
+func TestAuditRejected(t *testing.T) {
+	tooMany := func(w http.ResponseWriter, r *http.Request) error {
+		return uhttp.NewHTTPError(http.StatusTooManyRequests, errors.New("rate limit exceeded"))
+	}
+
+	tests := []struct {
+		name          string
+		path          string
+		body          string
+		contentLength int64
+		limiter       api.RateLimiterFunc
+		wantAction    string
+		wantStatus    int
+		wantOutcome   api.AuditOutcome
+		wantDetail    string
+	}{
+		{
+			name:          "rate limited",
+			path:          "/reports",
+			body:          "a",
+			contentLength: 1,
+			limiter:       tooMany,
+			wantAction:    auditActionUpload,
+			wantStatus:    http.StatusTooManyRequests,
+			wantOutcome:   api.AuditOutcomeinvalid,
+			wantDetail:    "Too Many Requests",
+		},
+		{
+			name:          "rate limited queue",
+			path:          "/reports/queue",
+			body:          "a",
+			contentLength: 1,
+			limiter:       tooMany,
+			wantAction:    auditActionQueue,
+			wantStatus:    http.StatusTooManyRequests,
+			wantOutcome:   api.AuditOutcomeinvalid,
+			wantDetail:    "Too Many Requests",
+		},
+		{
+			name:          "content length too large",
+			path:          "/reports",
+			body:          strings.Repeat("a", 32),
+			contentLength: 32,
+			wantAction:    auditActionUpload,
+			wantStatus:    http.StatusRequestEntityTooLarge,
+			wantOutcome:   api.AuditOutcomeinvalid,
+			wantDetail:    "Request Entity Too Large",
+		},
+		{
+			name:          "body too large",
+			path:          "/reports",
+			body:          strings.Repeat("a", 32),
+			contentLength: -1,
+			wantAction:    auditActionUpload,
+			wantStatus:    http.StatusRequestEntityTooLarge,
+			wantOutcome:   api.AuditOutcomeinvalid,
+			wantDetail:    "Request Entity Too Large",
+		},
+		{
+			name:          "recorded by the api",
+			path:          "/reports",
+			body:          "a",
+			contentLength: 1,
+			wantAction:    auditActionUpload,
+			wantStatus:    http.StatusConflict,
+			wantOutcome:   api.AuditOutcomeconflict,
+			wantDetail:    "report already exists",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *models.AuditEvent
+			r := repo.NewMockRepository(t)
+			r.On("SaveAuditEvent", mock.AnythingOfType("*models.AuditEvent")).Run(func(args mock.Arguments) {
+				got = args.Get(0).(*models.AuditEvent)
+			}).Return(nil).Once()
+
+			opts := make([]api.ServerOption, 0)
+			if tt.limiter != nil {
+				opts = append(opts, api.WithRateLimiter(tt.limiter))
+			}
+
+			router := mux.NewRouter()
+			router.Use(AuditRejected(r))
+			router.Use(LimitRequestBody(16))
+			api.RegisterHandlers(router, NewAudit(r, &uploadStub{
+				err: uhttp.NewHTTPError(http.StatusConflict, errors.New("report already exists")),
+			}), opts...)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set(uhttp.HeaderContentType, "application/x-www-form-urlencoded")
+			req.ContentLength = tt.contentLength
+			req.RemoteAddr = "10.0.0.1:1234"
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			require.Equal(t, tt.wantStatus, w.Code)
+
+			require.NotNil(t, got)
+			require.Equal(t, tt.wantAction, got.Action)
+			require.Equal(t, tt.wantStatus, got.StatusCode)
+			require.Equal(t, string(tt.wantOutcome), got.Outcome)
+			require.Equal(t, tt.wantDetail, got.Detail)
+			require.Equal(t, "10.0.0.1", got.ActorIp)
+		})
+	}
+}
@@ -0,0 +1,299 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/jacobbrewer1/puppet-reporter/pkg/apis/specs/api"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/auth"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/logging"
+	repo "github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api"
+	"github.com/jacobbrewer1/uhttp"
+)
+
+const (
+	// tokenTouchInterval is how often the last used time of a token is updated, so every request does not write to
+	// the database.
+	tokenTouchInterval = time.Minute
+)
+
+// authz checks the API token of each request has the scope of the operation before passing the request on.
+type authz struct {
+	// r is the repository the tokens are stored in.
+	r repo.Repository
+
+	// next is the service the authorised requests are passed on to.
+	next api.ServerInterface
+
+	// certUploads is true if a verified client certificate authorises an upload without a token.
+	certUploads bool
+
+	// acl decides which environments each token can read, nil if every token can read every environment.
+	acl *auth.ACL
+}
+
+type AuthzOption func(a *authz)
+
+// WithCertificateUploads lets the clients upload reports with a verified client certificate instead of a token. The
+// Puppet agents cannot send a token, but they have a certificate signed by the Puppet CA.
+func WithCertificateUploads() AuthzOption {
+	return func(a *authz) {
+		a.certUploads = true
+	}
+}
+
+// WithACL restricts the environments each token can read. The admin tokens can read every environment.
+func WithACL(acl *auth.ACL) AuthzOption {
+	return func(a *authz) {
+		a.acl = acl
+	}
+}
+
+// NewAuthz creates the authorisation of the API, next is the service. It is wrapped by the audit log rather than
+// registered with api.WithAuthorization, as the generated server calls the authorisation in place of the handler and
+// the uploads it rejects would not be audited.
+func NewAuthz(r repo.Repository, next api.ServerInterface, opts ...AuthzOption) api.ServerInterface {
+	a := &authz{
+		r:    r,
+		next: next,
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a
+}
+
+// authorize returns an error if the request does not have a valid token with the scope, or a client certificate if
+// it is an upload and uploads with a certificate are allowed. The returned request has the access of the token.
+func (a *authz) authorize(l *slog.Logger, r *http.Request, scope auth.Scope) (*http.Request, error) {
+	if a.certUploads && scope == auth.ScopeReportsWrite && auth.ClientCertificate(r) != nil {
+		return r, nil
+	}
+
+	token, ok := auth.BearerToken(uhttp.AuthHeaderFromContext(r.Context()))
+	if !ok {
+		return nil, uhttp.NewHTTPError(http.StatusUnauthorized, errors.New("missing bearer token"))
+	}
+
+	t, err := a.r.GetAPITokenByHash(auth.HashToken(token))
+	if err != nil {
+		switch {
+		case errors.Is(err, repo.ErrAPITokenNotFound):
+			return nil, uhttp.NewHTTPError(http.StatusUnauthorized, errors.New("invalid token"))
+		default:
+			l.Error("Error getting api token", slog.String(logging.KeyError, err.Error()))
+			return nil, uhttp.NewHTTPError(http.StatusInternalServerError, err)
+		}
+	}
+
+	// The token is recorded even if it is not allowed, so the audit log shows which token was refused.
+	if actor := auth.ActorFromContext(r.Context()); actor != nil {
+		actor.Token = t.Name
+	}
+
+	now := time.Now().UTC()
+	switch {
+	case t.RevokedAt.Valid:
+		return nil, uhttp.NewHTTPError(http.StatusUnauthorized, errors.New("token has been revoked"))
+	case t.ExpiresAt.Valid && !now.Before(t.ExpiresAt.Time):
+		return nil, uhttp.NewHTTPError(http.StatusUnauthorized, errors.New("token has expired"))
+	}
+
+	scopes, err := auth.ParseScopes(t.Scopes)
+	if err != nil {
+		l.Error("Error parsing api token scopes", slog.Int("token_id", t.Id), slog.String(logging.KeyError, err.Error()))
+		return nil, uhttp.NewHTTPError(http.StatusInternalServerError, err)
+	} else if !scopes.Has(scope) {
+		return nil, uhttp.NewHTTPError(http.StatusForbidden, fmt.Errorf("token does not have the %s scope", scope))
+	}
+
+	if !t.LastUsedAt.Valid || now.Sub(t.LastUsedAt.Time) >= tokenTouchInterval {
+		// The request is still allowed if the last used time cannot be recorded.
+		if err := a.r.TouchAPIToken(t.Id, now); err != nil {
+			l.Error("Error recording api token use", slog.Int("token_id", t.Id), slog.String(logging.KeyError, err.Error()))
+		}
+	}
+
+	if a.acl != nil {
+		access := a.acl.TokenAccess(t.Name)
+		if scopes.Has(auth.ScopeAdmin) {
+			access = auth.Unrestricted(access.Identity)
+		}
+		r = r.WithContext(auth.WithAccess(r.Context(), access))
+	}
+
+	return r, nil
+}
+
+func (a *authz) GetAuditEvents(l *slog.Logger, r *http.Request, params api.GetAuditEventsParams) (*api.AuditEventResponse, error) {
+	r, err := a.authorize(l, r, auth.ScopeAdmin)
+	if err != nil {
+		return nil, err
+	}
+	return a.next.GetAuditEvents(l, r, params)
+}
+
+func (a *authz) UploadFacts(l *slog.Logger, r *http.Request, host string, body0 *api.UploadFactsRequestBody) (*api.HostFacts, error) {
+	r, err := a.authorize(l, r, auth.ScopeReportsWrite)
+	if err != nil {
+		return nil, err
+	}
+	return a.next.UploadFacts(l, r, host, body0)
+}
+
+func (a *authz) GetFacts(l *slog.Logger, r *http.Request, host string) (*api.HostFacts, error) {
+	r, err := a.authorize(l, r, auth.ScopeReportsRead)
+	if err != nil {
+		return nil, err
+	}
+	return a.next.GetFacts(l, r, host)
+}
+
+func (a *authz) GetFactHistory(l *slog.Logger, r *http.Request, host string, params api.GetFactHistoryParams) (*api.FactHistoryResponse, error) {
+	r, err := a.authorize(l, r, auth.ScopeReportsRead)
+	if err != nil {
+		return nil, err
+	}
+	return a.next.GetFactHistory(l, r, host, params)
+}
+
+func (a *authz) QueryPDBNodes(l *slog.Logger, r *http.Request, params api.QueryPDBNodesParams) (*api.PdbResults, error) {
+	r, err := a.authorize(l, r, auth.ScopeReportsRead)
+	if err != nil {
+		return nil, err
+	}
+	return a.next.QueryPDBNodes(l, r, params)
+}
+
+func (a *authz) QueryPDBReports(l *slog.Logger, r *http.Request, params api.QueryPDBReportsParams) (*api.PdbResults, error) {
+	r, err := a.authorize(l, r, auth.ScopeReportsRead)
+	if err != nil {
+		return nil, err
+	}
+	return a.next.QueryPDBReports(l, r, params)
+}
+
+func (a *authz) QueryPDBEvents(l *slog.Logger, r *http.Request, params api.QueryPDBEventsParams) (*api.PdbResults, error) {
+	r, err := a.authorize(l, r, auth.ScopeReportsRead)
+	if err != nil {
+		return nil, err
+	}
+	return a.next.QueryPDBEvents(l, r, params)
+}
+
+func (a *authz) QueryPDBResources(l *slog.Logger, r *http.Request, params api.QueryPDBResourcesParams) (*api.PdbResults, error) {
+	r, err := a.authorize(l, r, auth.ScopeReportsRead)
+	if err != nil {
+		return nil, err
+	}
+	return a.next.QueryPDBResources(l, r, params)
+}
+
+func (a *authz) GetIngestionFailures(l *slog.Logger, r *http.Request, params api.GetIngestionFailuresParams) (*api.IngestionFailureResponse, error) {
+	r, err := a.authorize(l, r, auth.ScopeAdmin)
+	if err != nil {
+		return nil, err
+	}
+	return a.next.GetIngestionFailures(l, r, params)
+}
+
+func (a *authz) GetIngestionFailure(l *slog.Logger, r *http.Request, id int64) (*api.IngestionFailure, error) {
+	r, err := a.authorize(l, r, auth.ScopeAdmin)
+	if err != nil {
+		return nil, err
+	}
+	return a.next.GetIngestionFailure(l, r, id)
+}
+
+func (a *authz) GetIngestionFailurePayload(l *slog.Logger, r *http.Request, id int64) ([]byte, error) {
+	r, err := a.authorize(l, r, auth.ScopeAdmin)
+	if err != nil {
+		return nil, err
+	}
+	return a.next.GetIngestionFailurePayload(l, r, id)
+}
+
+func (a *authz) GetReports(l *slog.Logger, r *http.Request, params api.GetReportsParams) (*api.ReportResponse, error) {
+	r, err := a.authorize(l, r, auth.ScopeReportsRead)
+	if err != nil {
+		return nil, err
+	}
+	return a.next.GetReports(l, r, params)
+}
+
+func (a *authz) StreamGetReports(l *slog.Logger, r *http.Request, params api.GetReportsParams) (api.Stream, error) {
+	r, err := a.authorize(l, r, auth.ScopeReportsRead)
+	if err != nil {
+		return nil, err
+	}
+	return a.next.StreamGetReports(l, r, params)
+}
+
+func (a *authz) UploadReport(l *slog.Logger, r *http.Request, params api.UploadReportParams, body0 *api.UploadReportRequestBody) (*api.ReportUpload, error) {
+	r, err := a.authorize(l, r, auth.ScopeReportsWrite)
+	if err != nil {
+		return nil, err
+	}
+	return a.next.UploadReport(l, r, params, body0)
+}
+
+func (a *authz) QueueReport(l *slog.Logger, r *http.Request, body0 *api.QueueReportRequestBody) (*api.ReportQueueStatus, error) {
+	r, err := a.authorize(l, r, auth.ScopeReportsWrite)
+	if err != nil {
+		return nil, err
+	}
+	return a.next.QueueReport(l, r, body0)
+}
+
+func (a *authz) GetQueuedReport(l *slog.Logger, r *http.Request, id int64) (*api.ReportQueueStatus, error) {
+	r, err := a.authorize(l, r, auth.ScopeReportsRead)
+	if err != nil {
+		return nil, err
+	}
+	return a.next.GetQueuedReport(l, r, id)
+}
+
+func (a *authz) GetReportStream(l *slog.Logger, r *http.Request, params api.GetReportStreamParams) ([]byte, error) {
+	r, err := a.authorize(l, r, auth.ScopeReportsRead)
+	if err != nil {
+		return nil, err
+	}
+	return a.next.GetReportStream(l, r, params)
+}
+
+func (a *authz) StreamGetReportStream(l *slog.Logger, r *http.Request, params api.GetReportStreamParams) (api.Stream, error) {
+	r, err := a.authorize(l, r, auth.ScopeReportsRead)
+	if err != nil {
+		return nil, err
+	}
+	return a.next.StreamGetReportStream(l, r, params)
+}
+
+func (a *authz) GetReport(l *slog.Logger, r *http.Request, hash string, params api.GetReportParams) (*api.ReportDetails, error) {
+	r, err := a.authorize(l, r, auth.ScopeReportsRead)
+	if err != nil {
+		return nil, err
+	}
+	return a.next.GetReport(l, r, hash, params)
+}
+
+func (a *authz) StreamGetReport(l *slog.Logger, r *http.Request, hash string, params api.GetReportParams) (api.Stream, error) {
+	r, err := a.authorize(l, r, auth.ScopeReportsRead)
+	if err != nil {
+		return nil, err
+	}
+	return a.next.StreamGetReport(l, r, hash, params)
+}
+
+func (a *authz) GetFlappingStats(l *slog.Logger, r *http.Request, params api.GetFlappingStatsParams) (*api.FlappingStats, error) {
+	r, err := a.authorize(l, r, auth.ScopeReportsRead)
+	if err != nil {
+		return nil, err
+	}
+	return a.next.GetFlappingStats(l, r, params)
+}
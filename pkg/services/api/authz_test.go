@@ -0,0 +1,225 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jacobbrewer1/goschema/usql"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/auth"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+	repo "github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api"
+	"github.com/jacobbrewer1/uhttp"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthz_Authorize(t *testing.T) {
+	const token = auth.TokenPrefix + "test"
+
+	tests := []struct {
+		name       string
+		header     string
+		apiToken   *models.ApiToken
+		lookupErr  error
+		scope      auth.Scope
+		wantStatus int
+		wantTouch  bool
+	}{
+		{
+			name:       "missing token",
+			header:     "",
+			scope:      auth.ScopeReportsRead,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "not a bearer token",
+			header:     "Basic dXNlcjpwYXNz",
+			scope:      auth.ScopeReportsRead,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "unknown token",
+			header:     "Bearer " + token,
+			lookupErr:  repo.ErrAPITokenNotFound,
+			scope:      auth.ScopeReportsRead,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "database error",
+			header:     "Bearer " + token,
+			lookupErr:  errors.New("connection refused"),
+			scope:      auth.ScopeReportsRead,
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:   "revoked token",
+			header: "Bearer " + token,
+			apiToken: &models.ApiToken{
+				Id:        1,
+				Scopes:    "reports:read",
+				RevokedAt: *usql.NewNullTime(time.Now().Add(-time.Hour)),
+			},
+			scope:      auth.ScopeReportsRead,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "expired token",
+			header: "Bearer " + token,
+			apiToken: &models.ApiToken{
+				Id:        1,
+				Scopes:    "reports:read",
+				ExpiresAt: *usql.NewNullTime(time.Now().Add(-time.Hour)),
+			},
+			scope:      auth.ScopeReportsRead,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "missing scope",
+			header: "Bearer " + token,
+			apiToken: &models.ApiToken{
+				Id:     1,
+				Scopes: "reports:read",
+			},
+			scope:      auth.ScopeReportsWrite,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "scope granted",
+			header: "Bearer " + token,
+			apiToken: &models.ApiToken{
+				Id:        1,
+				Scopes:    "reports:read reports:write",
+				ExpiresAt: *usql.NewNullTime(time.Now().Add(time.Hour)),
+			},
+			scope:     auth.ScopeReportsWrite,
+			wantTouch: true,
+		},
+		{
+			name:   "admin grants every scope",
+			header: "bearer " + token,
+			apiToken: &models.ApiToken{
+				Id:     1,
+				Scopes: "admin",
+			},
+			scope:     auth.ScopeReportsRead,
+			wantTouch: true,
+		},
+		{
+			name:   "recently used token is not touched",
+			header: "Bearer " + token,
+			apiToken: &models.ApiToken{
+				Id:         1,
+				Scopes:     "reports:read",
+				LastUsedAt: *usql.NewNullTime(time.Now().UTC()),
+			},
+			scope: auth.ScopeReportsRead,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := repo.NewMockRepository(t)
+			if tt.apiToken != nil || tt.lookupErr != nil {
+				r.On("GetAPITokenByHash", auth.HashToken(token)).Return(tt.apiToken, tt.lookupErr).Once()
+			}
+			if tt.wantTouch {
+				r.On("TouchAPIToken", tt.apiToken.Id, mock.AnythingOfType("time.Time")).Return(nil).Once()
+			}
+
+			a := &authz{r: r}
+
+			req := httptest.NewRequest(http.MethodGet, "/reports", nil)
+			req = req.WithContext(uhttp.AuthToContext(req.Context(), tt.header))
+
+			_, err := a.authorize(slog.Default(), req, tt.scope)
+			if tt.wantStatus == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			httpErr := new(uhttp.HTTPError)
+			require.ErrorAs(t, err, &httpErr)
+			require.Equal(t, tt.wantStatus, httpErr.StatusCode())
+		})
+	}
+}
+
+func TestAuthz_CertificateUploads(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/reports", nil)
+	req.TLS = &tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: "web-1"}}}},
+	}
+
+	// The repository is not used, the certificate authorises the upload.
+	a := &authz{r: repo.NewMockRepository(t), certUploads: true}
+	_, err := a.authorize(slog.Default(), req, auth.ScopeReportsWrite)
+	require.NoError(t, err)
+
+	// A certificate does not authorise reading the reports.
+	_, err = a.authorize(slog.Default(), req, auth.ScopeReportsRead)
+	httpErr := new(uhttp.HTTPError)
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusUnauthorized, httpErr.StatusCode())
+}
+
+func TestAuthz_ACL(t *testing.T) {
+	const token = auth.TokenPrefix + "test"
+
+	acl, err := auth.NewACL(
+		map[string][]string{"prod": {"production"}},
+		map[string][]string{"token:ci": {"prod"}},
+		false,
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		apiToken  *models.ApiToken
+		wantEnvs  []string
+		wantLimit bool
+	}{
+		{
+			name:      "token with a role",
+			apiToken:  &models.ApiToken{Id: 1, Name: "ci", Scopes: "reports:read"},
+			wantEnvs:  []string{"PRODUCTION"},
+			wantLimit: true,
+		},
+		{
+			name:      "token without a role",
+			apiToken:  &models.ApiToken{Id: 2, Name: "other", Scopes: "reports:read"},
+			wantEnvs:  []string{},
+			wantLimit: true,
+		},
+		{
+			name:     "admin token",
+			apiToken: &models.ApiToken{Id: 3, Name: "other", Scopes: "admin"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := repo.NewMockRepository(t)
+			r.On("GetAPITokenByHash", auth.HashToken(token)).Return(tt.apiToken, nil).Once()
+			r.On("TouchAPIToken", tt.apiToken.Id, mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+			a := &authz{r: r, acl: acl}
+
+			req := httptest.NewRequest(http.MethodGet, "/reports", nil)
+			req = req.WithContext(uhttp.AuthToContext(req.Context(), "Bearer "+token))
+
+			req, err := a.authorize(slog.Default(), req, auth.ScopeReportsRead)
+			require.NoError(t, err)
+
+			envs, limited := auth.AccessFromContext(req.Context()).Environments()
+			require.Equal(t, tt.wantLimit, limited)
+			require.Equal(t, tt.wantEnvs, envs)
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jacobbrewer1/puppet-reporter/pkg/apis/specs/api"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/auth"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+	repo "github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api"
+	"github.com/jacobbrewer1/uhttp"
+)
+
+const (
+	contentTypeCSV    = "text/csv"
+	contentTypeNDJSON = "application/x-ndjson"
+)
+
+// exportFormats are the formats other than JSON, keyed by the content types they can be asked for with in the Accept
+// header.
+var exportFormats = map[string]api.QueryFormat{
+	contentTypeCSV:          api.QueryFormatcsv,
+	contentTypeNDJSON:       api.QueryFormatndjson,
+	"application/ndjson":    api.QueryFormatndjson,
+	"application/jsonlines": api.QueryFormatndjson,
+}
+
+func (s *service) StreamGetReports(l *slog.Logger, r *http.Request, params api.GetReportsParams) (api.Stream, error) {
+	format, err := exportFormat(r, (*api.QueryFormat)(params.Format))
+	if err != nil {
+		return nil, uhttp.NewHTTPError(http.StatusBadRequest, err, "invalid format")
+	} else if format == api.QueryFormatjson {
+		return nil, nil
+	}
+
+	filts, err := s.getReportsFilters(auth.AccessFromContext(r.Context()), &params)
+	if err != nil {
+		return nil, uhttp.NewHTTPError(http.StatusBadRequest, err, "failed to parse filters")
+	}
+
+	return &exportStream[*models.Report]{
+		format: format,
+		header: []string{
+			"id", "hash", "host", "environment", "status", "puppet_version", "executed_at", "runtime_seconds",
+			"runtime_regression", "total_resources", "total_changed", "total_failed", "total_skipped",
+		},
+		row: func(report *models.Report) []string {
+			return []string{
+				strconv.Itoa(report.Id),
+				report.Hash,
+				report.Host,
+				report.Environment,
+				strings.ToLower(string(report.State)),
+				strconv.FormatFloat(report.PuppetVersion, 'f', -1, 64),
+				report.ExecutedAt.UTC().Format(time.RFC3339),
+				strconv.Itoa(report.Runtime),
+				strconv.FormatBool(report.RuntimeRegression),
+				strconv.Itoa(report.Total),
+				strconv.Itoa(report.Changed),
+				strconv.Itoa(report.Failed),
+				strconv.Itoa(report.Skipped),
+			}
+		},
+		object: func(report *models.Report) any {
+			return s.modelAsApiReport(report)
+		},
+		each: func(fn func(*models.Report) error) error {
+			return s.r.StreamReports(filts, fn)
+		},
+	}, nil
+}
+
+func (s *service) StreamGetReport(l *slog.Logger, r *http.Request, hash string, params api.GetReportParams) (api.Stream, error) {
+	format, err := exportFormat(r, (*api.QueryFormat)(params.Format))
+	if err != nil {
+		return nil, uhttp.NewHTTPError(http.StatusBadRequest, err, "invalid format")
+	} else if format == api.QueryFormatjson {
+		return nil, nil
+	}
+
+	report, err := s.r.GetReportByHash(hash)
+	if err == nil && !auth.AccessFromContext(r.Context()).AllowsEnvironment(report.Environment) {
+		err = repo.ErrReportNotFound
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, repo.ErrReportNotFound):
+			return nil, uhttp.NewHTTPError(http.StatusNotFound, err, "report not found", fmt.Sprintf("hash: %s", hash))
+		default:
+			return nil, uhttp.NewHTTPError(http.StatusInternalServerError, err, "failed to get report", fmt.Sprintf("hash: %s", hash))
+		}
+	}
+
+	// The resources are exported with the report they are from, so the exports of several reports can be joined.
+	return &exportStream[*models.Resource]{
+		format: format,
+		header: []string{"report_hash", "host", "environment", "executed_at", "status", "type", "name", "file", "line"},
+		row: func(resource *models.Resource) []string {
+			return []string{
+				report.Hash,
+				report.Host,
+				report.Environment,
+				report.ExecutedAt.UTC().Format(time.RFC3339),
+				strings.ToLower(string(resource.Status)),
+				resource.Type,
+				resource.Name,
+				resource.File,
+				strconv.Itoa(resource.Line),
+			}
+		},
+		object: func(resource *models.Resource) any {
+			return struct {
+				ReportHash string `json:"report_hash"`
+				*api.Resource
+			}{
+				ReportHash: report.Hash,
+				Resource:   s.modelAsApiResource(resource),
+			}
+		},
+		each: func(fn func(*models.Resource) error) error {
+			return s.r.StreamResourcesByReportID(report.Id, fn)
+		},
+	}, nil
+}
+
+// exportFormat returns the format the client asked for, with the format parameter or else the Accept header. The
+// response is JSON if it asked for neither CSV nor NDJSON.
+func exportFormat(r *http.Request, format *api.QueryFormat) (api.QueryFormat, error) {
+	if format != nil {
+		if !format.IsValid() {
+			return "", fmt.Errorf("format %q is not json, csv or ndjson", *format)
+		}
+		return *format, nil
+	}
+
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		if f, ok := exportFormats[mediaType]; ok {
+			return f, nil
+		}
+	}
+
+	return api.QueryFormatjson, nil
+}
+
+// exportStream streams the items each reads from the database, as a CSV row or a line of JSON each.
+type exportStream[T any] struct {
+	format api.QueryFormat
+
+	// header is the header row of the CSV.
+	header []string
+
+	// row returns the CSV row of the item, in the order of the header.
+	row func(T) []string
+
+	// object returns the JSON object of the item.
+	object func(T) any
+
+	// each calls fn with each item as it is read from the database.
+	each func(fn func(T) error) error
+}
+
+func (e *exportStream[T]) ContentType() string {
+	if e.format == api.QueryFormatcsv {
+		return contentTypeCSV + "; charset=utf-8"
+	}
+	return contentTypeNDJSON
+}
+
+func (e *exportStream[T]) Encode(w io.Writer) error {
+	if e.format == api.QueryFormatcsv {
+		cw := csv.NewWriter(w)
+		if err := cw.Write(e.header); err != nil {
+			return fmt.Errorf("write header: %w", err)
+		}
+
+		// The writer is buffered and flushes as it fills, so the rows are not held in memory.
+		if err := e.each(func(item T) error {
+			row := e.row(item)
+			for i := range row {
+				row[i] = csvCell(row[i])
+			}
+			return cw.Write(row)
+		}); err != nil {
+			return err
+		}
+
+		cw.Flush()
+		return cw.Error()
+	}
+
+	enc := json.NewEncoder(w)
+	return e.each(func(item T) error {
+		return enc.Encode(e.object(item))
+	})
+}
+
+// csvCell returns the value as a CSV cell that a spreadsheet shows as text. The hosts, resource names and files are as
+// the agent sent them, and a spreadsheet would run a cell starting with one of these as a formula when the export is
+// opened, so such a cell is prefixed with a quote.
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
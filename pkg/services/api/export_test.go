@@ -0,0 +1,157 @@
+package api
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jacobbrewer1/puppet-reporter/pkg/apis/specs/api"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+	repo "github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		accept  string
+		want    api.QueryFormat
+		wantErr bool
+	}{
+		{
+			name: "default",
+			want: api.QueryFormatjson,
+		},
+		{
+			name:   "parameter",
+			format: "csv",
+			accept: "application/x-ndjson",
+			want:   api.QueryFormatcsv,
+		},
+		{
+			name:   "accept",
+			accept: "application/json;q=0.5, text/csv; charset=utf-8",
+			want:   api.QueryFormatcsv,
+		},
+		{
+			name:   "accept anything",
+			accept: "*/*",
+			want:   api.QueryFormatjson,
+		},
+		{
+			name:    "unknown parameter",
+			format:  "xlsx",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/reports", nil)
+			r.Header.Set("Accept", tt.accept)
+
+			var format *api.QueryFormat
+			if tt.format != "" {
+				format = (*api.QueryFormat)(&tt.format)
+			}
+
+			got, err := exportFormat(r, format)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCSVCell(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "empty", value: "", want: ""},
+		{name: "text", value: "web-1", want: "web-1"},
+		{name: "formula", value: "=HYPERLINK(\"http://example.com\")", want: "'=HYPERLINK(\"http://example.com\")"},
+		{name: "plus", value: "+1", want: "'+1"},
+		{name: "minus", value: "-1+2", want: "'-1+2"},
+		{name: "at", value: "@SUM(A1)", want: "'@SUM(A1)"},
+		{name: "tab", value: "\t=1", want: "'\t=1"},
+		{name: "carriage return", value: "\r=1", want: "'\r=1"},
+		{name: "not at the start", value: "a=1", want: "a=1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, csvCell(tt.value))
+		})
+	}
+}
+
+func TestService_StreamGetReports(t *testing.T) {
+	reports := []*models.Report{
+		{Id: 1, Hash: "abc", Host: "web-1", PuppetVersion: 8.1, Environment: "production", State: "failed", ExecutedAt: time.Date(2024, 12, 24, 13, 0, 0, 0, time.UTC), Runtime: 30, Total: 10, Failed: 1},
+		{Id: 2, Hash: "def", Host: "web, \"2\"", PuppetVersion: 8, Environment: "production", State: "changed", ExecutedAt: time.Date(2024, 12, 24, 14, 0, 0, 0, time.UTC), Runtime: 12, Total: 10, Changed: 2},
+		{Id: 3, Hash: "ghi", Host: "=1+2", PuppetVersion: 8, Environment: "production", State: "unchanged", ExecutedAt: time.Date(2024, 12, 24, 15, 0, 0, 0, time.UTC), Runtime: 10, Total: 10},
+	}
+
+	tests := []struct {
+		name            string
+		format          string
+		wantContentType string
+		want            string
+	}{
+		{
+			name:            "csv",
+			format:          "csv",
+			wantContentType: "text/csv; charset=utf-8",
+			want: "id,hash,host,environment,status,puppet_version,executed_at,runtime_seconds,runtime_regression,total_resources,total_changed,total_failed,total_skipped\n" +
+				"1,abc,web-1,production,failed,8.1,2024-12-24T13:00:00Z,30,false,10,0,1,0\n" +
+				"2,def,\"web, \"\"2\"\"\",production,changed,8,2024-12-24T14:00:00Z,12,false,10,2,0,0\n" +
+				"3,ghi,'=1+2,production,unchanged,8,2024-12-24T15:00:00Z,10,false,10,0,0,0\n",
+		},
+		{
+			name:            "ndjson",
+			format:          "ndjson",
+			wantContentType: "application/x-ndjson",
+			want: `{"environment":"production","executed_at":"2024-12-24T13:00:00Z","hash":"abc","host":"web-1","id":1,"puppet_version":8.1,"runtime_regression":false,"runtime_seconds":30,"status":"failed","total_changed":0,"total_failed":1,"total_resources":10,"total_skipped":0}` + "\n" +
+				`{"environment":"production","executed_at":"2024-12-24T14:00:00Z","hash":"def","host":"web, \"2\"","id":2,"puppet_version":8,"runtime_regression":false,"runtime_seconds":12,"status":"changed","total_changed":2,"total_failed":0,"total_resources":10,"total_skipped":0}` + "\n" +
+				`{"environment":"production","executed_at":"2024-12-24T15:00:00Z","hash":"ghi","host":"=1+2","id":3,"puppet_version":8,"runtime_regression":false,"runtime_seconds":10,"status":"unchanged","total_changed":0,"total_failed":0,"total_resources":10,"total_skipped":0}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := repo.NewMockRepository(t)
+			r.On("StreamReports", mock.AnythingOfType("*api.GetReportsFilters"), mock.Anything).Run(func(args mock.Arguments) {
+				fn := args.Get(1).(func(*models.Report) error)
+				for _, report := range reports {
+					require.NoError(t, fn(report))
+				}
+			}).Return(nil).Once()
+
+			s := newService(r)
+			format := api.GetReportsParamsFormat(tt.format)
+			stream, err := s.StreamGetReports(slog.Default(), httptest.NewRequest(http.MethodGet, "/reports", nil), api.GetReportsParams{Format: &format})
+			require.NoError(t, err)
+			require.Equal(t, tt.wantContentType, stream.ContentType())
+
+			buf := new(bytes.Buffer)
+			require.NoError(t, stream.Encode(buf))
+			require.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func TestService_StreamGetReports_JSON(t *testing.T) {
+	stream, err := newService(repo.NewMockRepository(t)).StreamGetReports(slog.Default(), httptest.NewRequest(http.MethodGet, "/reports", nil), api.GetReportsParams{})
+	require.NoError(t, err)
+	require.Nil(t, stream)
+}
@@ -0,0 +1,391 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jacobbrewer1/pagefilter"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/apis/specs/api"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/auth"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+	repo "github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api"
+	"github.com/jacobbrewer1/uhttp"
+	"github.com/jacobbrewer1/utils"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// factsKeyName is the host of the facts in the output of puppet facts and the facts terminus.
+	factsKeyName = "name"
+
+	// factsKeyValues are the facts in the output of puppet facts and the facts terminus.
+	factsKeyValues = "values"
+
+	// factNameSeparator joins the names of the nested facts, so os.family is the family of the os fact.
+	factNameSeparator = "."
+)
+
+// volatileFacts change on every Puppet run, they are stored but a change to them alone is not a new set of facts.
+var volatileFacts = []string{
+	"load_averages",
+	"memory.swap.available",
+	"memory.swap.available_bytes",
+	"memory.swap.capacity",
+	"memory.swap.used",
+	"memory.swap.used_bytes",
+	"memory.system.available",
+	"memory.system.available_bytes",
+	"memory.system.capacity",
+	"memory.system.used",
+	"memory.system.used_bytes",
+	"memoryfree",
+	"memoryfree_mb",
+	"swapfree",
+	"swapfree_mb",
+	"system_uptime",
+	"uptime",
+	"uptime_days",
+	"uptime_hours",
+	"uptime_seconds",
+}
+
+func (s *service) UploadFacts(l *slog.Logger, r *http.Request, host string, body0 *api.UploadFactsRequestBody) (*api.HostFacts, error) {
+	cert, err := s.uploadCertificate(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkReportHost(l, cert, host); err != nil {
+		return nil, err
+	}
+
+	bts, err := body0.File.Bytes()
+	if err != nil {
+		return nil, uhttp.NewHTTPError(http.StatusBadRequest, err, "error reading file")
+	} else if len(bts) == 0 {
+		return nil, uhttp.NewHTTPError(http.StatusBadRequest, errors.New("empty facts"), "error reading file")
+	}
+
+	name, values, err := parseFacts(bts)
+	if err != nil {
+		return nil, uhttp.NewHTTPError(http.StatusBadRequest, err, "error parsing facts")
+	} else if name != "" && name != host {
+		return nil, uhttp.NewHTTPError(http.StatusBadRequest, fmt.Errorf("facts are for %s, not %s", name, host), "error parsing facts")
+	}
+
+	flat := flattenFacts(values)
+	hash, err := factsHash(flat)
+	if err != nil {
+		return nil, uhttp.NewHTTPError(http.StatusInternalServerError, err, "error hashing facts")
+	}
+
+	latest, err := s.r.GetLatestFactSet(host)
+	if err != nil && !errors.Is(err, repo.ErrFactsNotFound) {
+		return nil, uhttp.NewHTTPError(http.StatusInternalServerError, err, "error getting facts")
+	} else if latest != nil && latest.Hash == hash {
+		return s.modelAsApiHostFacts(latest)
+	}
+
+	previous := make(map[string]string)
+	if latest != nil {
+		latestValues := make(map[string]any)
+		if err := json.Unmarshal([]byte(latest.Facts), &latestValues); err != nil {
+			return nil, uhttp.NewHTTPError(http.StatusInternalServerError, err, "error reading the latest facts")
+		}
+		previous = flattenFacts(latestValues)
+	}
+
+	factsJSON, err := json.Marshal(values)
+	if err != nil {
+		return nil, uhttp.NewHTTPError(http.StatusInternalServerError, err, "error encoding facts")
+	}
+
+	changesJSON, err := json.Marshal(diffFacts(previous, flat))
+	if err != nil {
+		return nil, uhttp.NewHTTPError(http.StatusInternalServerError, err, "error encoding fact changes")
+	}
+
+	set := &models.FactSet{
+		Host:       host,
+		Hash:       hash,
+		Facts:      string(factsJSON),
+		Changes:    string(changesJSON),
+		ReceivedAt: time.Now().UTC(),
+	}
+
+	hostFacts := make([]*models.HostFact, 0, len(flat))
+	for _, name := range slices.Sorted(maps.Keys(flat)) {
+		hostFacts = append(hostFacts, &models.HostFact{
+			Host:  host,
+			Name:  name,
+			Value: flat[name],
+		})
+	}
+
+	if err := s.r.SaveFacts(set, hostFacts); err != nil {
+		return nil, uhttp.NewHTTPError(http.StatusInternalServerError, err, "error saving facts")
+	}
+
+	l.Info("Saved facts",
+		slog.String("host", host),
+		slog.Int("facts", len(hostFacts)),
+	)
+
+	return s.modelAsApiHostFacts(set)
+}
+
+func (s *service) GetFacts(l *slog.Logger, r *http.Request, host string) (*api.HostFacts, error) {
+	if err := s.checkHostAccess(auth.AccessFromContext(r.Context()), host); err != nil {
+		return nil, err
+	}
+
+	set, err := s.r.GetLatestFactSet(host)
+	if err != nil {
+		switch {
+		case errors.Is(err, repo.ErrFactsNotFound):
+			return nil, uhttp.NewHTTPError(http.StatusNotFound, err, "facts not found", fmt.Sprintf("host: %s", host))
+		default:
+			return nil, uhttp.NewHTTPError(http.StatusInternalServerError, err, "failed to get facts", fmt.Sprintf("host: %s", host))
+		}
+	}
+
+	return s.modelAsApiHostFacts(set)
+}
+
+func (s *service) GetFactHistory(l *slog.Logger, r *http.Request, host string, params api.GetFactHistoryParams) (*api.FactHistoryResponse, error) {
+	if err := s.checkHostAccess(auth.AccessFromContext(r.Context()), host); err != nil {
+		return nil, err
+	}
+
+	paginationDetails, err := pagefilter.DetailsFromRequest(r)
+	if err != nil {
+		return nil, uhttp.NewHTTPError(http.StatusBadRequest, err, "failed to get pagination details")
+	}
+
+	sets, err := s.r.GetFactHistory(paginationDetails, host)
+	if err != nil {
+		switch {
+		case errors.Is(err, repo.ErrFactsNotFound):
+			sets = &pagefilter.PaginatedResponse[models.FactSet]{
+				Items: make([]*models.FactSet, 0),
+				Total: 0,
+			}
+		default:
+			return nil, uhttp.NewHTTPError(http.StatusInternalServerError, err, "error getting fact history")
+		}
+	}
+
+	respArray := make([]api.FactSet, len(sets.Items))
+	for i, set := range sets.Items {
+		changes := make([]api.FactChange, 0)
+		if err := json.Unmarshal([]byte(set.Changes), &changes); err != nil {
+			return nil, uhttp.NewHTTPError(http.StatusInternalServerError, err, "error reading fact changes")
+		}
+
+		respArray[i] = api.FactSet{
+			Changes:    changes,
+			Hash:       set.Hash,
+			Id:         int64(set.Id),
+			ReceivedAt: set.ReceivedAt,
+		}
+	}
+
+	resp := &api.FactHistoryResponse{
+		History: respArray,
+		Total:   sets.Total,
+	}
+
+	return resp, nil
+}
+
+// checkHostAccess returns a 404 if the reader cannot read the environment of the latest report of the host, so the
+// reader cannot tell the host exists.
+func (s *service) checkHostAccess(access *auth.Access, host string) error {
+	if _, restricted := access.Environments(); !restricted {
+		return nil
+	}
+
+	rep, err := s.r.GetLatestReportByHost(host)
+	if err != nil {
+		switch {
+		case errors.Is(err, repo.ErrReportNotFound):
+			return uhttp.NewHTTPError(http.StatusNotFound, repo.ErrFactsNotFound, "facts not found", fmt.Sprintf("host: %s", host))
+		default:
+			return uhttp.NewHTTPError(http.StatusInternalServerError, err, "failed to get the latest report", fmt.Sprintf("host: %s", host))
+		}
+	}
+
+	if !access.AllowsEnvironment(rep.Environment) {
+		return uhttp.NewHTTPError(http.StatusNotFound, repo.ErrFactsNotFound, "facts not found", fmt.Sprintf("host: %s", host))
+	}
+
+	return nil
+}
+
+func (s *service) modelAsApiHostFacts(set *models.FactSet) (*api.HostFacts, error) {
+	facts := make(map[string]any)
+	if err := json.Unmarshal([]byte(set.Facts), &facts); err != nil {
+		return nil, uhttp.NewHTTPError(http.StatusInternalServerError, err, "error reading facts")
+	}
+
+	return &api.HostFacts{
+		Facts:      facts,
+		Hash:       set.Hash,
+		Host:       set.Host,
+		ReceivedAt: set.ReceivedAt,
+	}, nil
+}
+
+// parseFacts reads the facts of a host from the YAML or JSON of puppet facts or the facts terminus, which put the
+// facts under values, or the output of facter. The name is empty if the facts do not say which host they are for.
+func parseFacts(content []byte) (string, map[string]any, error) {
+	// YAML is a superset of JSON, and the Ruby tags of the facts terminus are ignored.
+	var doc any
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return "", nil, fmt.Errorf("failed to parse facts: %w", err)
+	}
+
+	facts, ok := normaliseFact(doc).(map[string]any)
+	if !ok {
+		return "", nil, errors.New("facts are not a map")
+	}
+
+	values, ok := facts[factsKeyValues].(map[string]any)
+	if !ok {
+		return "", facts, nil
+	}
+
+	name, _ := facts[factsKeyName].(string)
+	return name, values, nil
+}
+
+// normaliseFact converts the maps with keys that are not strings, so the facts can be encoded as JSON.
+func normaliseFact(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, val := range v {
+			v[k] = normaliseFact(val)
+		}
+		return v
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, val := range v {
+			m[fmt.Sprint(k)] = normaliseFact(val)
+		}
+		return m
+	case []any:
+		for i, val := range v {
+			v[i] = normaliseFact(val)
+		}
+		return v
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	default:
+		return v
+	}
+}
+
+// flattenFacts returns the value of each fact keyed by its dotted name, the elements of a list are named by their
+// index.
+func flattenFacts(values map[string]any) map[string]string {
+	flat := make(map[string]string)
+	flattenFact(flat, "", values)
+	return flat
+}
+
+func flattenFact(flat map[string]string, name string, v any) {
+	join := func(key string) string {
+		if name == "" {
+			return key
+		}
+		return name + factNameSeparator + key
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		for k, val := range v {
+			flattenFact(flat, join(k), val)
+		}
+	case []any:
+		for i, val := range v {
+			flattenFact(flat, join(strconv.Itoa(i)), val)
+		}
+	case nil:
+		flat[name] = ""
+	case string:
+		flat[name] = v
+	default:
+		flat[name] = fmt.Sprint(v)
+	}
+}
+
+// isVolatileFact returns true if the fact, or the fact it is nested in, changes on every Puppet run.
+func isVolatileFact(name string) bool {
+	for _, volatile := range volatileFacts {
+		if name == volatile || strings.HasPrefix(name, volatile+factNameSeparator) {
+			return true
+		}
+	}
+	return false
+}
+
+// factsHash returns the hash of the facts that are not volatile, the facts of a host have changed if it differs.
+func factsHash(flat map[string]string) (string, error) {
+	stable := make(map[string]string, len(flat))
+	for name, value := range flat {
+		if !isVolatileFact(name) {
+			stable[name] = value
+		}
+	}
+
+	// The keys of a map are sorted when it is encoded, so the same facts always have the same hash.
+	bts, err := json.Marshal(stable)
+	if err != nil {
+		return "", err
+	}
+
+	return utils.Sha256(bts), nil
+}
+
+// diffFacts returns the facts that were added, removed or changed, sorted by name. The volatile facts are left out.
+func diffFacts(previous, current map[string]string) []api.FactChange {
+	names := make(map[string]struct{}, len(current))
+	for name := range previous {
+		names[name] = struct{}{}
+	}
+	for name := range current {
+		names[name] = struct{}{}
+	}
+
+	changes := make([]api.FactChange, 0)
+	for _, name := range slices.Sorted(maps.Keys(names)) {
+		if isVolatileFact(name) {
+			continue
+		}
+
+		oldValue, hadOld := previous[name]
+		newValue, hasNew := current[name]
+		if hadOld && hasNew && oldValue == newValue {
+			continue
+		}
+
+		change := api.FactChange{Name: name}
+		if hadOld {
+			change.OldValue = utils.Ptr(oldValue)
+		}
+		if hasNew {
+			change.NewValue = utils.Ptr(newValue)
+		}
+		changes = append(changes, change)
+	}
+
+	return changes
+}
@@ -0,0 +1,205 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jacobbrewer1/puppet-reporter/pkg/apis/specs/api"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+	repo "github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api"
+	"github.com/jacobbrewer1/uhttp"
+	"github.com/jacobbrewer1/utils"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFacts(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		wantName   string
+		wantValues map[string]any
+		wantErr    bool
+	}{
+		{
+			name: "facts terminus",
+			content: `--- !ruby/object:Puppet::Node::Facts
+name: web-1
+values:
+  os:
+    family: RedHat
+  processorcount: 4
+  timestamp: 2024-12-24 13:00:00.000000000 +00:00
+`,
+			wantName: "web-1",
+			wantValues: map[string]any{
+				"os":             map[string]any{"family": "RedHat"},
+				"processorcount": 4,
+				"timestamp":      "2024-12-24 13:00:00.000000000 +00:00",
+			},
+		},
+		{
+			name:     "puppet facts",
+			content:  `{"name": "web-1", "values": {"os": {"family": "RedHat"}, "is_virtual": true}, "timestamp": "2024-12-24T13:00:00Z"}`,
+			wantName: "web-1",
+			wantValues: map[string]any{
+				"os":         map[string]any{"family": "RedHat"},
+				"is_virtual": true,
+			},
+		},
+		{
+			name:    "facter",
+			content: `{"os": {"family": "Debian"}}`,
+			wantValues: map[string]any{
+				"os": map[string]any{"family": "Debian"},
+			},
+		},
+		{
+			name:    "not a map",
+			content: "- web-1\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, values, err := parseFacts([]byte(tt.content))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.wantName, name)
+			require.Equal(t, tt.wantValues, values)
+		})
+	}
+}
+
+func TestDiffFacts(t *testing.T) {
+	previous := flattenFacts(map[string]any{
+		"os":     map[string]any{"family": "RedHat", "release": map[string]any{"full": "9.3"}},
+		"uptime": "1 day",
+		"ipaddresses": []any{
+			"10.0.0.1",
+		},
+		"removed": "yes",
+	})
+	current := flattenFacts(map[string]any{
+		"os":     map[string]any{"family": "RedHat", "release": map[string]any{"full": "9.4"}},
+		"uptime": "2 days",
+		"ipaddresses": []any{
+			"10.0.0.1",
+			"10.0.0.2",
+		},
+		"virtual": nil,
+	})
+
+	require.Equal(t, []api.FactChange{
+		{Name: "ipaddresses.1", NewValue: utils.Ptr("10.0.0.2")},
+		{Name: "os.release.full", OldValue: utils.Ptr("9.3"), NewValue: utils.Ptr("9.4")},
+		{Name: "removed", OldValue: utils.Ptr("yes")},
+		{Name: "virtual", NewValue: utils.Ptr("")},
+	}, diffFacts(previous, current))
+
+	// The volatile facts do not change the hash.
+	hash, err := factsHash(map[string]string{"os.family": "RedHat", "uptime": "1 day"})
+	require.NoError(t, err)
+	otherHash, err := factsHash(map[string]string{"os.family": "RedHat", "uptime": "2 days"})
+	require.NoError(t, err)
+	require.Equal(t, hash, otherHash)
+}
+
+func TestService_UploadFacts(t *testing.T) {
+	const facts = `{"name": "web-1", "values": {"os": {"family": "RedHat"}, "uptime": "2 days"}}`
+
+	hash, err := factsHash(map[string]string{"os.family": "RedHat"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		host        string
+		latest      *models.FactSet
+		wantStatus  int
+		wantSaved   bool
+		wantChanges []api.FactChange
+	}{
+		{
+			name:        "first facts",
+			host:        "web-1",
+			wantSaved:   true,
+			wantChanges: []api.FactChange{{Name: "os.family", NewValue: utils.Ptr("RedHat")}},
+		},
+		{
+			name:        "changed facts",
+			host:        "web-1",
+			latest:      &models.FactSet{Host: "web-1", Hash: "old", Facts: `{"os": {"family": "Debian"}}`},
+			wantSaved:   true,
+			wantChanges: []api.FactChange{{Name: "os.family", OldValue: utils.Ptr("Debian"), NewValue: utils.Ptr("RedHat")}},
+		},
+		{
+			name:   "unchanged facts",
+			host:   "web-1",
+			latest: &models.FactSet{Host: "web-1", Hash: hash, Facts: `{"os": {"family": "RedHat"}, "uptime": "1 day"}`},
+		},
+		{
+			name:       "facts for another host",
+			host:       "db-1",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := repo.NewMockRepository(t)
+			if tt.wantStatus == 0 {
+				var latestErr error
+				if tt.latest == nil {
+					latestErr = repo.ErrFactsNotFound
+				}
+				r.On("GetLatestFactSet", tt.host).Return(tt.latest, latestErr).Once()
+			}
+
+			var saved *models.FactSet
+			var savedFacts []*models.HostFact
+			if tt.wantSaved {
+				r.On("SaveFacts", mock.AnythingOfType("*models.FactSet"), mock.AnythingOfType("[]*models.HostFact")).Run(func(args mock.Arguments) {
+					saved = args.Get(0).(*models.FactSet)
+					savedFacts = args.Get(1).([]*models.HostFact)
+				}).Return(nil).Once()
+			}
+
+			body := &api.UploadFactsRequestBody{File: new(openapi_types.File)}
+			body.File.InitFromBytes([]byte(facts), "facts.json")
+
+			req := httptest.NewRequest(http.MethodPost, "/facts/"+tt.host, nil)
+			resp, err := newService(r).UploadFacts(slog.Default(), req, tt.host, body)
+			if tt.wantStatus != 0 {
+				httpErr := new(uhttp.HTTPError)
+				require.ErrorAs(t, err, &httpErr)
+				require.Equal(t, tt.wantStatus, httpErr.StatusCode())
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, "web-1", resp.Host)
+			require.Equal(t, map[string]any{"family": "RedHat"}, resp.Facts["os"])
+			if !tt.wantSaved {
+				return
+			}
+
+			require.Equal(t, hash, saved.Hash)
+			require.Len(t, savedFacts, 2)
+			require.Equal(t, "os.family", savedFacts[0].Name)
+			require.Equal(t, "uptime", savedFacts[1].Name)
+
+			changes := make([]api.FactChange, 0)
+			require.NoError(t, json.Unmarshal([]byte(saved.Changes), &changes))
+			require.Equal(t, tt.wantChanges, changes)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/jacobbrewer1/puppet-reporter/pkg/apis/specs/api"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/auth"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/flapping"
+	repo "github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api"
+	"github.com/jacobbrewer1/uhttp"
+)
+
+func (s *service) GetFlappingStats(l *slog.Logger, r *http.Request, params api.GetFlappingStatsParams) (*api.FlappingStats, error) {
+	window := flapping.DefaultWindow
+	if params.Window != nil {
+		window = *params.Window
+	}
+	if window < 2 || window > flapping.MaxWindow {
+		return nil, uhttp.NewHTTPError(http.StatusBadRequest, fmt.Errorf("window %d is not between 2 and %d", window, flapping.MaxWindow), "invalid window")
+	}
+
+	threshold := flapping.DefaultThreshold
+	if params.Threshold != nil {
+		threshold = *params.Threshold
+	}
+	if threshold < 0 || threshold > 1 {
+		return nil, uhttp.NewHTTPError(http.StatusBadRequest, fmt.Errorf("threshold %g is not between 0 and 1", threshold), "invalid threshold")
+	}
+
+	filters := &repo.GetReportsFilters{
+		Host:        params.Host,
+		Environment: params.Environment,
+	}
+	if envs, restricted := auth.AccessFromContext(r.Context()).Environments(); restricted {
+		filters.Environments = envs
+	}
+
+	reports, err := s.r.GetRecentReports(window, filters)
+	if err != nil {
+		return nil, uhttp.NewHTTPError(http.StatusInternalServerError, err, "error getting reports")
+	}
+
+	changes, err := s.r.GetResourceChanges(window, filters)
+	if err != nil {
+		return nil, uhttp.NewHTTPError(http.StatusInternalServerError, err, "error getting resource changes")
+	}
+
+	res := flapping.Detect(reports, changes, threshold)
+
+	stats := &api.FlappingStats{
+		Window:    window,
+		Threshold: threshold,
+		Hosts:     make([]api.FlappingHost, 0, len(res.Hosts)),
+		Resources: make([]api.FlappingResource, 0, len(res.Resources)),
+	}
+
+	for _, h := range res.Hosts {
+		states := make([]api.ReportStatus, len(h.States))
+		for i, state := range h.States {
+			states[i] = api.ReportStatus(state)
+		}
+
+		stats.Hosts = append(stats.Hosts, api.FlappingHost{
+			Host:        h.Host,
+			Environment: h.Environment,
+			Runs:        h.Runs,
+			Transitions: h.Transitions,
+			Ratio:       h.Ratio,
+			States:      states,
+		})
+	}
+
+	for _, rsc := range res.Resources {
+		stats.Resources = append(stats.Resources, api.FlappingResource{
+			Host:    rsc.Host,
+			Type:    rsc.Type,
+			Name:    rsc.Name,
+			Runs:    rsc.Runs,
+			Changes: rsc.Changes,
+			Ratio:   rsc.Ratio,
+		})
+	}
+
+	return stats, nil
+}
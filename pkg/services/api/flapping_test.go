@@ -0,0 +1,75 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jacobbrewer1/goschema/usql"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/apis/specs/api"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/flapping"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+	repo "github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api"
+	"github.com/jacobbrewer1/uhttp"
+	"github.com/jacobbrewer1/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_GetFlappingStats(t *testing.T) {
+	reports := make([]*models.Report, 0)
+	for _, state := range []string{"FAILED", "CHANGED", "FAILED", "CHANGED"} {
+		reports = append(reports, &models.Report{Host: "web-1", Environment: "PRODUCTION", State: usql.Enum(state)})
+	}
+	changes := []*flapping.ResourceChanges{{Host: "web-1", Type: "Exec", Name: "date", Changes: 4}}
+
+	filters := &repo.GetReportsFilters{Host: utils.Ptr("web")}
+
+	r := repo.NewMockRepository(t)
+	r.On("GetRecentReports", 5, filters).Return(reports, nil).Once()
+	r.On("GetResourceChanges", 5, filters).Return(changes, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/stats/flapping", nil)
+	got, err := newService(r).GetFlappingStats(slog.Default(), req, api.GetFlappingStatsParams{
+		Window: utils.Ptr(5),
+		Host:   utils.Ptr("web"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, &api.FlappingStats{
+		Window:    5,
+		Threshold: flapping.DefaultThreshold,
+		Hosts: []api.FlappingHost{{
+			Host:        "web-1",
+			Environment: "PRODUCTION",
+			Runs:        4,
+			Transitions: 3,
+			Ratio:       1,
+			States: []api.ReportStatus{
+				api.ReportStatusfailed, api.ReportStatuschanged, api.ReportStatusfailed, api.ReportStatuschanged,
+			},
+		}},
+		Resources: []api.FlappingResource{{Host: "web-1", Type: "Exec", Name: "date", Runs: 4, Changes: 4, Ratio: 1}},
+	}, got)
+}
+
+func TestService_GetFlappingStats_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		params api.GetFlappingStatsParams
+	}{
+		{name: "window too small", params: api.GetFlappingStatsParams{Window: utils.Ptr(1)}},
+		{name: "window too large", params: api.GetFlappingStatsParams{Window: utils.Ptr(flapping.MaxWindow + 1)}},
+		{name: "threshold above one", params: api.GetFlappingStatsParams{Threshold: utils.Ptr(1.5)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/stats/flapping", nil)
+			_, err := newService(repo.NewMockRepository(t)).GetFlappingStats(slog.Default(), req, tt.params)
+
+			httpErr := new(uhttp.HTTPError)
+			require.ErrorAs(t, err, &httpErr)
+			require.Equal(t, http.StatusBadRequest, httpErr.StatusCode())
+		})
+	}
+}
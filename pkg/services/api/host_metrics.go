@@ -0,0 +1,165 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+	repo "github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	resourceStateFailed  = "failed"
+	resourceStateChanged = "changed"
+	resourceStateSkipped = "skipped"
+	resourceStateTotal   = "total"
+)
+
+// reportStates are the states a report can be in, used to reset the host state gauge.
+var reportStates = []string{
+	string(models.ReportStateChanged),
+	string(models.ReportStateFailed),
+	string(models.ReportStateUnchanged),
+}
+
+var (
+	// hostLastRun is the execution time of the report the metrics of each host were last set from.
+	hostLastRun = make(map[string]time.Time)
+
+	// hostFailures is the number of failed reports of each host since its last successful report.
+	hostFailures = make(map[string]int)
+
+	// hostLastRunMu guards hostLastRun, hostFailures and the updates of the per host gauges.
+	hostLastRunMu = new(sync.Mutex)
+)
+
+// HostFilter decides which hosts are exported with their own metrics. This keeps the cardinality of the
+// per host metrics bounded, a host is only exported if it is in the allow list or matches the pattern.
+type HostFilter struct {
+	// hosts is the set of hosts that are always exported.
+	hosts map[string]struct{}
+
+	// pattern is matched against hosts that are not in the allow list.
+	pattern *regexp.Regexp
+}
+
+// NewHostFilter creates a new HostFilter. If no hosts or pattern are given no host will be exported.
+func NewHostFilter(hosts []string, pattern string) (*HostFilter, error) {
+	f := &HostFilter{
+		hosts: make(map[string]struct{}, len(hosts)),
+	}
+
+	for _, h := range hosts {
+		if h = strings.TrimSpace(h); h != "" {
+			f.hosts[h] = struct{}{}
+		}
+	}
+
+	if pattern != "" {
+		reg, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid host pattern: %w", err)
+		}
+		f.pattern = reg
+	}
+
+	return f, nil
+}
+
+// Allowed returns true if the host should be exported.
+func (f *HostFilter) Allowed(host string) bool {
+	if f == nil {
+		return false
+	}
+
+	if _, ok := f.hosts[host]; ok {
+		return true
+	}
+
+	return f.pattern != nil && f.pattern.MatchString(host)
+}
+
+// RebuildHostMetrics sets the per host metrics from the latest report of each host in the database. This is
+// called on startup so the gauges do not reset when the service restarts.
+func RebuildHostMetrics(r repo.Repository, f *HostFilter) error {
+	reports, err := r.GetLatestReports()
+	if err != nil {
+		return fmt.Errorf("get latest reports: %w", err)
+	}
+
+	failures, err := r.GetConsecutiveFailures()
+	if err != nil {
+		return fmt.Errorf("get consecutive failures: %w", err)
+	}
+
+	hostLastRunMu.Lock()
+	defer hostLastRunMu.Unlock()
+
+	for _, rep := range reports {
+		if !f.Allowed(rep.Host) {
+			continue
+		}
+
+		setHostMetrics(rep, failures[rep.Host])
+	}
+
+	return nil
+}
+
+// observeHostReport updates the per host gauges with a newly received report. Reports older than the one the
+// gauges were last set from are ignored.
+func observeHostReport(rep *models.Report) {
+	hostLastRunMu.Lock()
+	defer hostLastRunMu.Unlock()
+
+	if last, ok := hostLastRun[rep.Host]; ok && last.After(rep.ExecutedAt) {
+		return
+	}
+
+	failures := 0
+	if strings.EqualFold(string(rep.State), string(models.ReportStateFailed)) {
+		failures = hostFailures[rep.Host] + 1
+	}
+
+	setHostMetrics(rep, failures)
+}
+
+// setHostMetrics sets the per host gauges from the given report, replacing any previous values for the host.
+// The caller must hold hostLastRunMu.
+func setHostMetrics(rep *models.Report, failures int) {
+	host := rep.Host
+	hostLastRun[host] = rep.ExecutedAt
+	hostFailures[host] = failures
+
+	env := strings.ToLower(rep.Environment)
+
+	// The host may have moved environments, remove the series for the old environment.
+	hostLabels := prometheus.Labels{"host": host}
+	hostLastRunTimestamp.DeletePartialMatch(hostLabels)
+	hostRuntimeSeconds.DeletePartialMatch(hostLabels)
+	hostResources.DeletePartialMatch(hostLabels)
+	hostState.DeletePartialMatch(hostLabels)
+	hostConsecutiveFailures.DeletePartialMatch(hostLabels)
+
+	hostLastRunTimestamp.WithLabelValues(host, env).Set(float64(rep.ExecutedAt.Unix()))
+	hostRuntimeSeconds.WithLabelValues(host, env).Set(float64(rep.Runtime))
+	hostConsecutiveFailures.WithLabelValues(host, env).Set(float64(failures))
+
+	hostResources.WithLabelValues(host, env, resourceStateFailed).Set(float64(rep.Failed))
+	hostResources.WithLabelValues(host, env, resourceStateChanged).Set(float64(rep.Changed))
+	hostResources.WithLabelValues(host, env, resourceStateSkipped).Set(float64(rep.Skipped))
+	hostResources.WithLabelValues(host, env, resourceStateTotal).Set(float64(rep.Total))
+
+	current := strings.ToLower(string(rep.State))
+	for _, state := range reportStates {
+		v := 0.0
+		if state == current {
+			v = 1
+		}
+		hostState.WithLabelValues(host, env, state).Set(v)
+	}
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostFilter_Allowed(t *testing.T) {
+	tests := []struct {
+		name    string
+		hosts   []string
+		pattern string
+		host    string
+		want    bool
+	}{
+		{
+			name: "no filter configured",
+			host: "web-1.example.com",
+			want: false,
+		},
+		{
+			name:  "host in allow list",
+			hosts: []string{"web-1.example.com", "db-1.example.com"},
+			host:  "db-1.example.com",
+			want:  true,
+		},
+		{
+			name:  "host not in allow list",
+			hosts: []string{"web-1.example.com"},
+			host:  "web-2.example.com",
+			want:  false,
+		},
+		{
+			name:    "host matches pattern",
+			pattern: `^web-[0-9]+\.example\.com$`,
+			host:    "web-2.example.com",
+			want:    true,
+		},
+		{
+			name:    "host does not match pattern",
+			hosts:   []string{"db-1.example.com"},
+			pattern: `^web-`,
+			host:    "build-1.example.com",
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewHostFilter(tt.hosts, tt.pattern)
+			require.NoError(t, err)
+
+			got := f.Allowed(tt.host)
+			require.Equal(t, tt.want, got, "Allowed() = %v, want %v", got, tt.want)
+		})
+	}
+}
+
+func TestNewHostFilter_InvalidPattern(t *testing.T) {
+	_, err := NewHostFilter(nil, "web-[")
+	require.Error(t, err)
+}
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/jacobbrewer1/pagefilter"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/apis/specs/api"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/logging"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+	repo "github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api"
+	"github.com/jacobbrewer1/uhttp"
+	"github.com/jacobbrewer1/utils"
+)
+
+const (
+	// ingestionStageRead is the stage for reports where the uploaded file could not be read.
+	ingestionStageRead = "read"
+
+	// ingestionStageUnknown is the stage for errors that did not come from the parser.
+	ingestionStageUnknown = "unknown"
+
+	// DefaultIngestionFailureRetention is how long the reports that could not be ingested are kept for.
+	DefaultIngestionFailureRetention = 30 * 24 * time.Hour
+
+	// ingestionFailurePruneInterval is how often the ingestion failures past their retention are deleted.
+	ingestionFailurePruneInterval = time.Hour
+)
+
+// recordIngestionFailure counts a report that failed to parse and saves it to the dead-letter store, so it can be
+// downloaded and used to fix the parser or the agent.
+func (s *service) recordIngestionFailure(l *slog.Logger, payload []byte, err error) {
+	stage := ingestionStageUnknown
+	parseErr := new(ParseError)
+	if errors.As(err, &parseErr) {
+		stage = parseErr.Stage
+	}
+
+	ingestionErrors.WithLabelValues(stage).Inc()
+
+	failure := &models.IngestionFailure{
+		Stage:       stage,
+		Error:       err.Error(),
+		PayloadHash: utils.Sha256(payload),
+		PayloadSize: len(payload),
+		ReceivedAt:  time.Now().UTC(),
+	}
+
+	if err := s.r.SaveIngestionFailure(failure, payload); err != nil {
+		l.Error("Error saving ingestion failure", slog.String(logging.KeyError, err.Error()))
+		return
+	}
+
+	l.Info("Saved ingestion failure",
+		slog.Int("id", failure.Id),
+		slog.String("stage", stage),
+	)
+}
+
+// PruneIngestionFailures deletes the ingestion failures older than the retention every hour, until the context is
+// cancelled. Every payload is kept, so an agent sending broken reports would otherwise grow the dead-letter store
+// without bound.
+func PruneIngestionFailures(ctx context.Context, r repo.Repository, retention time.Duration) {
+	ticker := time.NewTicker(ingestionFailurePruneInterval)
+	defer ticker.Stop()
+
+	for {
+		n, err := r.DeleteIngestionFailures(time.Now().UTC().Add(-retention))
+		if err != nil {
+			slog.Error("Error deleting ingestion failures", slog.String(logging.KeyError, err.Error()))
+		} else if n > 0 {
+			slog.Info("Deleted ingestion failures past their retention", slog.Int64("count", n))
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *service) GetIngestionFailures(l *slog.Logger, r *http.Request, params api.GetIngestionFailuresParams) (*api.IngestionFailureResponse, error) {
+	paginationDetails, err := pagefilter.DetailsFromRequest(r)
+	if err != nil {
+		return nil, uhttp.NewHTTPError(http.StatusBadRequest, err, "failed to get pagination details")
+	}
+
+	filts := &repo.GetIngestionFailuresFilters{
+		Stage: params.Stage,
+		From:  params.From,
+		To:    params.To,
+	}
+
+	failures, err := s.r.GetIngestionFailures(paginationDetails, filts)
+	if err != nil {
+		switch {
+		case errors.Is(err, repo.ErrNoIngestionFailures):
+			failures = &pagefilter.PaginatedResponse[models.IngestionFailure]{
+				Items: make([]*models.IngestionFailure, 0),
+				Total: 0,
+			}
+		default:
+			return nil, uhttp.NewHTTPError(http.StatusInternalServerError, err, "error getting ingestion failures")
+		}
+	}
+
+	respArray := make([]api.IngestionFailure, len(failures.Items))
+	for i, failure := range failures.Items {
+		respArray[i] = *s.modelAsApiIngestionFailure(failure)
+	}
+
+	resp := &api.IngestionFailureResponse{
+		Failures: respArray,
+		Total:    failures.Total,
+	}
+
+	return resp, nil
+}
+
+func (s *service) GetIngestionFailure(l *slog.Logger, r *http.Request, id int64) (*api.IngestionFailure, error) {
+	failure, err := s.r.GetIngestionFailureByID(int(id))
+	if err != nil {
+		switch {
+		case errors.Is(err, repo.ErrIngestionFailureNotFound):
+			return nil, uhttp.NewHTTPError(http.StatusNotFound, err, "ingestion failure not found", fmt.Sprintf("id: %d", id))
+		default:
+			return nil, uhttp.NewHTTPError(http.StatusInternalServerError, err, "failed to get ingestion failure", fmt.Sprintf("id: %d", id))
+		}
+	}
+
+	return s.modelAsApiIngestionFailure(failure), nil
+}
+
+func (s *service) GetIngestionFailurePayload(l *slog.Logger, r *http.Request, id int64) ([]byte, error) {
+	payload, err := s.r.GetIngestionFailurePayload(int(id))
+	if err != nil {
+		switch {
+		case errors.Is(err, repo.ErrIngestionFailureNotFound):
+			return nil, uhttp.NewHTTPError(http.StatusNotFound, err, "ingestion failure not found", fmt.Sprintf("id: %d", id))
+		default:
+			return nil, uhttp.NewHTTPError(http.StatusInternalServerError, err, "failed to get ingestion failure payload", fmt.Sprintf("id: %d", id))
+		}
+	}
+
+	return payload, nil
+}
+
+func (s *service) modelAsApiIngestionFailure(failure *models.IngestionFailure) *api.IngestionFailure {
+	return &api.IngestionFailure{
+		Error:       failure.Error,
+		Id:          int64(failure.Id),
+		PayloadHash: failure.PayloadHash,
+		PayloadSize: int64(failure.PayloadSize),
+		ReceivedAt:  failure.ReceivedAt,
+		Stage:       failure.Stage,
+	}
+}
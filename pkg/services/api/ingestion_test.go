@@ -0,0 +1,41 @@
+package api
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+	repo "github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_RecordIngestionFailure(t *testing.T) {
+	payload := []byte("host: web-1")
+
+	r := repo.NewMockRepository(t)
+	r.On("SaveIngestionFailure", mock.MatchedBy(func(f *models.IngestionFailure) bool {
+		return f.Stage == parseStagePuppetVersion &&
+			f.PayloadSize == len(payload) &&
+			f.Error == "parsing puppet version: failed to get 'puppet_version' from YAML"
+	}), payload).Return(nil).Once()
+
+	s := &service{r: r}
+
+	before := counterValue(t, ingestionErrors.WithLabelValues(parseStagePuppetVersion))
+
+	_, err := parsePuppetReport(payload)
+	require.Error(t, err)
+
+	s.recordIngestionFailure(slog.Default(), payload, err)
+
+	require.Equal(t, before+1, counterValue(t, ingestionErrors.WithLabelValues(parseStagePuppetVersion)))
+}
+
+func counterValue(t *testing.T, c prometheus.Counter) float64 {
+	m := new(dto.Metric)
+	require.NoError(t, c.Write(m))
+	return m.GetCounter().GetValue()
+}
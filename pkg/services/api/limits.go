@@ -0,0 +1,246 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/auth"
+	"github.com/jacobbrewer1/uhttp"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// DefaultMaxBodyBytes is the default maximum size of a request body. The reports of the nodes with many resources
+	// can be a few megabytes.
+	DefaultMaxBodyBytes = 32 << 20
+
+	// rejectReasonGlobalRate is the reason of the requests rejected by the global rate limit.
+	rejectReasonGlobalRate = "global_rate_limit"
+
+	// rejectReasonClientRate is the reason of the requests rejected by the rate limit of the client.
+	rejectReasonClientRate = "client_rate_limit"
+
+	// rejectReasonBodySize is the reason of the requests rejected as the body is too large.
+	rejectReasonBodySize = "body_too_large"
+
+	// headerRetryAfter tells the client how many seconds to wait before retrying.
+	headerRetryAfter = "Retry-After"
+
+	// headerForwardedFor is the header a proxy puts the address of the client in.
+	headerForwardedFor = "X-Forwarded-For"
+
+	// limiterSweepInterval is how often the limiters of the clients that have not been seen for a while are removed.
+	limiterSweepInterval = time.Minute
+)
+
+// RateLimit is the rate of a token bucket.
+type RateLimit struct {
+	// RPS is the number of requests per second the bucket refills at, zero for no limit.
+	RPS float64
+
+	// Burst is the number of requests the bucket holds, the RPS rounded up if not set.
+	Burst int
+}
+
+// limiter returns the token bucket of the rate, nil if there is no limit.
+func (l RateLimit) limiter() *rate.Limiter {
+	if l.RPS <= 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(l.RPS), l.burst())
+}
+
+// burst returns the number of requests the bucket holds, at least one.
+func (l RateLimit) burst() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return max(1, int(math.Ceil(l.RPS)))
+}
+
+// RateLimiter limits the uploads with a global token bucket and a bucket per client. The clients are identified by
+// their certificate if they present one, so the Puppet agents behind a NAT are limited separately, and by their IP
+// address otherwise. It is registered with api.WithRateLimiter.
+type RateLimiter struct {
+	// global is the bucket shared by all the clients, nil if there is no global limit.
+	global *rate.Limiter
+
+	// client is the rate of the bucket of each client.
+	client RateLimit
+
+	// trustForwardedFor is true if the IP address of the client is taken from the X-Forwarded-For header.
+	trustForwardedFor bool
+
+	// mu guards the fields below.
+	mu sync.Mutex
+
+	// clients are the buckets of the clients.
+	clients map[string]*rate.Limiter
+
+	// lastSweep is when the idle clients were last removed.
+	lastSweep time.Time
+}
+
+// NewRateLimiter creates the rate limiter. A rate of zero is not limited.
+func NewRateLimiter(global, client RateLimit, trustForwardedFor bool) *RateLimiter {
+	return &RateLimiter{
+		global:            global.limiter(),
+		client:            client,
+		trustForwardedFor: trustForwardedFor,
+		clients:           make(map[string]*rate.Limiter),
+		lastSweep:         time.Now(),
+	}
+}
+
+// Limit returns a 429 error if the request is over the global limit or the limit of its client, setting the
+// Retry-After header to when it can be retried.
+func (l *RateLimiter) Limit(w http.ResponseWriter, r *http.Request) error {
+	now := time.Now()
+
+	limiters := make(map[string]*rate.Limiter, 2)
+	if cl := l.clientLimiter(l.clientKey(r), now); cl != nil {
+		limiters[rejectReasonClientRate] = cl
+	}
+	if l.global != nil {
+		limiters[rejectReasonGlobalRate] = l.global
+	}
+
+	var (
+		delay        time.Duration
+		reason       string
+		reservations = make([]*rate.Reservation, 0, len(limiters))
+	)
+	for limitReason, limiter := range limiters {
+		res := limiter.ReserveN(now, 1)
+		reservations = append(reservations, res)
+
+		if d := res.DelayFrom(now); d > delay {
+			delay = d
+			reason = limitReason
+		}
+	}
+
+	if delay == 0 {
+		return nil
+	}
+
+	// The request is rejected rather than delayed, so it does not use up any of the tokens.
+	for _, res := range reservations {
+		res.CancelAt(now)
+	}
+
+	rejectedRequests.WithLabelValues(reason).Inc()
+
+	retryAfter := int(math.Ceil(delay.Seconds()))
+	w.Header().Set(headerRetryAfter, strconv.Itoa(retryAfter))
+
+	return uhttp.NewHTTPError(http.StatusTooManyRequests, fmt.Errorf("rate limit exceeded, retry after %d seconds", retryAfter))
+}
+
+// clientLimiter returns the bucket of the client, nil if the clients are not limited.
+func (l *RateLimiter) clientLimiter(key string, now time.Time) *rate.Limiter {
+	if l.client.RPS <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) >= limiterSweepInterval {
+		// A full bucket is the same as a new one, so the clients with a full bucket can be forgotten.
+		for k, cl := range l.clients {
+			if cl.TokensAt(now) >= float64(cl.Burst()) {
+				delete(l.clients, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	cl, ok := l.clients[key]
+	if !ok {
+		cl = l.client.limiter()
+		l.clients[key] = cl
+	}
+
+	return cl
+}
+
+// clientKey returns the key of the bucket of the client of the request.
+func (l *RateLimiter) clientKey(r *http.Request) string {
+	if cert := auth.ClientCertificate(r); cert != nil {
+		return "cert:" + cert.Subject.CommonName
+	}
+	return "ip:" + clientIP(r, l.trustForwardedFor)
+}
+
+// clientIP returns the IP address of the client of the request, taken from the X-Forwarded-For header if it is
+// trusted.
+func clientIP(r *http.Request, trustForwardedFor bool) string {
+	if trustForwardedFor {
+		// The first address is the client, the others are the proxies it went through.
+		if ip, _, _ := strings.Cut(r.Header.Get(headerForwardedFor), ","); strings.TrimSpace(ip) != "" {
+			return strings.TrimSpace(ip)
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// LimitRequestBody rejects the requests with a body larger than the maximum with a 413. A request with a larger
+// Content-Length is rejected before the body is read, otherwise the body is cut off at the maximum. A maximum of zero
+// does not limit the body.
+func LimitRequestBody(maxBytes int64) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if maxBytes <= 0 || r.Body == nil || r.Body == http.NoBody {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if r.ContentLength > maxBytes {
+				rejectedRequests.WithLabelValues(rejectReasonBodySize).Inc()
+				uhttp.MustEncode(w, http.StatusRequestEntityTooLarge, uhttp.NewHTTPError(
+					http.StatusRequestEntityTooLarge,
+					fmt.Errorf("request body is larger than %d bytes", maxBytes),
+				))
+				return
+			}
+
+			r.Body = &limitedBody{ReadCloser: http.MaxBytesReader(w, r.Body, maxBytes)}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// limitedBody counts the request bodies cut off at the maximum size, the error is turned into a 413 by the handler.
+type limitedBody struct {
+	io.ReadCloser
+
+	// counted is true once the body has been counted as rejected.
+	counted bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+
+	maxBytesErr := new(http.MaxBytesError)
+	if err != nil && !b.counted && errors.As(err, &maxBytesErr) {
+		b.counted = true
+		rejectedRequests.WithLabelValues(rejectReasonBodySize).Inc()
+	}
+
+	return n, err
+}
@@ -0,0 +1,208 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/apis/specs/api"
+	repo "github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api"
+	"github.com/jacobbrewer1/uhttp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter_Limit(t *testing.T) {
+	newRequest := func(remoteAddr, forwardedFor, cn string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/reports", nil)
+		req.RemoteAddr = remoteAddr
+		if forwardedFor != "" {
+			req.Header.Set(headerForwardedFor, forwardedFor)
+		}
+		if cn != "" {
+			req.TLS = &tls.ConnectionState{
+				VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: cn}}}},
+			}
+		}
+		return req
+	}
+
+	tests := []struct {
+		name              string
+		global            RateLimit
+		client            RateLimit
+		trustForwardedFor bool
+		requests          []*http.Request
+		wantRejected      []bool
+	}{
+		{
+			name:   "per client IP",
+			client: RateLimit{RPS: 0.1, Burst: 1},
+			requests: []*http.Request{
+				newRequest("10.0.0.1:1234", "", ""),
+				newRequest("10.0.0.1:5678", "", ""),
+				newRequest("10.0.0.2:1234", "", ""),
+			},
+			wantRejected: []bool{false, true, false},
+		},
+		{
+			name:   "per client certificate",
+			client: RateLimit{RPS: 0.1, Burst: 1},
+			requests: []*http.Request{
+				newRequest("10.0.0.1:1234", "", "web-1"),
+				newRequest("10.0.0.1:1234", "", "web-2"),
+				newRequest("10.0.0.1:1234", "", "web-1"),
+			},
+			wantRejected: []bool{false, false, true},
+		},
+		{
+			name:              "trusted forwarded for",
+			client:            RateLimit{RPS: 0.1, Burst: 1},
+			trustForwardedFor: true,
+			requests: []*http.Request{
+				newRequest("10.0.0.1:1234", "192.168.0.1, 10.0.0.1", ""),
+				newRequest("10.0.0.1:1234", "192.168.0.2", ""),
+				newRequest("10.0.0.1:1234", "192.168.0.1", ""),
+			},
+			wantRejected: []bool{false, false, true},
+		},
+		{
+			name:   "untrusted forwarded for",
+			client: RateLimit{RPS: 0.1, Burst: 1},
+			requests: []*http.Request{
+				newRequest("10.0.0.1:1234", "192.168.0.1", ""),
+				newRequest("10.0.0.1:1234", "192.168.0.2", ""),
+			},
+			wantRejected: []bool{false, true},
+		},
+		{
+			name:   "global",
+			global: RateLimit{RPS: 0.1, Burst: 2},
+			requests: []*http.Request{
+				newRequest("10.0.0.1:1234", "", ""),
+				newRequest("10.0.0.2:1234", "", ""),
+				newRequest("10.0.0.3:1234", "", ""),
+			},
+			wantRejected: []bool{false, false, true},
+		},
+		{
+			name:   "rejected requests do not use the global tokens",
+			global: RateLimit{RPS: 0.1, Burst: 2},
+			client: RateLimit{RPS: 0.1, Burst: 1},
+			requests: []*http.Request{
+				newRequest("10.0.0.1:1234", "", ""),
+				newRequest("10.0.0.1:1234", "", ""),
+				newRequest("10.0.0.1:1234", "", ""),
+				newRequest("10.0.0.2:1234", "", ""),
+			},
+			wantRejected: []bool{false, true, true, false},
+		},
+		{
+			name: "not limited",
+			requests: []*http.Request{
+				newRequest("10.0.0.1:1234", "", ""),
+				newRequest("10.0.0.1:1234", "", ""),
+			},
+			wantRejected: []bool{false, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewRateLimiter(tt.global, tt.client, tt.trustForwardedFor)
+
+			for i, req := range tt.requests {
+				w := httptest.NewRecorder()
+				err := l.Limit(w, req)
+				if !tt.wantRejected[i] {
+					require.NoError(t, err, "request %d", i)
+					require.Empty(t, w.Header().Get(headerRetryAfter))
+					continue
+				}
+
+				httpErr := new(uhttp.HTTPError)
+				require.ErrorAs(t, err, &httpErr, "request %d", i)
+				require.Equal(t, http.StatusTooManyRequests, httpErr.StatusCode())
+				require.Equal(t, "10", w.Header().Get(headerRetryAfter))
+			}
+		})
+	}
+}
+
+func TestLimitRequestBody(t *testing.T) {
+	const maxBytes = 16
+
+	tests := []struct {
+		name          string
+		body          string
+		contentLength int64
+		wantStatus    int
+	}{
+		{
+			name:          "under the limit",
+			body:          "small",
+			contentLength: 5,
+			wantStatus:    http.StatusOK,
+		},
+		{
+			name:          "content length over the limit",
+			body:          strings.Repeat("a", maxBytes+1),
+			contentLength: maxBytes + 1,
+			wantStatus:    http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:          "chunked body over the limit",
+			body:          strings.Repeat("a", maxBytes+1),
+			contentLength: -1,
+			wantStatus:    http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := LimitRequestBody(maxBytes)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if _, err := io.ReadAll(r.Body); err != nil {
+					w.WriteHeader(http.StatusRequestEntityTooLarge)
+				}
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/reports", strings.NewReader(tt.body))
+			req.ContentLength = tt.contentLength
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			require.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestUploadLimits_Routes(t *testing.T) {
+	r := mux.NewRouter()
+	r.Use(LimitRequestBody(16))
+	api.RegisterHandlers(
+		r,
+		newService(repo.NewMockRepository(t)),
+		api.WithRateLimiter(NewRateLimiter(RateLimit{}, RateLimit{RPS: 0.1, Burst: 1}, false).Limit),
+	)
+
+	// The body is cut off before the report is parsed, the repository is not used.
+	req := httptest.NewRequest(http.MethodPost, "/reports", strings.NewReader(strings.Repeat("a", 32)))
+	req.Header.Set(uhttp.HeaderContentType, "application/x-www-form-urlencoded")
+	req.ContentLength = -1
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	// The client has used its token.
+	req = httptest.NewRequest(http.MethodPost, "/reports/queue", strings.NewReader("a"))
+	req.Header.Set(uhttp.HeaderContentType, "application/x-www-form-urlencoded")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Equal(t, "10", w.Header().Get(headerRetryAfter))
+}
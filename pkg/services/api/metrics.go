@@ -8,14 +8,185 @@ import (
 
 var appNameSuffix = utils.PackageName(&service{})
 
+// Names of the metrics exported by the service. These are used to generate the alerting rules, use MetricName to
+// get the name the metric is exported as.
+const (
+	MetricTotalReports            = "total_reports"
+	MetricRuntimeSeconds          = "runtime_seconds"
+	MetricHostLastRunTimestamp    = "host_last_run_timestamp_seconds"
+	MetricHostRuntimeSeconds      = "host_runtime_seconds"
+	MetricHostResources           = "host_resources"
+	MetricHostState               = "host_state"
+	MetricHostConsecutiveFailures = "host_consecutive_failures"
+	MetricIngestionErrors         = "ingestion_errors_total"
+	MetricQueueDepth              = "queue_depth"
+	MetricQueueProcessed          = "queue_processed_total"
+	MetricQueueWaitSeconds        = "queue_wait_seconds"
+	MetricRejectedRequests        = "rejected_requests_total"
+	MetricRuntimeRegressions      = "runtime_regressions_total"
+)
+
+// MetricName returns the fully qualified name the given service metric is exported as.
+func MetricName(name string) string {
+	return prometheus.BuildFQName(utils.AppName(appNameSuffix), "", name)
+}
+
+// metricNames are the names of every metric of the service, a metric added to the service is added here too.
+var metricNames = []string{
+	MetricTotalReports,
+	MetricRuntimeSeconds,
+	MetricHostLastRunTimestamp,
+	MetricHostRuntimeSeconds,
+	MetricHostResources,
+	MetricHostState,
+	MetricHostConsecutiveFailures,
+	MetricIngestionErrors,
+	MetricQueueDepth,
+	MetricQueueProcessed,
+	MetricQueueWaitSeconds,
+	MetricRejectedRequests,
+	MetricRuntimeRegressions,
+}
+
+// RegisteredMetricNames returns the fully qualified names of the metrics the service exports, so the alerting rules can
+// be checked against them.
+func RegisteredMetricNames() []string {
+	names := make([]string, len(metricNames))
+	for i, name := range metricNames {
+		names[i] = MetricName(name)
+	}
+	return names
+}
+
 var (
 	// totalReports is a counter for the total number of reports processed
 	totalReports = promauto.NewCounterVec(
 		prometheus.CounterOpts{
-			Name:      "total_reports",
+			Name:      MetricTotalReports,
 			Namespace: utils.AppName(appNameSuffix),
 			Help:      "Total number of reports processed",
 		},
 		[]string{"state", "environment"},
 	)
+
+	// runtimeSeconds is a histogram of the puppet run times per environment
+	runtimeSeconds = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:      MetricRuntimeSeconds,
+			Namespace: utils.AppName(appNameSuffix),
+			Help:      "Duration of the puppet runs per environment",
+			Buckets:   []float64{5, 10, 30, 60, 120, 300, 600, 1200, 1800, 3600},
+		},
+		[]string{"environment"},
+	)
+
+	// hostLastRunTimestamp is the time of the last puppet run of each host
+	hostLastRunTimestamp = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:      MetricHostLastRunTimestamp,
+			Namespace: utils.AppName(appNameSuffix),
+			Help:      "Unix timestamp of the last puppet run of the host",
+		},
+		[]string{"host", "environment"},
+	)
+
+	// hostRuntimeSeconds is the duration of the last puppet run of each host
+	hostRuntimeSeconds = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:      MetricHostRuntimeSeconds,
+			Namespace: utils.AppName(appNameSuffix),
+			Help:      "Duration of the last puppet run of the host",
+		},
+		[]string{"host", "environment"},
+	)
+
+	// hostResources is the number of resources in the last puppet run of each host
+	hostResources = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:      MetricHostResources,
+			Namespace: utils.AppName(appNameSuffix),
+			Help:      "Number of resources in the last puppet run of the host by state",
+		},
+		[]string{"host", "environment", "state"},
+	)
+
+	// hostState is set to 1 for the state of the last puppet run of each host and 0 for the others
+	hostState = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:      MetricHostState,
+			Namespace: utils.AppName(appNameSuffix),
+			Help:      "State of the last puppet run of the host",
+		},
+		[]string{"host", "environment", "state"},
+	)
+
+	// hostConsecutiveFailures is the number of failed puppet runs of each host since its last successful run
+	hostConsecutiveFailures = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:      MetricHostConsecutiveFailures,
+			Namespace: utils.AppName(appNameSuffix),
+			Help:      "Number of failed puppet runs of the host since its last successful run",
+		},
+		[]string{"host", "environment"},
+	)
+
+	// ingestionErrors is a counter of the reports that failed to be ingested by the stage they failed at
+	ingestionErrors = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:      MetricIngestionErrors,
+			Namespace: utils.AppName(appNameSuffix),
+			Help:      "Total number of reports that failed to be ingested by the stage they failed at",
+		},
+		[]string{"stage"},
+	)
+
+	// queueDepth is the number of reports in the ingestion queue by status
+	queueDepth = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:      MetricQueueDepth,
+			Namespace: utils.AppName(appNameSuffix),
+			Help:      "Number of reports in the ingestion queue by status",
+		},
+		[]string{"status"},
+	)
+
+	// queueProcessed is a counter of the queued reports processed by the workers by result
+	queueProcessed = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:      MetricQueueProcessed,
+			Namespace: utils.AppName(appNameSuffix),
+			Help:      "Total number of queued reports processed by result",
+		},
+		[]string{"result"},
+	)
+
+	// queueWaitSeconds is a histogram of how long reports wait in the ingestion queue before being processed
+	queueWaitSeconds = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:      MetricQueueWaitSeconds,
+			Namespace: utils.AppName(appNameSuffix),
+			Help:      "Time reports wait in the ingestion queue before being processed",
+			Buckets:   []float64{0.1, 0.5, 1, 5, 10, 30, 60, 300, 600, 1800},
+		},
+	)
+
+	// rejectedRequests is a counter of the requests rejected by the upload limits by reason
+	rejectedRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:      MetricRejectedRequests,
+			Namespace: utils.AppName(appNameSuffix),
+			Help:      "Total number of requests rejected by the rate and size limits by reason",
+		},
+		[]string{"reason"},
+	)
+
+	// runtimeRegressions is a counter of the reports whose runtime regressed from the baseline of their host
+	runtimeRegressions = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:      MetricRuntimeRegressions,
+			Namespace: utils.AppName(appNameSuffix),
+			Help:      "Total number of reports whose runtime regressed from the baseline of the host per environment",
+		},
+		[]string{"environment"},
+	)
 )
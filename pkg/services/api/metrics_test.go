@@ -0,0 +1,56 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricName_MatchesRegistered(t *testing.T) {
+	tests := []struct {
+		name      string
+		collector prometheus.Collector
+	}{
+		{name: MetricTotalReports, collector: totalReports},
+		{name: MetricRuntimeSeconds, collector: runtimeSeconds},
+		{name: MetricHostLastRunTimestamp, collector: hostLastRunTimestamp},
+		{name: MetricHostRuntimeSeconds, collector: hostRuntimeSeconds},
+		{name: MetricHostResources, collector: hostResources},
+		{name: MetricHostState, collector: hostState},
+		{name: MetricHostConsecutiveFailures, collector: hostConsecutiveFailures},
+		{name: MetricIngestionErrors, collector: ingestionErrors},
+		{name: MetricQueueDepth, collector: queueDepth},
+		{name: MetricQueueProcessed, collector: queueProcessed},
+		{name: MetricQueueWaitSeconds, collector: queueWaitSeconds},
+		{name: MetricRejectedRequests, collector: rejectedRequests},
+		{name: MetricRuntimeRegressions, collector: runtimeRegressions},
+	}
+
+	// Every metric of the service is checked.
+	names := make([]string, len(tests))
+	for i, tt := range tests {
+		names[i] = tt.name
+	}
+	require.ElementsMatch(t, metricNames, names)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			descs := make(chan *prometheus.Desc, 1)
+			tt.collector.Describe(descs)
+			close(descs)
+
+			desc := <-descs
+			require.NotNil(t, desc)
+			require.Contains(t, desc.String(), `fqName: "`+MetricName(tt.name)+`"`)
+		})
+	}
+}
+
+func TestRegisteredMetricNames(t *testing.T) {
+	names := RegisteredMetricNames()
+	require.NotEmpty(t, names)
+	require.Contains(t, names, MetricName(MetricHostConsecutiveFailures))
+	require.Contains(t, names, MetricName(MetricRuntimeRegressions))
+	require.NotContains(t, names, MetricName("unknown"))
+}
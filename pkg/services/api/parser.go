@@ -18,6 +18,43 @@ import (
 	"github.com/smallfish/simpleyaml"
 )
 
+// Stages of parsing a report, these are used to label the ingestion error metrics and the stored failures.
+const (
+	parseStageYAML           = "yaml"
+	parseStageHost           = "host"
+	parseStagePuppetVersion  = "puppet_version"
+	parseStageEnvironment    = "environment"
+	parseStageExecutionTime  = "execution_time"
+	parseStageStatus         = "status"
+	parseStageRuntime        = "runtime"
+	parseStageResourceStates = "resource_states"
+	parseStageResources      = "resources"
+)
+
+// ParseError is returned when a report fails to parse, it records the stage the parsing failed at.
+type ParseError struct {
+	// Stage is the stage of parsing that failed.
+	Stage string
+
+	// Err is the underlying error.
+	Err error
+}
+
+func newParseError(stage string, err error) *ParseError {
+	return &ParseError{
+		Stage: stage,
+		Err:   err,
+	}
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("parsing %s: %s", strings.ReplaceAll(e.Stage, "_", " "), e.Err.Error())
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
 type CompleteReport struct {
 	Report    *models.Report
 	Resources []*models.Resource
@@ -32,35 +69,35 @@ func parsePuppetReport(content []byte) (*CompleteReport, error) {
 
 	yaml, err := simpleyaml.NewYaml(content)
 	if err != nil {
-		return nil, errors.New("failed to parse YAML")
+		return nil, newParseError(parseStageYAML, err)
 	}
 
 	if err := parseHost(report, yaml); err != nil {
-		return nil, fmt.Errorf("parsing host: %w", err)
+		return nil, newParseError(parseStageHost, err)
 	}
 
 	if err := parsePuppetVersion(report, yaml); err != nil {
-		return nil, fmt.Errorf("parsing puppet version: %w", err)
+		return nil, newParseError(parseStagePuppetVersion, err)
 	}
 
 	if err := parseEnvironment(report, yaml); err != nil {
-		return nil, fmt.Errorf("parsing environment: %w", err)
+		return nil, newParseError(parseStageEnvironment, err)
 	}
 
 	if err := parseExecutionTime(report, yaml); err != nil {
-		return nil, fmt.Errorf("parsing execution time: %w", err)
+		return nil, newParseError(parseStageExecutionTime, err)
 	}
 
 	if err := parseStatus(report, yaml); err != nil {
-		return nil, fmt.Errorf("parsing status: %w", err)
+		return nil, newParseError(parseStageStatus, err)
 	}
 
 	if err := parseRuntime(report, yaml); err != nil {
-		return nil, fmt.Errorf("parsing runtime: %w", err)
+		return nil, newParseError(parseStageRuntime, err)
 	}
 
 	if err := parseResourceStates(report, yaml); err != nil {
-		return nil, fmt.Errorf("parsing resource states: %w", err)
+		return nil, newParseError(parseStageResourceStates, err)
 	}
 
 	complete.Report = report
@@ -71,7 +108,7 @@ func parsePuppetReport(content []byte) (*CompleteReport, error) {
 
 	resources, err := parseResources(yaml)
 	if err != nil {
-		return nil, fmt.Errorf("parsing resources: %w", err)
+		return nil, newParseError(parseStageResources, err)
 	}
 
 	sort.Slice(resources, func(i, j int) bool {
@@ -100,6 +137,21 @@ func parsePuppetReport(content []byte) (*CompleteReport, error) {
 	return complete, nil
 }
 
+// parseReportHost reads only the host of the report, so who the report is for can be checked without parsing it.
+func parseReportHost(content []byte) (string, error) {
+	yaml, err := simpleyaml.NewYaml(content)
+	if err != nil {
+		return "", newParseError(parseStageYAML, err)
+	}
+
+	rep := new(models.Report)
+	if err := parseHost(rep, yaml); err != nil {
+		return "", newParseError(parseStageHost, err)
+	}
+
+	return rep.Host, nil
+}
+
 // parseHost reads the `host` parameter from the YAML and populates
 // the given report-structure with suitable values.
 func parseHost(rep *models.Report, y *simpleyaml.Yaml) error {
@@ -146,7 +198,8 @@ func parseExecutionTime(rep *models.Report, y *simpleyaml.Yaml) error {
 		return errors.New("failed to parse 'time' from YAML")
 	}
 
-	rep.ExecutedAt = execTime
+	// Stored in UTC, so the execution times of reports from different time zones compare correctly.
+	rep.ExecutedAt = execTime.UTC()
 
 	return nil
 }
@@ -159,7 +212,7 @@ func parseStatus(rep *models.Report, y *simpleyaml.Yaml) error {
 		return errors.New("failed to get 'status' from YAML")
 	}
 
-	rep.State = usql.NewEnum(strings.ToUpper(s))
+	rep.State = usql.NewEnum(strings.ToLower(s))
 
 	return nil
 }
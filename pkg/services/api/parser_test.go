@@ -0,0 +1,68 @@
+package api
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePuppetReport_ParseErrorStage(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		stage   string
+	}{
+		{
+			name:    "invalid yaml",
+			content: "host: [web-1",
+			stage:   parseStageYAML,
+		},
+		{
+			name:    "missing host",
+			content: "puppet_version: 8.6.0",
+			stage:   parseStageHost,
+		},
+		{
+			name:    "host failing the security check",
+			content: "host: web-1; rm -rf /",
+			stage:   parseStageHost,
+		},
+		{
+			name:    "invalid puppet version",
+			content: "host: web-1\npuppet_version: latest",
+			stage:   parseStagePuppetVersion,
+		},
+		{
+			name:    "missing environment",
+			content: "host: web-1\npuppet_version: 8.6.0",
+			stage:   parseStageEnvironment,
+		},
+		{
+			name:    "invalid execution time",
+			content: "host: web-1\npuppet_version: 8.6.0\nenvironment: production\ntime: yesterday",
+			stage:   parseStageExecutionTime,
+		},
+		{
+			name:    "missing status",
+			content: "host: web-1\npuppet_version: 8.6.0\nenvironment: production\ntime: '2021-07-01T12:00:00Z'",
+			stage:   parseStageStatus,
+		},
+		{
+			name:    "missing runtime",
+			content: "host: web-1\npuppet_version: 8.6.0\nenvironment: production\ntime: '2021-07-01T12:00:00Z'\nstatus: changed",
+			stage:   parseStageRuntime,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parsePuppetReport([]byte(tt.content))
+			require.Error(t, err)
+
+			parseErr := new(ParseError)
+			require.True(t, errors.As(err, &parseErr))
+			require.Equal(t, tt.stage, parseErr.Stage)
+		})
+	}
+}
@@ -0,0 +1,371 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	repo "github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api/filters"
+)
+
+// pdbFieldType is the type of a field of a PuppetDB entity, it decides the operators and values a query can use.
+type pdbFieldType int
+
+const (
+	pdbString pdbFieldType = iota
+	pdbNumber
+	pdbTimestamp
+)
+
+// pdbEntity is an entity of the PuppetDB query API.
+type pdbEntity struct {
+	// name is the name of the entity in the path of the endpoint.
+	name string
+
+	// fields are the fields the queries can filter and order by, they are the columns of the view of the entity.
+	fields map[string]pdbFieldType
+
+	// output are the fields of the results, in the order PuppetDB has them. Extract can pick any of them.
+	output []string
+}
+
+var (
+	pdbNodes = &pdbEntity{
+		name: "nodes",
+		fields: map[string]pdbFieldType{
+			"certname":             pdbString,
+			"report_environment":   pdbString,
+			"latest_report_hash":   pdbString,
+			"latest_report_status": pdbString,
+			"report_timestamp":     pdbTimestamp,
+			"facts_timestamp":      pdbTimestamp,
+		},
+		output: []string{
+			"certname", "deactivated", "expired", "catalog_timestamp", "catalog_environment", "facts_timestamp",
+			"facts_environment", "report_timestamp", "report_environment", "latest_report_status",
+			"latest_report_hash", "latest_report_noop", "cached_catalog_status",
+		},
+	}
+
+	pdbReports = &pdbEntity{
+		name: "reports",
+		fields: map[string]pdbFieldType{
+			"hash":               pdbString,
+			"certname":           pdbString,
+			"environment":        pdbString,
+			"status":             pdbString,
+			"puppet_version":     pdbNumber,
+			"start_time":         pdbTimestamp,
+			"producer_timestamp": pdbTimestamp,
+			"receive_time":       pdbTimestamp,
+		},
+		output: []string{
+			"hash", "certname", "environment", "status", "puppet_version", "report_format",
+			"configuration_version", "transaction_uuid", "start_time", "end_time", "producer_timestamp",
+			"receive_time", "noop", "metrics",
+		},
+	}
+
+	pdbEvents = &pdbEntity{
+		name: "events",
+		fields: map[string]pdbFieldType{
+			"certname":       pdbString,
+			"report":         pdbString,
+			"environment":    pdbString,
+			"status":         pdbString,
+			"timestamp":      pdbTimestamp,
+			"run_start_time": pdbTimestamp,
+			"resource_type":  pdbString,
+			"resource_title": pdbString,
+			"file":           pdbString,
+			"line":           pdbNumber,
+		},
+		output: []string{
+			"certname", "report", "status", "timestamp", "run_start_time", "run_end_time", "report_receive_time",
+			"resource_type", "resource_title", "property", "new_value", "old_value", "message", "file", "line",
+			"containment_path", "containing_class", "environment", "configuration_version", "corrective_change",
+		},
+	}
+
+	pdbResources = &pdbEntity{
+		name: "resources",
+		fields: map[string]pdbFieldType{
+			"certname":    pdbString,
+			"environment": pdbString,
+			"type":        pdbString,
+			"title":       pdbString,
+			"file":        pdbString,
+			"line":        pdbNumber,
+		},
+		output: []string{
+			"certname", "resource", "type", "title", "tags", "exported", "file", "line", "parameters", "environment",
+		},
+	}
+)
+
+// pdbQuery is a parsed query of the PuppetDB AST query language.
+type pdbQuery struct {
+	// condition is the condition of the query, nil to match every result.
+	condition repo.Condition
+
+	// extract are the fields of the results to return, nil for every field.
+	extract []string
+}
+
+// parsePDBQuery parses a query of the PuppetDB AST query language on the entity. Only the operators =, ~, <, <=, >,
+// >=, and, or, not and extract are supported, and extract only at the top of the query.
+func parsePDBQuery(entity *pdbEntity, query string) (*pdbQuery, error) {
+	q := new(pdbQuery)
+	if strings.TrimSpace(query) == "" {
+		return q, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader([]byte(query)))
+	dec.UseNumber()
+
+	var ast any
+	if err := dec.Decode(&ast); err != nil {
+		return nil, fmt.Errorf("query is not valid JSON: %w", err)
+	}
+	if dec.More() {
+		return nil, errors.New("query has data after the JSON array")
+	}
+
+	args, op, err := pdbOperator(ast)
+	if err != nil {
+		return nil, err
+	}
+
+	if op == "extract" {
+		if len(args) != 1 && len(args) != 2 {
+			return nil, errors.New("extract takes the fields and an optional query")
+		}
+
+		q.extract, err = pdbExtractFields(entity, args[0])
+		if err != nil {
+			return nil, err
+		}
+
+		if len(args) == 1 {
+			return q, nil
+		}
+		ast = args[1]
+	}
+
+	q.condition, err = pdbCondition(entity, ast)
+	if err != nil {
+		return nil, err
+	}
+
+	return q, nil
+}
+
+// pdbOperator splits an expression of the query into its operator and arguments.
+func pdbOperator(ast any) ([]any, string, error) {
+	expr, ok := ast.([]any)
+	if !ok || len(expr) == 0 {
+		return nil, "", fmt.Errorf("expression %s is not an array of an operator and its arguments", pdbJSON(ast))
+	}
+
+	op, ok := expr[0].(string)
+	if !ok {
+		return nil, "", fmt.Errorf("operator %s is not a string", pdbJSON(expr[0]))
+	}
+
+	return expr[1:], strings.ToLower(op), nil
+}
+
+func pdbCondition(entity *pdbEntity, ast any) (repo.Condition, error) {
+	args, op, err := pdbOperator(ast)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case "and", "or":
+		if len(args) == 0 {
+			return nil, fmt.Errorf("%s needs at least one expression", op)
+		}
+
+		conds := make([]repo.Condition, len(args))
+		for i, arg := range args {
+			conds[i], err = pdbCondition(entity, arg)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if op == "and" {
+			return repo.AndCondition(conds), nil
+		}
+		return repo.OrCondition(conds), nil
+	case "not":
+		if len(args) != 1 {
+			return nil, errors.New("not takes exactly one expression")
+		}
+
+		cond, err := pdbCondition(entity, args[0])
+		if err != nil {
+			return nil, err
+		}
+		return repo.NotCondition{Condition: cond}, nil
+	case "=", "<", "<=", ">", ">=", "~":
+		return pdbComparison(entity, op, args)
+	case "extract":
+		return nil, errors.New("extract is only supported at the top of the query")
+	default:
+		return nil, fmt.Errorf("operator %q is not supported", op)
+	}
+}
+
+// pdbComparison parses the comparison of a field against a value, the value is converted to the type of the field.
+func pdbComparison(entity *pdbEntity, op string, args []any) (repo.Condition, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("%s takes a field and a value", op)
+	}
+
+	field, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("field %s is not a string", pdbJSON(args[0]))
+	}
+
+	typ, ok := entity.fields[field]
+	if !ok {
+		return nil, fmt.Errorf("%q is not a queryable field of %s", field, entity.name)
+	}
+
+	column := "t." + field
+
+	if op == "~" {
+		if typ != pdbString {
+			return nil, fmt.Errorf("~ is not supported on the field %q", field)
+		}
+
+		pattern, ok := args[1].(string)
+		if !ok {
+			return nil, fmt.Errorf("the pattern of %q is not a string", field)
+		}
+		if _, err := regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("the pattern of %q is not a regular expression: %w", field, err)
+		}
+
+		return repo.MatchCondition{Column: column, Pattern: pattern}, nil
+	}
+
+	if op != "=" && typ == pdbString {
+		return nil, fmt.Errorf("%s is not supported on the field %q", op, field)
+	}
+
+	value, err := pdbValue(typ, args[1])
+	if err != nil {
+		return nil, fmt.Errorf("the value of %q: %w", field, err)
+	}
+
+	return repo.CompareCondition{Column: column, Op: filters.CompareOp(op), Value: value}, nil
+}
+
+func pdbValue(typ pdbFieldType, v any) (any, error) {
+	switch typ {
+	case pdbNumber:
+		switch v := v.(type) {
+		case json.Number:
+			return v.Float64()
+		case string:
+			return json.Number(v).Float64()
+		}
+		return nil, fmt.Errorf("%s is not a number", pdbJSON(v))
+	case pdbTimestamp:
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s is not a timestamp", pdbJSON(v))
+		}
+
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a RFC 3339 timestamp", s)
+		}
+		return t.UTC(), nil
+	default:
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s is not a string", pdbJSON(v))
+		}
+		return s, nil
+	}
+}
+
+// pdbExtractFields returns the fields of an extract, a single field or an array of them.
+func pdbExtractFields(entity *pdbEntity, v any) ([]string, error) {
+	var fields []any
+	switch v := v.(type) {
+	case string:
+		fields = []any{v}
+	case []any:
+		fields = v
+	default:
+		return nil, fmt.Errorf("extract fields %s are not a field or an array of them", pdbJSON(v))
+	}
+
+	if len(fields) == 0 {
+		return nil, errors.New("extract needs at least one field")
+	}
+
+	extract := make([]string, len(fields))
+	for i, f := range fields {
+		name, ok := f.(string)
+		if !ok || !slices.Contains(entity.output, name) {
+			return nil, fmt.Errorf("%s is not a field of %s", pdbJSON(f), entity.name)
+		}
+		extract[i] = name
+	}
+
+	return extract, nil
+}
+
+// parsePDBOrderBy parses the order_by parameter, a JSON array of objects with a field and an order of asc or desc.
+func parsePDBOrderBy(entity *pdbEntity, orderBy string) ([]repo.PDBOrder, error) {
+	if strings.TrimSpace(orderBy) == "" {
+		return nil, nil
+	}
+
+	var items []struct {
+		Field string `json:"field"`
+		Order string `json:"order"`
+	}
+	if err := json.Unmarshal([]byte(orderBy), &items); err != nil {
+		return nil, fmt.Errorf("order_by is not an array of fields and orders: %w", err)
+	}
+
+	orders := make([]repo.PDBOrder, len(items))
+	for i, item := range items {
+		if _, ok := entity.fields[item.Field]; !ok {
+			return nil, fmt.Errorf("%q is not a field of %s that can be ordered by", item.Field, entity.name)
+		}
+
+		switch strings.ToLower(item.Order) {
+		case "", "asc":
+		case "desc":
+			orders[i].Descending = true
+		default:
+			return nil, fmt.Errorf("order %q of %q is not asc or desc", item.Order, item.Field)
+		}
+		orders[i].Field = item.Field
+	}
+
+	return orders, nil
+}
+
+// pdbJSON returns the JSON of a part of the query for the errors.
+func pdbJSON(v any) string {
+	bts, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(bts)
+}
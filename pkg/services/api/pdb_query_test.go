@@ -0,0 +1,142 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	repo "github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePDBQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		entity  *pdbEntity
+		query   string
+		want    *pdbQuery
+		wantErr bool
+	}{
+		{
+			name:   "empty",
+			entity: pdbNodes,
+			query:  "",
+			want:   new(pdbQuery),
+		},
+		{
+			name:   "equal",
+			entity: pdbNodes,
+			query:  `["=", "latest_report_status", "failed"]`,
+			want: &pdbQuery{
+				condition: repo.CompareCondition{Column: "t.latest_report_status", Op: "=", Value: "failed"},
+			},
+		},
+		{
+			name:   "and, or and not",
+			entity: pdbReports,
+			query:  `["and", ["or", ["=", "status", "failed"], ["=", "status", "changed"]], ["not", ["~", "certname", "^build-"]]]`,
+			want: &pdbQuery{
+				condition: repo.AndCondition{
+					repo.OrCondition{
+						repo.CompareCondition{Column: "t.status", Op: "=", Value: "failed"},
+						repo.CompareCondition{Column: "t.status", Op: "=", Value: "changed"},
+					},
+					repo.NotCondition{Condition: repo.MatchCondition{Column: "t.certname", Pattern: "^build-"}},
+				},
+			},
+		},
+		{
+			name:   "timestamp",
+			entity: pdbReports,
+			query:  `[">", "start_time", "2024-12-24T13:00:00+01:00"]`,
+			want: &pdbQuery{
+				condition: repo.CompareCondition{Column: "t.start_time", Op: ">", Value: time.Date(2024, 12, 24, 12, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name:   "number",
+			entity: pdbEvents,
+			query:  `["<", "line", 10]`,
+			want: &pdbQuery{
+				condition: repo.CompareCondition{Column: "t.line", Op: "<", Value: float64(10)},
+			},
+		},
+		{
+			name:   "extract",
+			entity: pdbNodes,
+			query:  `["extract", ["certname", "deactivated"], ["=", "report_environment", "production"]]`,
+			want: &pdbQuery{
+				condition: repo.CompareCondition{Column: "t.report_environment", Op: "=", Value: "production"},
+				extract:   []string{"certname", "deactivated"},
+			},
+		},
+		{
+			name:   "extract without a query",
+			entity: pdbNodes,
+			query:  `["extract", "certname"]`,
+			want:   &pdbQuery{extract: []string{"certname"}},
+		},
+		{
+			name:    "unknown field",
+			entity:  pdbNodes,
+			query:   `["=", "password", "x"]`,
+			wantErr: true,
+		},
+		{
+			name:    "unsupported operator",
+			entity:  pdbNodes,
+			query:   `["in", "certname", ["extract", "certname"]]`,
+			wantErr: true,
+		},
+		{
+			name:    "nested extract",
+			entity:  pdbNodes,
+			query:   `["not", ["extract", "certname"]]`,
+			wantErr: true,
+		},
+		{
+			name:    "order of a string",
+			entity:  pdbNodes,
+			query:   `["<", "certname", "m"]`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid regular expression",
+			entity:  pdbNodes,
+			query:   `["~", "certname", "("]`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid timestamp",
+			entity:  pdbNodes,
+			query:   `["<", "report_timestamp", "yesterday"]`,
+			wantErr: true,
+		},
+		{
+			name:    "not JSON",
+			entity:  pdbNodes,
+			query:   `[=, certname, web-1]`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePDBQuery(tt.entity, tt.query)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParsePDBOrderBy(t *testing.T) {
+	got, err := parsePDBOrderBy(pdbReports, `[{"field": "certname"}, {"field": "start_time", "order": "DESC"}]`)
+	require.NoError(t, err)
+	require.Equal(t, []repo.PDBOrder{{Field: "certname"}, {Field: "start_time", Descending: true}}, got)
+
+	_, err = parsePDBOrderBy(pdbReports, `[{"field": "id; DROP TABLE report"}]`)
+	require.Error(t, err)
+}
@@ -0,0 +1,260 @@
+package api
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jacobbrewer1/puppet-reporter/pkg/apis/specs/api"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/auth"
+	repo "github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api"
+	"github.com/jacobbrewer1/uhttp"
+)
+
+// pdbParams are the parameters every endpoint of the PuppetDB query API has.
+type pdbParams struct {
+	query   *string
+	limit   *int
+	offset  *int
+	orderBy *string
+}
+
+func (s *service) QueryPDBNodes(l *slog.Logger, r *http.Request, params api.QueryPDBNodesParams) (*api.PdbResults, error) {
+	q, query, err := pdbRepositoryQuery(r, pdbNodes, &pdbParams{params.Query, params.Limit, params.Offset, params.OrderBy})
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := s.r.GetPDBNodes(query)
+	if err != nil {
+		return nil, uhttp.NewHTTPError(http.StatusInternalServerError, err, "failed to query nodes")
+	}
+
+	results := make(api.PdbResults, len(nodes))
+	for i, node := range nodes {
+		results[i] = pdbExtract(q, modelAsPDBNode(node))
+	}
+
+	return &results, nil
+}
+
+func (s *service) QueryPDBReports(l *slog.Logger, r *http.Request, params api.QueryPDBReportsParams) (*api.PdbResults, error) {
+	q, query, err := pdbRepositoryQuery(r, pdbReports, &pdbParams{params.Query, params.Limit, params.Offset, params.OrderBy})
+	if err != nil {
+		return nil, err
+	}
+
+	reports, err := s.r.GetPDBReports(query)
+	if err != nil {
+		return nil, uhttp.NewHTTPError(http.StatusInternalServerError, err, "failed to query reports")
+	}
+
+	results := make(api.PdbResults, len(reports))
+	for i, report := range reports {
+		results[i] = pdbExtract(q, modelAsPDBReport(report))
+	}
+
+	return &results, nil
+}
+
+func (s *service) QueryPDBEvents(l *slog.Logger, r *http.Request, params api.QueryPDBEventsParams) (*api.PdbResults, error) {
+	q, query, err := pdbRepositoryQuery(r, pdbEvents, &pdbParams{params.Query, params.Limit, params.Offset, params.OrderBy})
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := s.r.GetPDBEvents(query)
+	if err != nil {
+		return nil, uhttp.NewHTTPError(http.StatusInternalServerError, err, "failed to query events")
+	}
+
+	results := make(api.PdbResults, len(events))
+	for i, event := range events {
+		results[i] = pdbExtract(q, modelAsPDBEvent(event))
+	}
+
+	return &results, nil
+}
+
+func (s *service) QueryPDBResources(l *slog.Logger, r *http.Request, params api.QueryPDBResourcesParams) (*api.PdbResults, error) {
+	q, query, err := pdbRepositoryQuery(r, pdbResources, &pdbParams{params.Query, params.Limit, params.Offset, params.OrderBy})
+	if err != nil {
+		return nil, err
+	}
+
+	resources, err := s.r.GetPDBResources(query)
+	if err != nil {
+		return nil, uhttp.NewHTTPError(http.StatusInternalServerError, err, "failed to query resources")
+	}
+
+	results := make(api.PdbResults, len(resources))
+	for i, resource := range resources {
+		results[i] = pdbExtract(q, modelAsPDBResource(resource))
+	}
+
+	return &results, nil
+}
+
+// pdbRepositoryQuery parses the parameters of the request into the query of the repository, restricted to the
+// environments the reader can read.
+func pdbRepositoryQuery(r *http.Request, entity *pdbEntity, params *pdbParams) (*pdbQuery, *repo.PDBQuery, error) {
+	q := new(pdbQuery)
+	if params.query != nil {
+		var err error
+		q, err = parsePDBQuery(entity, *params.query)
+		if err != nil {
+			return nil, nil, uhttp.NewHTTPError(http.StatusBadRequest, err, "invalid query")
+		}
+	}
+
+	query := &repo.PDBQuery{
+		Condition: q.condition,
+	}
+
+	if params.orderBy != nil {
+		orderBy, err := parsePDBOrderBy(entity, *params.orderBy)
+		if err != nil {
+			return nil, nil, uhttp.NewHTTPError(http.StatusBadRequest, err, "invalid order_by")
+		}
+		query.OrderBy = orderBy
+	}
+
+	if params.limit != nil {
+		if *params.limit < 1 {
+			return nil, nil, uhttp.NewHTTPError(http.StatusBadRequest, errors.New("limit must be at least 1"))
+		}
+		query.Limit = *params.limit
+	}
+
+	if params.offset != nil {
+		if *params.offset < 0 {
+			return nil, nil, uhttp.NewHTTPError(http.StatusBadRequest, errors.New("offset cannot be negative"))
+		}
+		query.Offset = *params.offset
+	}
+
+	if envs, restricted := auth.AccessFromContext(r.Context()).Environments(); restricted {
+		query.Environments = envs
+	}
+
+	return q, query, nil
+}
+
+// pdbExtract returns the fields of the result the query extracts, every field if it extracts none.
+func pdbExtract(q *pdbQuery, result map[string]any) map[string]any {
+	if q.extract == nil {
+		return result
+	}
+
+	extracted := make(map[string]any, len(q.extract))
+	for _, field := range q.extract {
+		extracted[field] = result[field]
+	}
+
+	return extracted
+}
+
+// The fields PuppetDB has that puppet-reporter does not keep are null, or the value PuppetDB has when the agent does
+// not send them.
+
+func modelAsPDBNode(node *repo.PDBNode) map[string]any {
+	var factsTimestamp any
+	if node.FactsTimestamp != nil {
+		factsTimestamp = pdbTime(*node.FactsTimestamp)
+	}
+
+	return map[string]any{
+		"certname":              node.Certname,
+		"deactivated":           nil,
+		"expired":               nil,
+		"catalog_timestamp":     nil,
+		"catalog_environment":   nil,
+		"facts_timestamp":       factsTimestamp,
+		"facts_environment":     nil,
+		"report_timestamp":      pdbTime(node.ReportTimestamp),
+		"report_environment":    node.ReportEnvironment,
+		"latest_report_status":  node.LatestReportStatus,
+		"latest_report_hash":    node.LatestReportHash,
+		"latest_report_noop":    false,
+		"cached_catalog_status": nil,
+	}
+}
+
+func modelAsPDBReport(report *repo.PDBReport) map[string]any {
+	return map[string]any{
+		"hash":                  report.Hash,
+		"certname":              report.Certname,
+		"environment":           report.Environment,
+		"status":                report.Status,
+		"puppet_version":        strconv.FormatFloat(report.PuppetVersion, 'f', -1, 64),
+		"report_format":         nil,
+		"configuration_version": nil,
+		"transaction_uuid":      nil,
+		"start_time":            pdbTime(report.StartTime),
+		"end_time":              pdbTime(report.StartTime.Add(time.Duration(report.Runtime) * time.Second)),
+		"producer_timestamp":    pdbTime(report.ProducerTimestamp),
+		"receive_time":          pdbTime(report.ReceiveTime),
+		"noop":                  false,
+		"metrics": map[string]any{
+			"data": []map[string]any{
+				{"category": "resources", "name": "total", "value": report.Total},
+				{"category": "resources", "name": "changed", "value": report.Changed},
+				{"category": "resources", "name": "failed", "value": report.Failed},
+				{"category": "resources", "name": "skipped", "value": report.Skipped},
+				{"category": "time", "name": "total", "value": report.Runtime},
+			},
+		},
+	}
+}
+
+func modelAsPDBEvent(event *repo.PDBEvent) map[string]any {
+	return map[string]any{
+		"certname":              event.Certname,
+		"report":                event.Report,
+		"status":                event.Status,
+		"timestamp":             pdbTime(event.Timestamp),
+		"run_start_time":        pdbTime(event.RunStartTime),
+		"run_end_time":          pdbTime(event.RunStartTime.Add(time.Duration(event.Runtime) * time.Second)),
+		"report_receive_time":   pdbTime(event.RunStartTime),
+		"resource_type":         event.ResourceType,
+		"resource_title":        event.ResourceTitle,
+		"property":              nil,
+		"new_value":             nil,
+		"old_value":             nil,
+		"message":               nil,
+		"file":                  event.File,
+		"line":                  event.Line,
+		"containment_path":      nil,
+		"containing_class":      nil,
+		"environment":           event.Environment,
+		"configuration_version": nil,
+		"corrective_change":     nil,
+	}
+}
+
+func modelAsPDBResource(resource *repo.PDBResource) map[string]any {
+	// PuppetDB identifies a resource by a SHA-1 of it, the type and title are what make it unique in a catalog.
+	sum := sha1.Sum([]byte(resource.Type + "[" + resource.Title + "]"))
+
+	return map[string]any{
+		"certname":    resource.Certname,
+		"resource":    hex.EncodeToString(sum[:]),
+		"type":        resource.Type,
+		"title":       resource.Title,
+		"tags":        []string{},
+		"exported":    false,
+		"file":        resource.File,
+		"line":        resource.Line,
+		"parameters":  map[string]any{},
+		"environment": resource.Environment,
+	}
+}
+
+// pdbTime formats a time as PuppetDB does.
+func pdbTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000Z")
+}
@@ -0,0 +1,301 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jacobbrewer1/puppet-reporter/pkg/apis/specs/api"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/logging"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+	repo "github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api"
+	"github.com/jacobbrewer1/uhttp"
+	"github.com/jacobbrewer1/utils"
+)
+
+const (
+	// queueResultRetry is the result of a queued report that failed with a server error and was returned to the queue.
+	queueResultRetry = "retry"
+)
+
+func (s *service) QueueReport(l *slog.Logger, r *http.Request, body0 *api.QueueReportRequestBody) (*api.ReportQueueStatus, error) {
+	if !s.async {
+		return nil, uhttp.NewHTTPError(http.StatusNotFound, errors.New("asynchronous ingestion is not enabled"))
+	}
+
+	cert, err := s.uploadCertificate(r)
+	if err != nil {
+		return nil, err
+	}
+
+	bts, err := body0.File.Bytes()
+	if err != nil {
+		ingestionErrors.WithLabelValues(ingestionStageRead).Inc()
+		return nil, uhttp.NewHTTPError(http.StatusBadRequest, err, "error reading file")
+	} else if len(bts) == 0 {
+		ingestionErrors.WithLabelValues(ingestionStageRead).Inc()
+		return nil, uhttp.NewHTTPError(http.StatusBadRequest, errors.New("empty report"), "error reading file")
+	}
+
+	// The workers do not know who uploaded the report, so the host is checked before it is queued.
+	if cert != nil {
+		host, err := parseReportHost(bts)
+		if err != nil {
+			s.recordIngestionFailure(l, bts, err)
+			return nil, uhttp.NewHTTPError(http.StatusBadRequest, err, "error parsing report")
+		}
+
+		if err := checkReportHost(l, cert, host); err != nil {
+			return nil, err
+		}
+	}
+
+	// The report is only parsed by the workers, but a report that was already ingested can be rejected early.
+	hash := utils.Sha256(bts)
+	existingRep, err := s.r.GetReportByHash(hash)
+	if err != nil && !errors.Is(err, repo.ErrReportNotFound) {
+		return nil, uhttp.NewHTTPError(http.StatusInternalServerError, err, "error getting report")
+	} else if existingRep != nil {
+		return nil, uhttp.NewHTTPError(http.StatusConflict, fmt.Errorf("report with hash %s already exists", hash), "report already exists")
+	}
+
+	now := time.Now().UTC()
+	item := &models.ReportQueue{
+		Hash:      hash,
+		Status:    models.ReportQueueStatusPending,
+		Payload:   bts,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.r.QueueReport(item); err != nil {
+		return nil, uhttp.NewHTTPError(http.StatusInternalServerError, err, "error queueing report")
+	}
+
+	return s.modelAsApiQueueStatus(item), nil
+}
+
+func (s *service) GetQueuedReport(l *slog.Logger, r *http.Request, id int64) (*api.ReportQueueStatus, error) {
+	item, err := s.r.GetQueuedReport(int(id))
+	if err != nil {
+		switch {
+		case errors.Is(err, repo.ErrQueuedReportNotFound):
+			return nil, uhttp.NewHTTPError(http.StatusNotFound, err, "queued report not found", fmt.Sprintf("id: %d", id))
+		default:
+			return nil, uhttp.NewHTTPError(http.StatusInternalServerError, err, "failed to get queued report", fmt.Sprintf("id: %d", id))
+		}
+	}
+
+	return s.modelAsApiQueueStatus(item), nil
+}
+
+func (s *service) modelAsApiQueueStatus(item *models.ReportQueue) *api.ReportQueueStatus {
+	resp := &api.ReportQueueStatus{
+		CreatedAt: item.CreatedAt,
+		Hash:      item.Hash,
+		Id:        int64(item.Id),
+		Status:    api.QueueStatus(strings.ToLower(string(item.Status))),
+		StatusUrl: fmt.Sprintf("/reports/queue/%d", item.Id),
+		UpdatedAt: item.UpdatedAt,
+	}
+
+	if item.Error != "" {
+		resp.Error = utils.Ptr(item.Error)
+	}
+
+	return resp
+}
+
+// WorkerPool parses and saves the reports queued for asynchronous ingestion.
+type WorkerPool struct {
+	// s is the service used to ingest the reports.
+	s *service
+
+	// workers is the number of reports processed concurrently.
+	workers int
+
+	// pollInterval is how often the queue is checked for new reports when it is empty.
+	pollInterval time.Duration
+
+	// staleAfter is how long a report can be processing before it is assumed the worker died and it is queued again.
+	staleAfter time.Duration
+}
+
+// NewWorkerPool creates a new WorkerPool. The service options should match the ones the service was created with.
+func NewWorkerPool(r repo.Repository, workers int, pollInterval, staleAfter time.Duration, opts ...ServiceOption) (*WorkerPool, error) {
+	if workers < 1 {
+		return nil, errors.New("at least one worker is required")
+	} else if pollInterval <= 0 {
+		return nil, errors.New("poll interval must be positive")
+	} else if staleAfter <= 0 {
+		return nil, errors.New("stale after must be positive")
+	}
+
+	return &WorkerPool{
+		s:            newService(r, opts...),
+		workers:      workers,
+		pollInterval: pollInterval,
+		staleAfter:   staleAfter,
+	}, nil
+}
+
+// Run processes the queue until the context is cancelled, it waits for the reports being processed to finish. Only as
+// many reports are claimed as there are idle workers, so a claimed report is not left waiting for a worker while
+// other replicas could process it.
+func (p *WorkerPool) Run(ctx context.Context) {
+	// The jobs never outnumber the workers, so handing a report to a worker does not block.
+	jobs := make(chan *models.ReportQueue, p.workers)
+	idle := make(chan struct{}, p.workers)
+
+	wg := new(sync.WaitGroup)
+	for i := 0; i < p.workers; i++ {
+		idle <- struct{}{}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range jobs {
+				p.process(item)
+				idle <- struct{}{}
+			}
+		}()
+	}
+
+	defer func() {
+		close(jobs)
+		wg.Wait()
+	}()
+
+	ticker := time.NewTicker(p.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		p.maintain()
+
+		// Wait for a worker to be idle.
+		select {
+		case <-idle:
+		case <-ctx.Done():
+			return
+		}
+
+		available := 1
+		for len(idle) > 0 {
+			<-idle
+			available++
+		}
+
+		claimed, err := p.s.r.ClaimQueuedReports(available)
+		if err != nil {
+			slog.Error("Error claiming queued reports", slog.String(logging.KeyError, err.Error()))
+		}
+
+		for _, item := range claimed {
+			jobs <- item
+		}
+
+		for i := len(claimed); i < available; i++ {
+			idle <- struct{}{}
+		}
+
+		// Keep going while the queue is full, otherwise wait for more reports.
+		if len(claimed) == available {
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// maintain queues the reports of workers that died again and updates the queue depth metrics.
+func (p *WorkerPool) maintain() {
+	n, err := p.s.r.RequeueStaleReports(time.Now().UTC().Add(-p.staleAfter))
+	if err != nil {
+		slog.Error("Error requeueing stale reports", slog.String(logging.KeyError, err.Error()))
+	} else if n > 0 {
+		slog.Warn("Requeued stale reports", slog.Int64("count", n))
+	}
+
+	depth, err := p.s.r.GetQueueDepth()
+	if err != nil {
+		slog.Error("Error getting queue depth", slog.String(logging.KeyError, err.Error()))
+		return
+	}
+
+	for status, d := range depth {
+		queueDepth.WithLabelValues(status).Set(float64(d))
+	}
+}
+
+// process ingests a queued report and records the result on the queue.
+func (p *WorkerPool) process(item *models.ReportQueue) {
+	l := slog.With(slog.Int("queue_id", item.Id), slog.String("hash", item.Hash))
+
+	// The report is stale from when it is started, not from when it was claimed.
+	if err := p.s.r.StartQueuedReport(item.Id, item.Claim); err != nil {
+		if errors.Is(err, repo.ErrQueuedReportNotClaimed) {
+			l.Warn("Queued report was queued again before it was started, leaving it to the new claim")
+		} else {
+			l.Error("Error starting queued report, it is queued again once it is stale", slog.String(logging.KeyError, err.Error()))
+		}
+		return
+	}
+
+	queueWaitSeconds.Observe(time.Since(item.CreatedAt).Seconds())
+
+	status := models.ReportQueueStatusDone
+	errMsg := ""
+
+	if _, err := p.s.ingestReport(l, item.Payload, nil); err != nil {
+		httpErr := new(uhttp.HTTPError)
+		if errors.As(err, &httpErr) && httpErr.StatusCode() >= http.StatusInternalServerError {
+			l.Error("Error ingesting queued report, returning it to the queue", slog.String(logging.KeyError, err.Error()))
+			if err := p.s.r.UpdateQueuedReportStatus(item.Id, item.Claim, models.ReportQueueStatusPending, err.Error()); err != nil {
+				l.Error("Error returning report to the queue", slog.String(logging.KeyError, err.Error()))
+			}
+			queueProcessed.WithLabelValues(queueResultRetry).Inc()
+			return
+		}
+
+		l.Warn("Queued report failed to be ingested", slog.String(logging.KeyError, err.Error()))
+		status = models.ReportQueueStatusFailed
+		errMsg = err.Error()
+	}
+
+	if err := p.s.r.UpdateQueuedReportStatus(item.Id, item.Claim, status, errMsg); err != nil {
+		if errors.Is(err, repo.ErrQueuedReportNotClaimed) {
+			// The report was thought to be stale and queued again, the status is left to the worker that claimed it.
+			l.Warn("Queued report was queued again while it was processed, leaving its status to the new claim")
+			return
+		}
+		l.Error("Error updating queued report status", slog.String(logging.KeyError, err.Error()))
+	}
+
+	queueProcessed.WithLabelValues(string(status)).Inc()
+}
+
+// QueueUploads serves report uploads from the queue endpoint, so agents do not need to be reconfigured to use the
+// asynchronous ingestion. The summary of a queued report is not known until a worker has ingested it, so an upload
+// asking for it is answered with the 202 status of the queued report, like any other upload.
+func QueueUploads(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/reports" {
+			r.URL.Path = "/reports/queue"
+			r.URL.RawPath = ""
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,163 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jacobbrewer1/goschema/usql"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+	repo "github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerPool_Process(t *testing.T) {
+	validPayload := []byte(`host: web-1
+puppet_version: 8.6.0
+environment: production
+time: '2021-07-01T12:00:00Z'
+status: changed
+metrics:
+  time:
+    values:
+      - - total
+        - Total
+        - 12.5
+  resources:
+    values:
+      - - total
+        - Total
+        - 10
+      - - failed
+        - Failed
+        - 0
+      - - skipped
+        - Skipped
+        - 0
+      - - changed
+        - Changed
+        - 2
+resource_statuses: {}
+`)
+
+	tests := []struct {
+		name       string
+		payload    []byte
+		setup      func(r *repo.MockRepository)
+		startErr   error
+		wantStatus string
+		updateErr  error
+	}{
+		{
+			name:    "invalid report fails",
+			payload: []byte("puppet_version: 8.6.0"),
+			setup: func(r *repo.MockRepository) {
+				r.On("SaveIngestionFailure", mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			wantStatus: string(models.ReportQueueStatusFailed),
+		},
+		{
+			name:    "database error is retried",
+			payload: validPayload,
+			setup: func(r *repo.MockRepository) {
+				r.On("GetReportByHash", mock.Anything).Return(nil, errors.New("connection refused")).Once()
+			},
+			wantStatus: string(models.ReportQueueStatusPending),
+		},
+		{
+			name:    "duplicate report fails",
+			payload: validPayload,
+			setup: func(r *repo.MockRepository) {
+				r.On("GetReportByHash", mock.Anything).Return(new(models.Report), nil).Once()
+			},
+			wantStatus: string(models.ReportQueueStatusFailed),
+		},
+		{
+			name:    "claim lost while processing",
+			payload: []byte("puppet_version: 8.6.0"),
+			setup: func(r *repo.MockRepository) {
+				r.On("SaveIngestionFailure", mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			wantStatus: string(models.ReportQueueStatusFailed),
+			updateErr:  repo.ErrQueuedReportNotClaimed,
+		},
+		{
+			name:     "claim lost before it was started",
+			payload:  validPayload,
+			setup:    func(r *repo.MockRepository) {},
+			startErr: repo.ErrQueuedReportNotClaimed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := repo.NewMockRepository(t)
+			tt.setup(r)
+			r.On("StartQueuedReport", 1, "claim").Return(tt.startErr).Once()
+			if tt.wantStatus != "" {
+				r.On("UpdateQueuedReportStatus", 1, "claim", mock.MatchedBy(func(s usql.Enum) bool {
+					return string(s) == tt.wantStatus
+				}), mock.Anything).Return(tt.updateErr).Once()
+			}
+
+			p, err := NewWorkerPool(r, 1, time.Second, time.Minute)
+			require.NoError(t, err)
+
+			p.process(&models.ReportQueue{
+				Id:        1,
+				Payload:   tt.payload,
+				CreatedAt: time.Now(),
+				Claim:     "claim",
+			})
+		})
+	}
+}
+
+func TestQueueUploads(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   string
+	}{
+		{
+			name:   "upload is queued",
+			method: http.MethodPost,
+			path:   "/reports",
+			want:   "/reports/queue",
+		},
+		{
+			name:   "upload asking for the summary is queued",
+			method: http.MethodPost,
+			path:   "/reports?summary=true",
+			want:   "/reports/queue",
+		},
+		{
+			name:   "listing reports is unchanged",
+			method: http.MethodGet,
+			path:   "/reports",
+			want:   "/reports",
+		},
+		{
+			name:   "queue status is unchanged",
+			method: http.MethodGet,
+			path:   "/reports/queue/1",
+			want:   "/reports/queue/1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			h := QueueUploads(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.URL.Path
+			}))
+
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.path, nil))
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
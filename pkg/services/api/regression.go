@@ -0,0 +1,108 @@
+package api
+
+import (
+	"log/slog"
+	"math"
+	"slices"
+
+	"github.com/jacobbrewer1/puppet-reporter/pkg/logging"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+)
+
+const (
+	// DefaultRegressionWindow is how many of the previous runs of a host its baseline runtime is calculated over.
+	DefaultRegressionWindow = 20
+
+	// DefaultRegressionFactor is how many deviations above the baseline runtime a run must take to be a regression.
+	DefaultRegressionFactor = 3.0
+
+	// minBaselineRuns is how many previous runs a host must have before its runs are checked, as the median of a
+	// couple of runs is not a baseline.
+	minBaselineRuns = 5
+
+	// madScale scales the median absolute deviation to the standard deviation of normally distributed runtimes, so the
+	// factor reads as the number of standard deviations.
+	madScale = 1.4826
+)
+
+// runtimeBaseline is the usual runtime of a host, from the median and the median absolute deviation of its previous
+// runs. These are used over the mean and the standard deviation so a few slow runs do not raise the baseline.
+type runtimeBaseline struct {
+	median    float64
+	deviation float64
+}
+
+// newRuntimeBaseline returns the baseline of the runtimes, or nil if there are too few runtimes for a baseline.
+func newRuntimeBaseline(runtimes []int) *runtimeBaseline {
+	if len(runtimes) < minBaselineRuns {
+		return nil
+	}
+
+	values := make([]float64, len(runtimes))
+	for i, runtime := range runtimes {
+		values[i] = float64(runtime)
+	}
+
+	med := median(values)
+
+	deviations := make([]float64, len(values))
+	for i, v := range values {
+		deviations[i] = math.Abs(v - med)
+	}
+
+	return &runtimeBaseline{
+		median:    med,
+		deviation: median(deviations) * madScale,
+	}
+}
+
+// limit is the longest a run can take before it is a regression. The runtimes are whole seconds, so a host that
+// takes the same time every run has no deviation at all; the deviation is kept to at least a tenth of the median,
+// and a second, so that a run a second slower than usual is not a regression.
+func (b *runtimeBaseline) limit(factor float64) float64 {
+	deviation := max(b.deviation, b.median/10, 1)
+	return b.median + factor*deviation
+}
+
+// median returns the median of the values, the values are sorted in place.
+func median(values []float64) float64 {
+	slices.Sort(values)
+
+	mid := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[mid-1] + values[mid]) / 2
+	}
+	return values[mid]
+}
+
+// checkRuntimeRegression flags the report if its runtime regressed from the baseline of the previous runs of its host.
+// The report is still ingested if the previous runs cannot be read, it is just not flagged.
+func (s *service) checkRuntimeRegression(l *slog.Logger, report *models.Report) {
+	if s.regressionWindow <= 0 {
+		return
+	}
+
+	runtimes, err := s.r.GetRecentRuntimes(report, s.regressionWindow)
+	if err != nil {
+		l.Error("Error getting recent runtimes", slog.String(logging.KeyError, err.Error()))
+		return
+	}
+
+	baseline := newRuntimeBaseline(runtimes)
+	if baseline == nil {
+		return
+	}
+
+	limit := baseline.limit(s.regressionFactor)
+	if float64(report.Runtime) <= limit {
+		return
+	}
+
+	report.RuntimeRegression = true
+	l.Info("Runtime regressed from the baseline",
+		slog.String("host", report.Host),
+		slog.Int("runtime", report.Runtime),
+		slog.Float64("median", baseline.median),
+		slog.Float64("limit", limit),
+	)
+}
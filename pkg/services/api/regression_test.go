@@ -0,0 +1,76 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+	repo "github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_CheckRuntimeRegression(t *testing.T) {
+	tests := []struct {
+		name     string
+		runtime  int
+		runtimes []int
+		err      error
+		want     bool
+	}{
+		{
+			name:     "well above a steady baseline",
+			runtime:  120,
+			runtimes: []int{60, 60, 60, 60, 60, 60},
+			want:     true,
+		},
+		{
+			// The deviation of a steady host is kept to a tenth of its median, so the limit is 60 + 3*6.
+			name:     "a little above a steady baseline",
+			runtime:  75,
+			runtimes: []int{60, 60, 60, 60, 60, 60},
+		},
+		{
+			name:     "within a noisy baseline",
+			runtime:  120,
+			runtimes: []int{40, 90, 60, 110, 50, 70},
+		},
+		{
+			// A single slow run does not move the median, so the next slow run is still a regression.
+			name:     "slow run in the baseline",
+			runtime:  300,
+			runtimes: []int{30, 600, 30, 31, 29, 30},
+			want:     true,
+		},
+		{
+			name:     "too few runs",
+			runtime:  600,
+			runtimes: []int{30, 30, 30},
+		},
+		{
+			name:    "error getting runtimes",
+			runtime: 600,
+			err:     errors.New("boom"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := &models.Report{Host: "web-1", Runtime: tt.runtime}
+
+			r := repo.NewMockRepository(t)
+			r.On("GetRecentRuntimes", report, DefaultRegressionWindow).Return(tt.runtimes, tt.err).Once()
+
+			s := newService(r, WithRuntimeRegression(DefaultRegressionWindow, DefaultRegressionFactor))
+			s.checkRuntimeRegression(slog.Default(), report)
+			require.Equal(t, tt.want, report.RuntimeRegression)
+		})
+	}
+}
+
+func TestService_CheckRuntimeRegression_Disabled(t *testing.T) {
+	report := &models.Report{Host: "web-1", Runtime: 600}
+
+	newService(repo.NewMockRepository(t)).checkRuntimeRegression(slog.Default(), report)
+	require.False(t, report.RuntimeRegression)
+}
@@ -0,0 +1,360 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	repo "github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api/filters"
+)
+
+// reportQueryType is the type of a field of the report search query, it decides the operators and values a term can
+// use.
+type reportQueryType int
+
+const (
+	reportQueryString reportQueryType = iota
+	reportQueryNumber
+	reportQueryTime
+)
+
+type reportQueryField struct {
+	column string
+	typ    reportQueryType
+}
+
+// reportQueryFields are the fields the report search query can filter by, keyed by their names in the query.
+var reportQueryFields = map[string]reportQueryField{
+	"host":        {column: "t.host", typ: reportQueryString},
+	"env":         {column: "t.environment", typ: reportQueryString},
+	"environment": {column: "t.environment", typ: reportQueryString},
+	"state":       {column: "t.state", typ: reportQueryString},
+	"status":      {column: "t.state", typ: reportQueryString},
+	"hash":        {column: "t.hash", typ: reportQueryString},
+	"version":     {column: "t.puppet_version", typ: reportQueryNumber},
+	"runtime":     {column: "t.runtime", typ: reportQueryNumber},
+	"executed":    {column: "t.executed_at", typ: reportQueryTime},
+	"failed":      {column: "t.failed", typ: reportQueryNumber},
+	"changed":     {column: "t.changed", typ: reportQueryNumber},
+	"skipped":     {column: "t.skipped", typ: reportQueryNumber},
+	"total":       {column: "t.total", typ: reportQueryNumber},
+}
+
+// reportQueryError is an error in a report search query, at the position of the query it was found.
+type reportQueryError struct {
+	// pos is the position in the query, counting the characters from 1.
+	pos int
+	msg string
+}
+
+func (e *reportQueryError) Error() string {
+	return fmt.Sprintf("position %d: %s", e.pos, e.msg)
+}
+
+type reportQueryTokenKind int
+
+const (
+	tokenEOF reportQueryTokenKind = iota
+	tokenWord
+	tokenString
+	tokenOperator
+	tokenLeftParen
+	tokenRightParen
+)
+
+type reportQueryToken struct {
+	kind reportQueryTokenKind
+	text string
+
+	// pos is the position of the token in the query, counting the characters from 1.
+	pos int
+}
+
+// lexReportQuery splits the query into tokens. The value after an operator is a single token up to the next space or
+// closing parenthesis, so values such as times can have the characters of the operators in them. An operator followed
+// by a closing parenthesis or the end of the query has no value token, so the parser reports the missing value.
+func lexReportQuery(query string) ([]reportQueryToken, error) {
+	runes := []rune(query)
+	tokens := make([]reportQueryToken, 0)
+	afterOperator := false
+
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+			continue
+		case c == '"':
+			text, n, err := lexReportQueryString(runes[i:], i+1)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, reportQueryToken{kind: tokenString, text: text, pos: i + 1})
+			i += n
+		case afterOperator:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != ')' {
+				i++
+			}
+			if i > start {
+				tokens = append(tokens, reportQueryToken{kind: tokenWord, text: string(runes[start:i]), pos: start + 1})
+			}
+		case c == '(':
+			tokens = append(tokens, reportQueryToken{kind: tokenLeftParen, text: "(", pos: i + 1})
+			i++
+		case c == ')':
+			tokens = append(tokens, reportQueryToken{kind: tokenRightParen, text: ")", pos: i + 1})
+			i++
+		case c == ':' || c == '<' || c == '>' || c == '=':
+			start := i
+			i++
+			if i < len(runes) && ((c == ':' && runes[i] == '~') || (c != ':' && c != '=' && runes[i] == '=')) {
+				i++
+			}
+			tokens = append(tokens, reportQueryToken{kind: tokenOperator, text: string(runes[start:i]), pos: start + 1})
+			afterOperator = true
+			continue
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune(`():<>="`, runes[i]) {
+				i++
+			}
+			tokens = append(tokens, reportQueryToken{kind: tokenWord, text: string(runes[start:i]), pos: start + 1})
+		}
+		afterOperator = false
+	}
+
+	return append(tokens, reportQueryToken{kind: tokenEOF, pos: len(runes) + 1}), nil
+}
+
+// lexReportQueryString reads the double-quoted string at the start of runes, where a backslash escapes the next
+// character. It returns the string and the number of runes read.
+func lexReportQueryString(runes []rune, pos int) (string, int, error) {
+	sb := new(strings.Builder)
+	for i := 1; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			i++
+			if i == len(runes) {
+				return "", 0, &reportQueryError{pos: pos, msg: "unterminated string"}
+			}
+			sb.WriteRune(runes[i])
+		case '"':
+			return sb.String(), i + 1, nil
+		default:
+			sb.WriteRune(runes[i])
+		}
+	}
+	return "", 0, &reportQueryError{pos: pos, msg: "unterminated string"}
+}
+
+// reportQueryParser parses the report search query by recursive descent:
+//
+//	or    = and { "OR" and }
+//	and   = unary { [ "AND" ] unary }
+//	unary = "NOT" unary | "(" or ")" | field operator value
+type reportQueryParser struct {
+	tokens []reportQueryToken
+	pos    int
+}
+
+// parseReportQuery parses a report search query, such as state:failed AND NOT host:~"^build-" AND runtime>300, into
+// the condition on the columns of the reports.
+func parseReportQuery(query string) (repo.Condition, error) {
+	tokens, err := lexReportQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &reportQueryParser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, &reportQueryError{pos: 1, msg: "empty query"}
+	}
+
+	cond, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, &reportQueryError{pos: tok.pos, msg: fmt.Sprintf("unexpected %q", tok.text)}
+	}
+
+	return cond, nil
+}
+
+func (p *reportQueryParser) peek() reportQueryToken {
+	return p.tokens[p.pos]
+}
+
+func (p *reportQueryParser) next() reportQueryToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// isKeyword returns true if the token is the keyword, the keywords are not case-sensitive.
+func (t reportQueryToken) isKeyword(keyword string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.text, keyword)
+}
+
+func (p *reportQueryParser) parseOr() (repo.Condition, error) {
+	cond, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	conds := repo.OrCondition{cond}
+	for p.peek().isKeyword("OR") {
+		p.next()
+		cond, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, cond)
+	}
+
+	if len(conds) == 1 {
+		return conds[0], nil
+	}
+	return conds, nil
+}
+
+func (p *reportQueryParser) parseAnd() (repo.Condition, error) {
+	cond, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	conds := repo.AndCondition{cond}
+	for {
+		tok := p.peek()
+		switch {
+		case tok.isKeyword("AND"):
+			p.next()
+		case tok.kind == tokenEOF, tok.kind == tokenRightParen, tok.isKeyword("OR"):
+			if len(conds) == 1 {
+				return conds[0], nil
+			}
+			return conds, nil
+		}
+
+		// Terms next to each other are combined with AND.
+		cond, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, cond)
+	}
+}
+
+func (p *reportQueryParser) parseUnary() (repo.Condition, error) {
+	tok := p.next()
+	switch {
+	case tok.isKeyword("NOT"):
+		cond, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return repo.NotCondition{Condition: cond}, nil
+	case tok.kind == tokenLeftParen:
+		cond, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRightParen {
+			return nil, &reportQueryError{pos: closing.pos, msg: fmt.Sprintf("missing ) for the ( at position %d", tok.pos)}
+		}
+		return cond, nil
+	case tok.kind == tokenWord && !tok.isKeyword("AND") && !tok.isKeyword("OR"):
+		return p.parseTerm(tok)
+	case tok.kind == tokenEOF:
+		return nil, &reportQueryError{pos: tok.pos, msg: "unexpected end of query"}
+	default:
+		return nil, &reportQueryError{pos: tok.pos, msg: fmt.Sprintf("unexpected %q", tok.text)}
+	}
+}
+
+// parseTerm parses the operator and value of the term of the field, the value is converted to the type of the field.
+func (p *reportQueryParser) parseTerm(fieldTok reportQueryToken) (repo.Condition, error) {
+	field, ok := reportQueryFields[strings.ToLower(fieldTok.text)]
+	if !ok {
+		return nil, &reportQueryError{pos: fieldTok.pos, msg: fmt.Sprintf("unknown field %q", fieldTok.text)}
+	}
+
+	opTok := p.next()
+	if opTok.kind != tokenOperator {
+		return nil, &reportQueryError{pos: opTok.pos, msg: fmt.Sprintf("expected an operator after %q", fieldTok.text)}
+	}
+
+	valueTok := p.next()
+	if valueTok.kind != tokenWord && valueTok.kind != tokenString {
+		return nil, &reportQueryError{pos: valueTok.pos, msg: fmt.Sprintf("expected a value after %q", opTok.text)}
+	}
+
+	valueErr := func(msg string) error {
+		return &reportQueryError{pos: valueTok.pos, msg: msg}
+	}
+
+	// An empty value would match every report with a wildcard or a regular expression, which is never what was meant.
+	if valueTok.text == "" {
+		return nil, valueErr(fmt.Sprintf("empty value after %q", opTok.text))
+	}
+
+	if field.typ == reportQueryString {
+		switch opTok.text {
+		case ":", "=":
+			return repo.WildcardCondition{Column: field.column, Value: valueTok.text}, nil
+		case ":~":
+			// The pattern is run by the database, MySQL with ICU, PostgreSQL with its POSIX regular expressions and
+			// SQLite with Go's. Go's syntax is close to the subset they share, so checking it here catches most
+			// mistakes with a position, but a pattern using the extensions of one of them can still fail in the query.
+			if _, err := regexp.Compile(valueTok.text); err != nil {
+				return nil, valueErr(fmt.Sprintf("invalid regular expression: %s", err))
+			}
+			return repo.MatchCondition{Column: field.column, Pattern: valueTok.text}, nil
+		default:
+			return nil, &reportQueryError{pos: opTok.pos, msg: fmt.Sprintf("%s cannot be used with %q", opTok.text, fieldTok.text)}
+		}
+	}
+
+	op := filters.CompareOp(opTok.text)
+	switch opTok.text {
+	case ":":
+		op = filters.CompareEqual
+	case ":~":
+		return nil, &reportQueryError{pos: opTok.pos, msg: fmt.Sprintf(":~ cannot be used with %q", fieldTok.text)}
+	}
+
+	var value any
+	switch field.typ {
+	case reportQueryNumber:
+		n, err := strconv.ParseFloat(valueTok.text, 64)
+		if err != nil {
+			return nil, valueErr(fmt.Sprintf("%q is not a number", valueTok.text))
+		}
+		value = n
+	case reportQueryTime:
+		t, err := parseReportQueryTime(valueTok.text)
+		if err != nil {
+			return nil, valueErr(fmt.Sprintf("%q is not a RFC 3339 time or a date", valueTok.text))
+		}
+		value = t
+	}
+
+	return repo.CompareCondition{Column: field.column, Op: op, Value: value}, nil
+}
+
+// parseReportQueryTime parses a RFC 3339 time, or a date as the start of the day in UTC.
+func parseReportQueryTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UTC(), nil
+	}
+	return time.Parse(time.DateOnly, s)
+}
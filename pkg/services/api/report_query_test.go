@@ -0,0 +1,151 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	repo "github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReportQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    repo.Condition
+		wantErr string
+	}{
+		{
+			name:  "term",
+			query: "host:web-*",
+			want:  repo.WildcardCondition{Column: "t.host", Value: "web-*"},
+		},
+		{
+			name:  "example",
+			query: `state:failed AND env:PRODUCTION AND NOT host:~"^build-" AND runtime>300`,
+			want: repo.AndCondition{
+				repo.WildcardCondition{Column: "t.state", Value: "failed"},
+				repo.WildcardCondition{Column: "t.environment", Value: "PRODUCTION"},
+				repo.NotCondition{Condition: repo.MatchCondition{Column: "t.host", Pattern: "^build-"}},
+				repo.CompareCondition{Column: "t.runtime", Op: ">", Value: float64(300)},
+			},
+		},
+		{
+			name:  "precedence",
+			query: "state:failed OR state:changed failed >= 2",
+			want: repo.OrCondition{
+				repo.WildcardCondition{Column: "t.state", Value: "failed"},
+				repo.AndCondition{
+					repo.WildcardCondition{Column: "t.state", Value: "changed"},
+					repo.CompareCondition{Column: "t.failed", Op: ">=", Value: float64(2)},
+				},
+			},
+		},
+		{
+			name:  "parentheses",
+			query: "(state:failed or state:changed) and not (host:db-1)",
+			want: repo.AndCondition{
+				repo.OrCondition{
+					repo.WildcardCondition{Column: "t.state", Value: "failed"},
+					repo.WildcardCondition{Column: "t.state", Value: "changed"},
+				},
+				repo.NotCondition{Condition: repo.WildcardCondition{Column: "t.host", Value: "db-1"}},
+			},
+		},
+		{
+			name:  "time",
+			query: "executed>2024-12-24T13:00:00+01:00 executed<2024-12-25",
+			want: repo.AndCondition{
+				repo.CompareCondition{Column: "t.executed_at", Op: ">", Value: time.Date(2024, 12, 24, 12, 0, 0, 0, time.UTC)},
+				repo.CompareCondition{Column: "t.executed_at", Op: "<", Value: time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name:  "quoted value",
+			query: `host:"web \"1\""`,
+			want:  repo.WildcardCondition{Column: "t.host", Value: `web "1"`},
+		},
+		{
+			name:    "unknown field",
+			query:   "state:failed AND owner:me",
+			wantErr: `position 18: unknown field "owner"`,
+		},
+		{
+			name:    "missing operator",
+			query:   "host web-1",
+			wantErr: `position 6: expected an operator after "host"`,
+		},
+		{
+			name:    "missing value",
+			query:   "runtime>",
+			wantErr: `position 9: expected a value after ">"`,
+		},
+		{
+			name:    "not a number",
+			query:   "runtime>slow",
+			wantErr: `position 9: "slow" is not a number`,
+		},
+		{
+			name:    "comparison of a string",
+			query:   "host>web",
+			wantErr: `position 5: > cannot be used with "host"`,
+		},
+		{
+			name:    "invalid regular expression",
+			query:   `host:~"("`,
+			wantErr: "position 7: invalid regular expression",
+		},
+		{
+			name:    "missing parenthesis",
+			query:   "(state:failed",
+			wantErr: "position 14: missing ) for the ( at position 1",
+		},
+		{
+			name:    "unexpected parenthesis",
+			query:   "state:failed)",
+			wantErr: `position 13: unexpected ")"`,
+		},
+		{
+			name:    "dangling operator",
+			query:   "state:failed AND",
+			wantErr: "position 17: unexpected end of query",
+		},
+		{
+			name:    "unterminated string",
+			query:   `host:"web`,
+			wantErr: "position 6: unterminated string",
+		},
+		{
+			name:    "missing value before parenthesis",
+			query:   "(host:)",
+			wantErr: `position 7: expected a value after ":"`,
+		},
+		{
+			name:    "missing value at end",
+			query:   "state:failed AND host:~",
+			wantErr: `position 24: expected a value after ":~"`,
+		},
+		{
+			name:    "empty value",
+			query:   `host:""`,
+			wantErr: `position 6: empty value after ":"`,
+		},
+		{
+			name:    "empty",
+			query:   "  ",
+			wantErr: "position 1: empty query",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseReportQuery(tt.query)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
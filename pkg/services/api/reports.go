@@ -9,6 +9,7 @@ import (
 
 	"github.com/jacobbrewer1/pagefilter"
 	"github.com/jacobbrewer1/puppet-reporter/pkg/apis/specs/api"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/auth"
 	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
 	repo "github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api"
 	"github.com/jacobbrewer1/uhttp"
@@ -21,7 +22,7 @@ func (s *service) GetReports(l *slog.Logger, r *http.Request, params api.GetRepo
 		return nil, uhttp.NewHTTPError(http.StatusBadRequest, err, "failed to get pagination details")
 	}
 
-	filts, err := s.getReportsFilters(&params)
+	filts, err := s.getReportsFilters(auth.AccessFromContext(r.Context()), &params)
 	if err != nil {
 		return nil, uhttp.NewHTTPError(http.StatusBadRequest, err, "failed to parse filters")
 	}
@@ -52,8 +53,13 @@ func (s *service) GetReports(l *slog.Logger, r *http.Request, params api.GetRepo
 	return resp, nil
 }
 
-func (s *service) getReportsFilters(params *api.GetReportsParams) (*repo.GetReportsFilters, error) {
+// getReportsFilters returns the filters of the reports, restricted to the environments the reader can read.
+func (s *service) getReportsFilters(access *auth.Access, params *api.GetReportsParams) (*repo.GetReportsFilters, error) {
 	filters := new(repo.GetReportsFilters)
+	if envs, restricted := access.Environments(); restricted {
+		filters.Environments = envs
+	}
+
 	if params == nil {
 		return filters, nil
 	}
@@ -78,28 +84,52 @@ func (s *service) getReportsFilters(params *api.GetReportsParams) (*repo.GetRepo
 		filters.To = params.To
 	}
 
+	if params.RuntimeRegression != nil {
+		filters.RuntimeRegression = params.RuntimeRegression
+	}
+
+	if params.Fact != nil {
+		filters.Facts = make(map[string]string, len(*params.Fact))
+		for _, fact := range *params.Fact {
+			name, value, ok := strings.Cut(fact, "=")
+			if !ok || name == "" {
+				return nil, fmt.Errorf("fact filter %q is not name=value", fact)
+			}
+			filters.Facts[name] = value
+		}
+	}
+
+	if params.Q != nil && strings.TrimSpace(*params.Q) != "" {
+		query, err := parseReportQuery(*params.Q)
+		if err != nil {
+			return nil, fmt.Errorf("invalid query: %w", err)
+		}
+		filters.Query = query
+	}
+
 	return filters, nil
 }
 
 func (s *service) modelAsApiReport(report *models.Report) *api.Report {
 	return &api.Report{
-		Environment:    report.Environment,
-		ExecutedAt:     report.ExecutedAt,
-		Hash:           report.Hash,
-		Host:           report.Host,
-		Id:             int64(report.Id),
-		PuppetVersion:  float32(report.PuppetVersion),
-		RuntimeSeconds: int64(report.Runtime),
-		Status:         api.ReportStatus(strings.ToLower(string(report.State))),
-		TotalChanged:   int64(report.Changed),
-		TotalFailed:    int64(report.Failed),
-		TotalResources: int64(report.Total),
-		TotalSkipped:   int64(report.Skipped),
+		Environment:       report.Environment,
+		ExecutedAt:        report.ExecutedAt,
+		Hash:              report.Hash,
+		Host:              report.Host,
+		Id:                int64(report.Id),
+		PuppetVersion:     float32(report.PuppetVersion),
+		RuntimeRegression: report.RuntimeRegression,
+		RuntimeSeconds:    int64(report.Runtime),
+		Status:            api.ReportStatus(strings.ToLower(string(report.State))),
+		TotalChanged:      int64(report.Changed),
+		TotalFailed:       int64(report.Failed),
+		TotalResources:    int64(report.Total),
+		TotalSkipped:      int64(report.Skipped),
 	}
 }
 
-func (s *service) GetReport(l *slog.Logger, r *http.Request, hash string) (*api.ReportDetails, error) {
-	report, err := s.reportDetailsByHash(hash)
+func (s *service) GetReport(l *slog.Logger, r *http.Request, hash string, params api.GetReportParams) (*api.ReportDetails, error) {
+	report, err := s.reportDetailsByHash(auth.AccessFromContext(r.Context()), hash)
 	if err != nil {
 		switch {
 		case errors.Is(err, repo.ErrReportNotFound):
@@ -128,7 +158,9 @@ func (s *service) modelAsApiResource(resource *models.Resource) *api.Resource {
 	}
 }
 
-func (s *service) reportDetailsByHash(hash string) (*api.ReportDetails, error) {
+// reportDetailsByHash returns the report with its resources and logs. The reports of the environments the reader
+// cannot read are not found, so the reader cannot tell they exist.
+func (s *service) reportDetailsByHash(access *auth.Access, hash string) (*api.ReportDetails, error) {
 	report, err := s.r.GetReportByHash(hash)
 	if err != nil {
 		switch {
@@ -139,6 +171,10 @@ func (s *service) reportDetailsByHash(hash string) (*api.ReportDetails, error) {
 		}
 	}
 
+	if !access.AllowsEnvironment(report.Environment) {
+		return nil, fmt.Errorf("report not found: %w", repo.ErrReportNotFound)
+	}
+
 	reportResp := s.modelAsApiReport(report)
 
 	resources, err := s.r.GetResourcesByReportID(report.Id)
@@ -2,16 +2,46 @@ package api
 
 import (
 	"github.com/jacobbrewer1/puppet-reporter/pkg/apis/specs/api"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/events"
 	repo "github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api"
 )
 
 type service struct {
 	// r is the repository used by the service.
 	r repo.Repository
+
+	// hostFilter decides which hosts have their own metrics.
+	hostFilter *HostFilter
+
+	// async is true if reports can be queued to be ingested by a WorkerPool.
+	async bool
+
+	// clientCerts is true if the uploads need a client certificate of the host the report is for.
+	clientCerts bool
+
+	// broker is passed the reports as they are ingested, the reports cannot be streamed without it.
+	broker *events.Broker
+
+	// regressionWindow is how many previous runs of a host the runtime of a report is compared to, the runtimes are
+	// not checked if it is zero.
+	regressionWindow int
+
+	// regressionFactor is how many deviations above the baseline runtime a run must take to be a regression.
+	regressionFactor float64
 }
 
-func NewService(r repo.Repository) api.ServerInterface {
-	return &service{
+func NewService(r repo.Repository, opts ...ServiceOption) api.ServerInterface {
+	return newService(r, opts...)
+}
+
+func newService(r repo.Repository, opts ...ServiceOption) *service {
+	s := &service{
 		r: r,
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
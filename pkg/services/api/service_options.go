@@ -0,0 +1,43 @@
+package api
+
+import "github.com/jacobbrewer1/puppet-reporter/pkg/events"
+
+type ServiceOption func(s *service)
+
+// WithHostFilter sets the filter deciding which hosts are exported with per host metrics.
+func WithHostFilter(f *HostFilter) ServiceOption {
+	return func(s *service) {
+		s.hostFilter = f
+	}
+}
+
+// WithAsyncIngestion allows reports to be queued, they are then ingested by a WorkerPool.
+func WithAsyncIngestion() ServiceOption {
+	return func(s *service) {
+		s.async = true
+	}
+}
+
+// WithClientCertificates requires the uploads to be made with a client certificate of the host of the report, so
+// one node cannot upload reports for another.
+func WithClientCertificates() ServiceOption {
+	return func(s *service) {
+		s.clientCerts = true
+	}
+}
+
+// WithBroker publishes the reports to the broker as they are ingested, so they can be streamed to the clients.
+func WithBroker(b *events.Broker) ServiceOption {
+	return func(s *service) {
+		s.broker = b
+	}
+}
+
+// WithRuntimeRegression flags the reports whose runtime is more than factor deviations above the median runtime of the
+// previous window runs of the host.
+func WithRuntimeRegression(window int, factor float64) ServiceOption {
+	return func(s *service) {
+		s.regressionWindow = window
+		s.regressionFactor = factor
+	}
+}
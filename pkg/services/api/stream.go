@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/jacobbrewer1/puppet-reporter/pkg/apis/specs/api"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/auth"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/events"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+	"github.com/jacobbrewer1/uhttp"
+)
+
+var errNoBroker = errors.New("reports are not streamed by this server")
+
+func (s *service) StreamGetReportStream(l *slog.Logger, r *http.Request, params api.GetReportStreamParams) (api.Stream, error) {
+	if s.broker == nil {
+		return nil, uhttp.NewHTTPError(http.StatusNotFound, errNoBroker, "report stream not available")
+	}
+
+	filter := &events.Filter{
+		Access: auth.AccessFromContext(r.Context()),
+	}
+	if params.Host != nil {
+		filter.Host = *params.Host
+	}
+	if params.Environment != nil {
+		filter.Environment = *params.Environment
+	}
+	if params.State != nil {
+		filter.State = string(*params.State)
+	}
+
+	return &reportStream{
+		ctx:    r.Context(),
+		broker: s.broker,
+		filter: filter,
+		data: func(report *models.Report) ([]byte, error) {
+			return json.Marshal(s.modelAsApiReport(report))
+		},
+	}, nil
+}
+
+// GetReportStream is never called, as StreamGetReportStream always returns a stream or an error.
+func (s *service) GetReportStream(l *slog.Logger, r *http.Request, params api.GetReportStreamParams) ([]byte, error) {
+	return nil, uhttp.NewHTTPError(http.StatusNotFound, errNoBroker, "report stream not available")
+}
+
+// reportStream streams the reports as server-sent events until the client goes away.
+type reportStream struct {
+	ctx    context.Context
+	broker *events.Broker
+	filter *events.Filter
+	data   func(*models.Report) ([]byte, error)
+}
+
+func (s *reportStream) ContentType() string {
+	return events.ContentTypeEventStream
+}
+
+func (s *reportStream) Encode(w io.Writer) error {
+	return s.broker.Stream(s.ctx, w, s.filter, s.data)
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jacobbrewer1/puppet-reporter/pkg/apis/specs/api"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/auth"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/events"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+	repo "github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api"
+	"github.com/jacobbrewer1/uhttp"
+	"github.com/jacobbrewer1/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_StreamGetReportStream(t *testing.T) {
+	acl, err := auth.NewACL(
+		map[string][]string{"prod": {"production"}},
+		map[string][]string{"token:ci": {"prod"}},
+		false,
+	)
+	require.NoError(t, err)
+
+	broker := events.NewBroker(events.DefaultBufferSize)
+	s := newService(repo.NewMockRepository(t), WithBroker(broker))
+
+	ctx, cancel := context.WithCancel(auth.WithAccess(context.Background(), acl.TokenAccess("ci")))
+	defer cancel()
+	req := httptest.NewRequest(http.MethodGet, "/reports/stream", nil).WithContext(ctx)
+
+	stream, err := s.StreamGetReportStream(slog.Default(), req, api.GetReportStreamParams{Host: utils.Ptr("web-")})
+	require.NoError(t, err)
+	require.Equal(t, events.ContentTypeEventStream, stream.ContentType())
+
+	pr, pw := io.Pipe()
+	done := make(chan error)
+	go func() {
+		done <- stream.Encode(pw)
+		pw.Close()
+	}()
+
+	br := bufio.NewReader(pr)
+	readEvent := func() string {
+		var lines []string
+		for {
+			line, err := br.ReadString('\n')
+			require.NoError(t, err)
+			if line == "\n" {
+				return strings.Join(lines, "")
+			}
+			lines = append(lines, line)
+		}
+	}
+	require.Equal(t, ": connected\n", readEvent())
+
+	// The report of another host and the report the token cannot read are not sent.
+	broker.Publish(&models.Report{Id: 1, Host: "db-1", Environment: "PRODUCTION", State: models.ReportStateFailed})
+	broker.Publish(&models.Report{Id: 2, Host: "web-1", Environment: "STAGING", State: models.ReportStateFailed})
+	broker.Publish(&models.Report{Id: 3, Host: "web-1", Environment: "PRODUCTION", State: models.ReportStateFailed})
+
+	data, err := json.Marshal(s.modelAsApiReport(&models.Report{Id: 3, Host: "web-1", Environment: "PRODUCTION", State: models.ReportStateFailed}))
+	require.NoError(t, err)
+	require.Equal(t, "event: report\nid: 3\ndata: "+string(data)+"\n", readEvent())
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestService_StreamGetReportStream_NoBroker(t *testing.T) {
+	_, err := newService(repo.NewMockRepository(t)).StreamGetReportStream(slog.Default(), httptest.NewRequest(http.MethodGet, "/reports/stream", nil), api.GetReportStreamParams{})
+	httpErr := new(uhttp.HTTPError)
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusNotFound, httpErr.StatusCode())
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -9,22 +10,161 @@ import (
 	"sync"
 
 	"github.com/jacobbrewer1/puppet-reporter/pkg/apis/specs/api"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/auth"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/logging"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
 	repo "github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api"
 	"github.com/jacobbrewer1/uhttp"
 	"github.com/jacobbrewer1/utils"
 )
 
-func (s *service) UploadReport(l *slog.Logger, r *http.Request, body0 *api.UploadReportRequestBody) (*api.ReportDetails, error) {
+func (s *service) UploadReport(l *slog.Logger, r *http.Request, params api.UploadReportParams, body0 *api.UploadReportRequestBody) (*api.ReportUpload, error) {
+	cert, err := s.uploadCertificate(r)
+	if err != nil {
+		return nil, err
+	}
+
 	bts, err := body0.File.Bytes()
 	if err != nil {
+		ingestionErrors.WithLabelValues(ingestionStageRead).Inc()
 		return nil, uhttp.NewHTTPError(http.StatusBadRequest, err, "error reading file")
 	}
 
+	rep, err := s.ingestReport(l, bts, cert)
+	if err != nil {
+		return nil, err
+	}
+
+	if uploadSummary(r, &params) {
+		return &api.ReportUpload{
+			Summary: s.reportSummary(l, rep.Report),
+		}, nil
+	}
+
+	respReport := s.modelAsApiReport(rep.Report)
+	respLogs := make([]api.LogMessage, len(rep.Logs))
+	respResources := make([]api.Resource, len(rep.Resources))
+
+	wg := new(sync.WaitGroup)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i, log := range rep.Logs {
+			respLogs[i] = *s.modelAsApiLogMessage(log)
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		for i, resource := range rep.Resources {
+			respResources[i] = *s.modelAsApiResource(resource)
+		}
+	}()
+
+	wg.Wait()
+
+	respReportUpload := &api.ReportUpload{
+		Logs:      &respLogs,
+		Report:    respReport,
+		Resources: &respResources,
+	}
+
+	return respReportUpload, nil
+}
+
+// uploadSummary returns true if the client asked for only the summary of the uploaded report, with the summary
+// parameter or the Prefer: return=minimal header. The parameter wins if it is given.
+func uploadSummary(r *http.Request, params *api.UploadReportParams) bool {
+	if params.Summary != nil {
+		return *params.Summary
+	}
+
+	for _, header := range r.Header.Values("Prefer") {
+		for _, pref := range strings.Split(header, ",") {
+			// The preference can have parameters after a semicolon, they do not change what it asks for.
+			pref, _, _ = strings.Cut(pref, ";")
+			if strings.EqualFold(strings.ReplaceAll(pref, " ", ""), "return=minimal") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// reportSummary returns the summary of the report, with the status of the previous report of the host. The report has
+// been saved by then, so the upload does not fail if the previous report cannot be read, the summary does not have it.
+func (s *service) reportSummary(l *slog.Logger, report *models.Report) *api.ReportSummary {
+	apiReport := s.modelAsApiReport(report)
+	summary := &api.ReportSummary{
+		Hash:              apiReport.Hash,
+		Host:              apiReport.Host,
+		Status:            apiReport.Status,
+		RuntimeRegression: apiReport.RuntimeRegression,
+		TotalChanged:      apiReport.TotalChanged,
+		TotalFailed:       apiReport.TotalFailed,
+		TotalResources:    apiReport.TotalResources,
+		TotalSkipped:      apiReport.TotalSkipped,
+	}
+
+	prev, err := s.r.GetPreviousReport(report)
+	switch {
+	case errors.Is(err, repo.ErrReportNotFound):
+		// The first report of the host has no status to change from.
+	case err != nil:
+		l.Error("Error getting previous report", slog.String(logging.KeyError, err.Error()))
+	default:
+		prevStatus := s.modelAsApiReport(prev).Status
+		summary.PreviousStatus = &prevStatus
+		summary.StatusChanged = prevStatus != summary.Status
+	}
+
+	return summary
+}
+
+// uploadCertificate returns the client certificate of an upload. It returns an error if client certificates are
+// required and the client did not present one, and nil if they are not required.
+func (s *service) uploadCertificate(r *http.Request) (*x509.Certificate, error) {
+	if !s.clientCerts {
+		return nil, nil
+	}
+
+	cert := auth.ClientCertificate(r)
+	if cert == nil {
+		return nil, uhttp.NewHTTPError(http.StatusUnauthorized, errors.New("client certificate required"))
+	}
+
+	return cert, nil
+}
+
+// checkReportHost returns an error if the client certificate is not for the host of the report. Any host is allowed
+// if there is no certificate.
+func checkReportHost(l *slog.Logger, cert *x509.Certificate, host string) error {
+	if cert == nil || auth.CertificateMatchesHost(cert, host) {
+		return nil
+	}
+
+	l.Warn("Rejected report for another host",
+		slog.String("certificate", cert.Subject.CommonName),
+		slog.String("host", host),
+	)
+
+	return uhttp.NewHTTPError(http.StatusForbidden, fmt.Errorf("client certificate %s cannot upload reports for %s", cert.Subject.CommonName, host))
+}
+
+// ingestReport parses the report and saves it to the database. The report must be for the host of the client
+// certificate if there is one. The returned error describes why the report could not be ingested as an HTTP error.
+func (s *service) ingestReport(l *slog.Logger, bts []byte, cert *x509.Certificate) (*CompleteReport, error) {
 	rep, err := parsePuppetReport(bts)
 	if err != nil {
+		s.recordIngestionFailure(l, bts, err)
 		return nil, uhttp.NewHTTPError(http.StatusBadRequest, err, "error parsing report")
 	}
 
+	if err := checkReportHost(l, cert, rep.Report.Host); err != nil {
+		return nil, err
+	}
+
 	existingRep, err := s.r.GetReportByHash(rep.Report.Hash)
 	if err != nil && !errors.Is(err, repo.ErrReportNotFound) {
 		return nil, uhttp.NewHTTPError(http.StatusInternalServerError, err, "error getting report")
@@ -32,6 +172,8 @@ func (s *service) UploadReport(l *slog.Logger, r *http.Request, body0 *api.Uploa
 		return nil, uhttp.NewHTTPError(http.StatusConflict, fmt.Errorf("report with hash %s already exists", rep.Report.Hash), "report already exists")
 	}
 
+	s.checkRuntimeRegression(l, rep.Report)
+
 	if err := s.r.SaveReport(rep.Report); err != nil {
 		return nil, uhttp.NewHTTPError(http.StatusInternalServerError, err, "error saving report")
 	}
@@ -66,38 +208,20 @@ func (s *service) UploadReport(l *slog.Logger, r *http.Request, body0 *api.Uploa
 		return nil, uhttp.NewHTTPError(http.StatusInternalServerError, errors.New("error saving resources/logs"), []any{multiErr.ErrorStrings()}...)
 	}
 
-	go updateMetrics(rep)
+	go s.updateMetrics(rep)
+	s.broker.Publish(rep.Report)
 
-	respReport := s.modelAsApiReport(rep.Report)
-	respLogs := make([]api.LogMessage, len(rep.Logs))
-	respResources := make([]api.Resource, len(rep.Resources))
-
-	wg.Add(2)
-	go func() {
-		defer wg.Done()
-		for i, log := range rep.Logs {
-			respLogs[i] = *s.modelAsApiLogMessage(log)
-		}
-	}()
-
-	go func() {
-		defer wg.Done()
-		for i, resource := range rep.Resources {
-			respResources[i] = *s.modelAsApiResource(resource)
-		}
-	}()
-
-	wg.Wait()
-
-	respReportDetails := &api.ReportDetails{
-		Logs:      respLogs,
-		Report:    *respReport,
-		Resources: respResources,
-	}
-
-	return respReportDetails, nil
+	return rep, nil
 }
 
-func updateMetrics(rep *CompleteReport) {
+func (s *service) updateMetrics(rep *CompleteReport) {
 	totalReports.WithLabelValues(strings.ToLower(string(rep.Report.State)), strings.ToLower(rep.Report.Environment)).Inc()
+	runtimeSeconds.WithLabelValues(strings.ToLower(rep.Report.Environment)).Observe(float64(rep.Report.Runtime))
+	if rep.Report.RuntimeRegression {
+		runtimeRegressions.WithLabelValues(strings.ToLower(rep.Report.Environment)).Inc()
+	}
+
+	if s.hostFilter.Allowed(rep.Report.Host) {
+		observeHostReport(rep.Report)
+	}
 }
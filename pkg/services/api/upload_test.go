@@ -0,0 +1,175 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jacobbrewer1/puppet-reporter/pkg/apis/specs/api"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+	repo "github.com/jacobbrewer1/puppet-reporter/pkg/repositories/api"
+	"github.com/jacobbrewer1/uhttp"
+	"github.com/jacobbrewer1/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_UploadCertificate(t *testing.T) {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "web-1"}}
+
+	tests := []struct {
+		name        string
+		clientCerts bool
+		cert        *x509.Certificate
+		wantCert    *x509.Certificate
+		wantStatus  int
+	}{
+		{
+			name: "not required",
+		},
+		{
+			name:        "required and presented",
+			clientCerts: true,
+			cert:        cert,
+			wantCert:    cert,
+		},
+		{
+			name:        "required and missing",
+			clientCerts: true,
+			wantStatus:  http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{clientCerts: tt.clientCerts}
+
+			req := httptest.NewRequest(http.MethodPost, "/reports", nil)
+			if tt.cert != nil {
+				req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{tt.cert}}}
+			}
+
+			got, err := s.uploadCertificate(req)
+			if tt.wantStatus != 0 {
+				httpErr := new(uhttp.HTTPError)
+				require.ErrorAs(t, err, &httpErr)
+				require.Equal(t, tt.wantStatus, httpErr.StatusCode())
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.wantCert, got)
+		})
+	}
+}
+
+func TestCheckReportHost(t *testing.T) {
+	cert := &x509.Certificate{
+		Subject:  pkix.Name{CommonName: "web-1"},
+		DNSNames: []string{"web-1.example.com"},
+	}
+
+	require.NoError(t, checkReportHost(slog.Default(), nil, "db-1"))
+	require.NoError(t, checkReportHost(slog.Default(), cert, "web-1"))
+	require.NoError(t, checkReportHost(slog.Default(), cert, "web-1.example.com"))
+
+	err := checkReportHost(slog.Default(), cert, "db-1")
+	httpErr := new(uhttp.HTTPError)
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusForbidden, httpErr.StatusCode())
+}
+
+func TestParseReportHost(t *testing.T) {
+	host, err := parseReportHost([]byte("host: web-1\nstatus: changed\n"))
+	require.NoError(t, err)
+	require.Equal(t, "web-1", host)
+
+	_, err = parseReportHost([]byte("status: changed\n"))
+	require.Error(t, err)
+}
+
+func TestUploadSummary(t *testing.T) {
+	yes, no := true, false
+
+	tests := []struct {
+		name    string
+		summary *bool
+		prefer  []string
+		want    bool
+	}{
+		{name: "default"},
+		{name: "parameter", summary: &yes, want: true},
+		{name: "prefer minimal", prefer: []string{"return=minimal"}, want: true},
+		{name: "prefer with other preferences", prefer: []string{"respond-async, return = minimal; foo=bar"}, want: true},
+		{name: "prefer in a later header", prefer: []string{"respond-async", "return=minimal"}, want: true},
+		{name: "prefer representation", prefer: []string{"return=representation"}},
+		{name: "parameter wins", summary: &no, prefer: []string{"return=minimal"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/reports", nil)
+			for _, p := range tt.prefer {
+				req.Header.Add("Prefer", p)
+			}
+
+			require.Equal(t, tt.want, uploadSummary(req, &api.UploadReportParams{Summary: tt.summary}))
+		})
+	}
+}
+
+func TestService_ReportSummary(t *testing.T) {
+	report := &models.Report{Id: 2, Hash: "abc", Host: "web-1", State: "FAILED", Failed: 1, Total: 10}
+
+	tests := []struct {
+		name        string
+		prev        *models.Report
+		prevErr     error
+		wantPrev    *api.ReportStatus
+		wantChanged bool
+	}{
+		{
+			name:    "first report",
+			prevErr: repo.ErrReportNotFound,
+		},
+		{
+			name:        "changed",
+			prev:        &models.Report{Id: 1, Host: "web-1", State: "UNCHANGED"},
+			wantPrev:    utils.Ptr(api.ReportStatusunchanged),
+			wantChanged: true,
+		},
+		{
+			name:     "unchanged",
+			prev:     &models.Report{Id: 1, Host: "web-1", State: "FAILED"},
+			wantPrev: utils.Ptr(api.ReportStatusfailed),
+		},
+		{
+			name:    "error",
+			prevErr: errors.New("database down"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := repo.NewMockRepository(t)
+			r.On("GetPreviousReport", report).Return(tt.prev, tt.prevErr).Once()
+
+			s := &service{r: r}
+			got := s.reportSummary(slog.Default(), report)
+
+			require.Equal(t, &api.ReportSummary{
+				Hash:           "abc",
+				Host:           "web-1",
+				Status:         api.ReportStatusfailed,
+				PreviousStatus: tt.wantPrev,
+				StatusChanged:  tt.wantChanged,
+				TotalFailed:    1,
+				TotalResources: 10,
+			}, got)
+		})
+	}
+}
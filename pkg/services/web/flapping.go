@@ -0,0 +1,104 @@
+package web
+
+import (
+	"log/slog"
+	"sync"
+
+	"github.com/jacobbrewer1/puppet-reporter/pkg/flapping"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/logging"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+)
+
+// hostFlapping is what flaps on a host, its state or the resources its runs keep changing.
+type hostFlapping struct {
+	// State is set if the state of the host alternates between its runs.
+	State *flapping.Host
+
+	// Resources are the resources most of the runs of the host change.
+	Resources []*flapping.Resource
+}
+
+// getFlapping returns what flaps on the hosts of the reports, by host. The reports are shown without the flapping
+// highlighted if it cannot be found.
+func (s *service) getFlapping(reports []*models.Report) map[string]*hostFlapping {
+	hosts := make([]string, 0, len(reports))
+	for _, rep := range reports {
+		hosts = append(hosts, rep.Host)
+	}
+
+	recent, err := s.r.GetRecentReports(flapping.DefaultWindow, hosts)
+	if err != nil {
+		slog.Error("Error getting recent reports", slog.String(logging.KeyError, err.Error()))
+		return nil
+	}
+
+	changes, err := s.r.GetResourceChanges(flapping.DefaultWindow, hosts)
+	if err != nil {
+		slog.Error("Error getting resource changes", slog.String(logging.KeyError, err.Error()))
+		return nil
+	}
+
+	res := flapping.Detect(recent, changes, flapping.DefaultThreshold)
+
+	flaps := make(map[string]*hostFlapping)
+	get := func(host string) *hostFlapping {
+		if _, ok := flaps[host]; !ok {
+			flaps[host] = new(hostFlapping)
+		}
+		return flaps[host]
+	}
+
+	for _, h := range res.Hosts {
+		get(h.Host).State = h
+	}
+	for _, rsc := range res.Resources {
+		get(rsc.Host).Resources = append(get(rsc.Host).Resources, rsc)
+	}
+
+	return flaps
+}
+
+// flappingCache holds what flaps on the hosts of the recently published reports, so it is found once for each report
+// rather than once for each page the report is streamed to.
+type flappingCache struct {
+	mut sync.Mutex
+
+	// size is how many reports are held, the reports a subscriber can fall behind by.
+	size int
+
+	// ids are the reports held, oldest first.
+	ids []int
+
+	// flaps are what flaps on the host of each report held, by the report.
+	flaps map[int]map[string]*hostFlapping
+}
+
+func newFlappingCache(size int) *flappingCache {
+	return &flappingCache{
+		size:  size,
+		ids:   make([]int, 0, size),
+		flaps: make(map[int]map[string]*hostFlapping, size),
+	}
+}
+
+// get returns what flaps on the host of the report, calling find if it is not held. The lock is held while find is
+// called, so the pages streaming a report wait for the first of them rather than each finding it.
+func (c *flappingCache) get(report *models.Report, find func() map[string]*hostFlapping) map[string]*hostFlapping {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	if flaps, ok := c.flaps[report.Id]; ok {
+		return flaps
+	}
+
+	flaps := find()
+
+	if len(c.ids) == c.size {
+		delete(c.flaps, c.ids[0])
+		c.ids = c.ids[1:]
+	}
+	c.ids = append(c.ids, report.Id)
+	c.flaps[report.Id] = flaps
+
+	return flaps
+}
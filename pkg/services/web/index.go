@@ -5,8 +5,10 @@ import (
 	"html/template"
 	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/jacobbrewer1/pagefilter"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/auth"
 	"github.com/jacobbrewer1/puppet-reporter/pkg/logging"
 	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
 	repo "github.com/jacobbrewer1/puppet-reporter/pkg/repositories/web"
@@ -36,10 +38,12 @@ func (s *service) indexHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	filters := s.getListReportFilters(
+		auth.AccessFromContext(r.Context()),
 		r.URL.Query().Get("host"),
 		r.URL.Query().Get("puppet-version"),
 		r.URL.Query().Get("environment"),
 		r.URL.Query().Get("status"),
+		r.URL.Query().Get("fact"),
 	)
 
 	reps, err := s.r.ListLatestHosts(details, filters)
@@ -57,16 +61,19 @@ func (s *service) indexHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	tmpl := template.Must(template.New("index").Funcs(
-		template.FuncMap{
-			"getReportStyle": getReportStyle,
-		},
-	).ParseFS(localTemplates, "templates/index.gohtml"))
+	tmpl := parseIndex(s.getFlapping(reps.Items))
 
 	tmplTpe := struct {
 		Reports *pagefilter.PaginatedResponse[models.Report]
+		User    string
+		Live    bool
 	}{
 		Reports: reps,
+		Live:    s.broker != nil,
+	}
+
+	if session := auth.SessionFromContext(r.Context()); session != nil {
+		tmplTpe.User = session.User
 	}
 
 	if err := tmpl.Execute(w, tmplTpe); err != nil {
@@ -94,10 +101,12 @@ func (s *service) APIListReports(w http.ResponseWriter, r *http.Request) {
 	}
 
 	filters := s.getListReportFilters(
+		auth.AccessFromContext(r.Context()),
 		r.URL.Query().Get("host"),
 		r.URL.Query().Get("puppet-version"),
 		r.URL.Query().Get("environment"),
 		r.URL.Query().Get("status"),
+		r.URL.Query().Get("fact"),
 	)
 
 	reps, err := s.r.ListLatestHosts(details, filters)
@@ -114,11 +123,7 @@ func (s *service) APIListReports(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	tmpl := template.Must(template.New("index").Funcs(
-		template.FuncMap{
-			"getReportStyle": getReportStyle,
-		},
-	).ParseFS(localTemplates, "templates/index.gohtml"))
+	tmpl := parseIndex(s.getFlapping(reps.Items))
 
 	tmplTpe := struct {
 		Reports *pagefilter.PaginatedResponse[models.Report]
@@ -150,10 +155,12 @@ func (s *service) APIReportsTotal(w http.ResponseWriter, r *http.Request) {
 	}
 
 	filters := s.getListReportFilters(
+		auth.AccessFromContext(r.Context()),
 		r.URL.Query().Get("host"),
 		r.URL.Query().Get("puppet-version"),
 		r.URL.Query().Get("environment"),
 		r.URL.Query().Get("status"),
+		r.URL.Query().Get("fact"),
 	)
 
 	reps, err := s.r.ListLatestHosts(details, filters)
@@ -170,11 +177,7 @@ func (s *service) APIReportsTotal(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	tmpl := template.Must(template.New("index").Funcs(
-		template.FuncMap{
-			"getReportStyle": getReportStyle,
-		},
-	).ParseFS(localTemplates, "templates/index.gohtml"))
+	tmpl := parseIndex(nil)
 
 	tmplTpe := struct {
 		Reports *pagefilter.PaginatedResponse[models.Report]
@@ -188,13 +191,32 @@ func (s *service) APIReportsTotal(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// parseIndex parses the index template, highlighting the flapping hosts.
+func parseIndex(flaps map[string]*hostFlapping) *template.Template {
+	return template.Must(template.New("index").Funcs(
+		template.FuncMap{
+			"getReportStyle": getReportStyle,
+			"flapping": func(host string) *hostFlapping {
+				return flaps[host]
+			},
+		},
+	).ParseFS(localTemplates, "templates/index.gohtml"))
+}
+
+// getListReportFilters returns the filters of the reports, restricted to the environments the reader can read.
 func (s *service) getListReportFilters(
+	access *auth.Access,
 	host string,
 	puppetVersion string,
 	environment string,
 	status string,
+	fact string,
 ) *repo.ListLatestHostsFilters {
 	filters := new(repo.ListLatestHostsFilters)
+	if envs, restricted := access.Environments(); restricted {
+		filters.Environments = envs
+	}
+
 	if host != "" {
 		filters.Hostname = &host
 	}
@@ -211,5 +233,10 @@ func (s *service) getListReportFilters(
 		filters.Status = &status
 	}
 
+	// The fact is searched for as name=value, a search without a value is ignored.
+	if name, value, ok := strings.Cut(fact, "="); ok && name != "" {
+		filters.Facts = map[string]string{name: value}
+	}
+
 	return filters
 }
@@ -5,6 +5,8 @@ import (
 	"net/http"
 
 	"github.com/gorilla/mux"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/auth"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/events"
 	repo "github.com/jacobbrewer1/puppet-reporter/pkg/repositories/web"
 	"github.com/jacobbrewer1/uhttp"
 )
@@ -18,21 +20,55 @@ type Service interface {
 
 type service struct {
 	r repo.Repository
+
+	// login is the OIDC login the users need to see the reports, nil if no login is needed.
+	login *auth.OIDC
+
+	// broker is passed the reports as they are ingested, nil if the reports are not shown live.
+	broker *events.Broker
+
+	// streamFlaps holds what flaps on the hosts of the reports streamed to the pages.
+	streamFlaps *flappingCache
 }
 
-func NewService(r repo.Repository) Service {
-	return &service{
-		r: r,
+func NewService(r repo.Repository, opts ...ServiceOption) Service {
+	s := &service{
+		r:           r,
+		streamFlaps: newFlappingCache(events.DefaultBufferSize),
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
 func (s *service) Register(r *mux.Router, middleware ...http.HandlerFunc) {
 	apiRouter := r.PathPrefix("/api").Subrouter()
 
-	r.HandleFunc("/", wrapHandler(s.indexHandler, middleware...)).Methods(http.MethodGet)
+	if s.login != nil {
+		s.login.Register(r)
+	}
 
-	apiRouter.HandleFunc("/reports", wrapHandler(s.APIListReports, middleware...)).Methods(http.MethodGet)
-	apiRouter.HandleFunc("/reports/total", wrapHandler(s.APIReportsTotal, middleware...)).Methods(http.MethodGet)
+	r.HandleFunc("/", s.authenticate(wrapHandler(s.indexHandler, middleware...))).Methods(http.MethodGet)
+
+	apiRouter.HandleFunc("/reports", s.authenticate(wrapHandler(s.APIListReports, middleware...))).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/reports/total", s.authenticate(wrapHandler(s.APIReportsTotal, middleware...))).Methods(http.MethodGet)
+
+	// The stream is not wrapped, as the events are flushed to the client as they are written and the response of a
+	// request that lasts as long as the page is open says nothing in the metrics.
+	if s.broker != nil {
+		apiRouter.HandleFunc("/reports/stream", s.authenticate(s.APIReportStream)).Methods(http.MethodGet)
+	}
+}
+
+// authenticate requires the user to be logged in, if the login is enabled.
+func (s *service) authenticate(next http.HandlerFunc) http.HandlerFunc {
+	if s.login == nil {
+		return next
+	}
+	return s.login.Require(next)
 }
 
 func wrapHandler(next http.HandlerFunc, middleware ...http.HandlerFunc) http.HandlerFunc {
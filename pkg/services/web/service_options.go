@@ -0,0 +1,22 @@
+package web
+
+import (
+	"github.com/jacobbrewer1/puppet-reporter/pkg/auth"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/events"
+)
+
+type ServiceOption func(s *service)
+
+// WithLogin requires the users to log in with the OIDC provider before they can see the reports.
+func WithLogin(o *auth.OIDC) ServiceOption {
+	return func(s *service) {
+		s.login = o
+	}
+}
+
+// WithBroker shows the reports on the index as they are ingested, the broker must be the one the API publishes to.
+func WithBroker(b *events.Broker) ServiceOption {
+	return func(s *service) {
+		s.broker = b
+	}
+}
@@ -0,0 +1,45 @@
+package web
+
+import (
+	"bytes"
+	"log/slog"
+	"maps"
+	"net/http"
+
+	"github.com/jacobbrewer1/puppet-reporter/pkg/auth"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/events"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/logging"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/models"
+	"github.com/jacobbrewer1/uhttp"
+)
+
+// APIReportStream streams the rows of the reports as they are ingested, for the index to add them to the list.
+func (s *service) APIReportStream(w http.ResponseWriter, r *http.Request) {
+	// The template is parsed once for the connection, the flapping it shows is replaced with that of each report.
+	flaps := make(map[string]*hostFlapping)
+	tmpl := parseIndex(flaps)
+
+	w.Header().Set(uhttp.HeaderContentType, events.ContentTypeEventStream)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	// The reports of every host are sent, the page decides which of them match the search it shows.
+	filter := &events.Filter{
+		Access: auth.AccessFromContext(r.Context()),
+	}
+
+	if err := s.broker.Stream(r.Context(), w, filter, func(report *models.Report) ([]byte, error) {
+		clear(flaps)
+		maps.Copy(flaps, s.streamFlaps.get(report, func() map[string]*hostFlapping {
+			return s.getFlapping([]*models.Report{report})
+		}))
+
+		buf := new(bytes.Buffer)
+		if err := tmpl.ExecuteTemplate(buf, "report_row", report); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}); err != nil {
+		slog.Error("Error streaming reports", slog.String(logging.KeyError, err.Error()))
+	}
+}
@@ -0,0 +1,449 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jacobbrewer1/puppet-reporter/database"
+	"github.com/jacobbrewer1/vaulty/repositories"
+)
+
+var (
+	// ErrSchemaOutOfDate is returned when the database has migrations that have not been applied.
+	ErrSchemaOutOfDate = errors.New("database schema is out of date")
+
+	// ErrSchemaTooNew is returned when the database has migrations applied that this build does not know about.
+	ErrSchemaTooNew = errors.New("database schema is newer than this build")
+
+	// ErrUnknownMigration is returned when a migration version does not exist.
+	ErrUnknownMigration = errors.New("unknown migration")
+
+	// ErrDirtyLegacyMigration is returned when golang-migrate failed part way through a migration, so the schema is
+	// somewhere between two versions and cannot be imported.
+	ErrDirtyLegacyMigration = errors.New("golang-migrate schema version is dirty")
+)
+
+const (
+	// migrationUpSuffix is the suffix of the migrations that are applied.
+	migrationUpSuffix = ".up.sql"
+
+	// migrationDownSuffix is the suffix of the migrations that are rolled back.
+	migrationDownSuffix = ".down.sql"
+)
+
+// migrationSource is where the migrations of a driver are, and how the applied migrations are recorded.
+type migrationSource struct {
+	// fs is the file system with the migrations.
+	fs fs.ReadFileFS
+
+	// dir is the directory of the migrations in the file system.
+	dir string
+
+	// createTableSQL creates the schema_migration table the applied migrations are recorded in.
+	createTableSQL string
+
+	// legacyTableSQL counts the schema_migrations tables golang-migrate recorded the schema version in, before the
+	// binaries applied the migrations themselves.
+	legacyTableSQL string
+}
+
+var migrationSources = map[Driver]migrationSource{
+	DriverMySQL: {
+		fs:  database.MySQLMigrations,
+		dir: "migrations",
+		createTableSQL: `
+			create table if not exists schema_migration
+			(
+				id         int auto_increment primary key,
+				version    varchar(255) not null unique,
+				applied_at datetime     not null
+			)
+		`,
+		legacyTableSQL: "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = 'schema_migrations'",
+	},
+	DriverSQLite: {
+		fs:  database.SQLiteMigrations,
+		dir: "sqlite",
+		createTableSQL: `
+			create table if not exists schema_migration
+			(
+				version    text     not null primary key,
+				applied_at datetime not null
+			)
+		`,
+		legacyTableSQL: "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'",
+	},
+	DriverPostgres: {
+		fs:  database.PostgresMigrations,
+		dir: "postgres",
+		// The table has an id, as the inserts return the id of the inserted row.
+		createTableSQL: `
+			create table if not exists schema_migration
+			(
+				id         serial    primary key,
+				version    text      not null unique,
+				applied_at timestamp not null
+			)
+		`,
+		legacyTableSQL: "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = 'schema_migrations'",
+	},
+}
+
+// MigrationStatus is the state of a migration in the database.
+type MigrationStatus struct {
+	// Version is the version of the migration, the timestamp and name of the migration file.
+	Version string
+
+	// AppliedAt is when the migration was applied, nil if it has not been applied.
+	AppliedAt *time.Time
+
+	// Unknown is true if the migration is applied, but this build does not have it.
+	Unknown bool
+}
+
+// Migrator applies and rolls back the migrations of the database.
+type Migrator struct {
+	// db is the database that is migrated.
+	db *repositories.Database
+
+	// source is where the migrations of the database are.
+	source migrationSource
+
+	// versions are the versions of the migrations, oldest first.
+	versions []string
+}
+
+// NewMigrator creates a new Migrator for the migrations of the database driver.
+func NewMigrator(db *repositories.Database) (*Migrator, error) {
+	source, ok := migrationSources[DriverOf(db)]
+	if !ok {
+		return nil, fmt.Errorf("no migrations for database driver %q", DriverOf(db))
+	}
+
+	files, err := fs.Glob(source.fs, source.dir+"/*"+migrationUpSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("list migrations: %w", err)
+	}
+
+	versions := make([]string, 0, len(files))
+	for _, file := range files {
+		versions = append(versions, strings.TrimSuffix(path.Base(file), migrationUpSuffix))
+	}
+
+	// The migrations are prefixed with their timestamp, so they sort in the order they are applied.
+	sort.Strings(versions)
+
+	m := &Migrator{
+		db:       db,
+		source:   source,
+		versions: versions,
+	}
+
+	if _, err := db.Exec(source.createTableSQL); err != nil {
+		return nil, fmt.Errorf("create migrations table: %w", err)
+	}
+
+	if err := m.importLegacy(); err != nil {
+		return nil, fmt.Errorf("import golang-migrate version: %w", err)
+	}
+
+	return m, nil
+}
+
+// Status returns the state of every migration, oldest first.
+func (m *Migrator) Status() ([]*MigrationStatus, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]*MigrationStatus, 0, len(m.versions))
+	for _, version := range m.versions {
+		s := &MigrationStatus{
+			Version: version,
+		}
+		if at, ok := applied[version]; ok {
+			s.AppliedAt = &at
+			delete(applied, version)
+		}
+		statuses = append(statuses, s)
+	}
+
+	for version, at := range applied {
+		statuses = append(statuses, &MigrationStatus{
+			Version:   version,
+			AppliedAt: &at,
+			Unknown:   true,
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, nil
+}
+
+// Check returns an error if the database schema does not match the migrations of this build.
+func (m *Migrator) Check() error {
+	statuses, err := m.Status()
+	if err != nil {
+		return err
+	}
+
+	pending := make([]string, 0)
+	unknown := make([]string, 0)
+	for _, s := range statuses {
+		switch {
+		case s.Unknown:
+			unknown = append(unknown, s.Version)
+		case s.AppliedAt == nil:
+			pending = append(pending, s.Version)
+		}
+	}
+
+	switch {
+	case len(unknown) > 0:
+		return fmt.Errorf("%w: unknown migrations %s", ErrSchemaTooNew, strings.Join(unknown, ", "))
+	case len(pending) > 0:
+		return fmt.Errorf("%w: pending migrations %s", ErrSchemaOutOfDate, strings.Join(pending, ", "))
+	}
+
+	return nil
+}
+
+// Up applies the migrations that have not been applied yet and returns how many were applied.
+func (m *Migrator) Up() (int, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for _, version := range m.versions {
+		if _, ok := applied[version]; ok {
+			continue
+		}
+
+		if err := m.run(version, migrationUpSuffix, "INSERT INTO schema_migration (version, applied_at) VALUES (?, ?)", version, time.Now().UTC()); err != nil {
+			return n, fmt.Errorf("apply migration %s: %w", version, err)
+		}
+
+		slog.Info("Applied migration", slog.String("version", version))
+		n++
+	}
+
+	return n, nil
+}
+
+// Down rolls back the given number of the most recently applied migrations and returns how many were rolled back.
+func (m *Migrator) Down(steps int) (int, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for i := len(m.versions) - 1; i >= 0 && n < steps; i-- {
+		version := m.versions[i]
+		if _, ok := applied[version]; !ok {
+			continue
+		}
+
+		if err := m.run(version, migrationDownSuffix, "DELETE FROM schema_migration WHERE version = ?", version); err != nil {
+			return n, fmt.Errorf("roll back migration %s: %w", version, err)
+		}
+
+		slog.Info("Rolled back migration", slog.String("version", version))
+		n++
+	}
+
+	return n, nil
+}
+
+// Baseline records every migration up to and including the version as applied, without running them. It is used
+// for databases that were migrated before the migrations were recorded.
+func (m *Migrator) Baseline(version string) (int, error) {
+	idx := sort.SearchStrings(m.versions, version)
+	if idx == len(m.versions) || m.versions[idx] != version {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownMigration, version)
+	}
+
+	applied, err := m.applied()
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for _, v := range m.versions[:idx+1] {
+		if _, ok := applied[v]; ok {
+			continue
+		}
+
+		if _, err := m.db.Exec("INSERT INTO schema_migration (version, applied_at) VALUES (?, ?)", v, time.Now().UTC()); err != nil {
+			return n, fmt.Errorf("record migration %s: %w", v, err)
+		}
+		n++
+	}
+
+	return n, nil
+}
+
+// importLegacy records the migrations up to the version in golang-migrate's schema_migrations table as applied, so
+// the databases it migrated do not need a baseline. It is only done while no migration is recorded, so a later roll
+// back is not undone by the old version.
+func (m *Migrator) importLegacy() error {
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	} else if len(applied) > 0 {
+		return nil
+	}
+
+	tables := 0
+	if err := m.db.Get(&tables, m.source.legacyTableSQL); err != nil {
+		return fmt.Errorf("find schema_migrations table: %w", err)
+	} else if tables == 0 {
+		return nil
+	}
+
+	legacy := struct {
+		Version int64 `db:"version"`
+		Dirty   bool  `db:"dirty"`
+	}{}
+	if err := m.db.Get(&legacy, "SELECT version, dirty FROM schema_migrations"); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("get schema_migrations version: %w", err)
+	}
+
+	if legacy.Dirty {
+		return fmt.Errorf("%w: fix the schema of version %d by hand, then run migrate baseline", ErrDirtyLegacyMigration, legacy.Version)
+	}
+
+	// golang-migrate records the timestamp the migration files start with.
+	version := ""
+	for _, v := range m.versions {
+		prefix, _, _ := strings.Cut(v, "_")
+		if n, err := strconv.ParseInt(prefix, 10, 64); err == nil && n <= legacy.Version {
+			version = v
+		}
+	}
+
+	if version == "" {
+		return nil
+	}
+
+	n, err := m.Baseline(version)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Imported the golang-migrate schema version", slog.String("version", version), slog.Int("count", n))
+
+	return nil
+}
+
+// applied returns when each applied migration was applied.
+func (m *Migrator) applied() (map[string]time.Time, error) {
+	rows := make([]struct {
+		Version   string    `db:"version"`
+		AppliedAt time.Time `db:"applied_at"`
+	}, 0)
+	if err := m.db.Select(&rows, "SELECT version, applied_at FROM schema_migration"); err != nil {
+		return nil, fmt.Errorf("get applied migrations: %w", err)
+	}
+
+	applied := make(map[string]time.Time, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row.AppliedAt
+	}
+
+	return applied, nil
+}
+
+// run runs the statements of the migration file and the query recording it in a single transaction. MySQL commits
+// schema changes straight away, so a failed MySQL migration can leave the statements before it applied.
+func (m *Migrator) run(version, suffix, recordSQL string, args ...any) error {
+	bts, err := m.source.fs.ReadFile(path.Join(m.source.dir, version+suffix))
+	if err != nil {
+		return fmt.Errorf("read migration: %w", err)
+	}
+
+	tx, err := m.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	for _, stmt := range splitStatements(string(bts)) {
+		if _, err := tx.Exec(stmt); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("run migration: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(recordSQL, args...); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("record migration: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// EnsureSchema checks the database schema matches the migrations of this build, applying the pending migrations
+// first if migrate is true.
+func EnsureSchema(db *repositories.Database, migrate bool) error {
+	m, err := NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	if migrate {
+		if _, err := m.Up(); err != nil {
+			return fmt.Errorf("error migrating database: %w", err)
+		}
+	}
+
+	return m.Check()
+}
+
+// splitStatements splits the SQL into its statements, so migrations with several statements can be run without
+// the driver supporting it. Semicolons in quoted strings and identifiers do not end a statement.
+func splitStatements(sqlStr string) []string {
+	stmts := make([]string, 0)
+	sb := new(strings.Builder)
+
+	var quote rune
+	for _, c := range sqlStr {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == ';':
+			if stmt := strings.TrimSpace(sb.String()); stmt != "" {
+				stmts = append(stmts, stmt)
+			}
+			sb.Reset()
+			continue
+		}
+		sb.WriteRune(c)
+	}
+
+	if stmt := strings.TrimSpace(sb.String()); stmt != "" {
+		stmts = append(stmts, stmt)
+	}
+
+	return stmts
+}
@@ -0,0 +1,159 @@
+package storage
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrator(t *testing.T) {
+	db, err := OpenSQLite(SQLiteInMemory)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	m, err := NewMigrator(db)
+	require.NoError(t, err)
+	require.NotEmpty(t, m.versions)
+
+	require.ErrorIs(t, m.Check(), ErrSchemaOutOfDate)
+
+	n, err := m.Up()
+	require.NoError(t, err)
+	require.Equal(t, len(m.versions), n)
+	require.NoError(t, m.Check())
+
+	// Applying the migrations again does nothing.
+	n, err = m.Up()
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	n, err = m.Down(1)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.ErrorIs(t, m.Check(), ErrSchemaOutOfDate)
+
+	// A migration recorded by a newer build is reported.
+	_, err = db.Exec("INSERT INTO schema_migration (version, applied_at) VALUES ('99999999999999_future', CURRENT_TIMESTAMP)")
+	require.NoError(t, err)
+
+	statuses, err := m.Status()
+	require.NoError(t, err)
+	require.True(t, statuses[len(statuses)-1].Unknown)
+	require.ErrorIs(t, m.Check(), ErrSchemaTooNew)
+}
+
+func TestMigrator_Baseline(t *testing.T) {
+	db, err := OpenSQLite(SQLiteInMemory)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	m, err := NewMigrator(db)
+	require.NoError(t, err)
+
+	_, err = m.Baseline("20000101000000_unknown")
+	require.ErrorIs(t, err, ErrUnknownMigration)
+
+	n, err := m.Baseline(m.versions[len(m.versions)-1])
+	require.NoError(t, err)
+	require.Equal(t, len(m.versions), n)
+	require.NoError(t, m.Check())
+}
+
+func TestMigrations_Pairs(t *testing.T) {
+	// Every migration of every driver must be able to be rolled back.
+	for driver, source := range migrationSources {
+		t.Run(string(driver), func(t *testing.T) {
+			ups, err := fs.Glob(source.fs, source.dir+"/*"+migrationUpSuffix)
+			require.NoError(t, err)
+			require.NotEmpty(t, ups)
+
+			for _, up := range ups {
+				down := strings.TrimSuffix(up, migrationUpSuffix) + migrationDownSuffix
+				_, err := source.fs.ReadFile(down)
+				require.NoError(t, err, "missing %s", down)
+			}
+		})
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want []string
+	}{
+		{
+			name: "single statement",
+			sql:  "drop table if exists report;",
+			want: []string{"drop table if exists report"},
+		},
+		{
+			name: "several statements",
+			sql:  "drop table resource;\n\ndrop table report;\n",
+			want: []string{"drop table resource", "drop table report"},
+		},
+		{
+			name: "semicolons in quotes",
+			sql:  "insert into log_message (message) values ('a;b'); select `x;y` from report",
+			want: []string{"insert into log_message (message) values ('a;b')", "select `x;y` from report"},
+		},
+		{
+			name: "empty",
+			sql:  "\n",
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, splitStatements(tt.sql))
+		})
+	}
+}
+
+func TestMigrator_ImportLegacy(t *testing.T) {
+	tests := []struct {
+		name    string
+		dirty   bool
+		applied int
+		err     error
+	}{
+		{
+			name:    "clean",
+			applied: 2,
+		},
+		{
+			name:  "dirty",
+			dirty: true,
+			err:   ErrDirtyLegacyMigration,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := OpenSQLite(SQLiteInMemory)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = db.Close() })
+
+			// The table golang-migrate leaves behind, at the version of the second SQLite migration.
+			_, err = db.Exec("CREATE TABLE schema_migrations (version bigint not null primary key, dirty boolean not null)")
+			require.NoError(t, err)
+			_, err = db.Exec("INSERT INTO schema_migrations (version, dirty) VALUES (20261019130000, ?)", tt.dirty)
+			require.NoError(t, err)
+
+			m, err := NewMigrator(db)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+
+			applied, err := m.applied()
+			require.NoError(t, err)
+			require.Len(t, applied, tt.applied)
+			require.Contains(t, applied, "20261019130000_api_token")
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jacobbrewer1/puppet-reporter/pkg/utils"
+	"github.com/jacobbrewer1/vaulty"
+	"github.com/jacobbrewer1/vaulty/repositories"
+	"github.com/jmoiron/sqlx"
+	"github.com/spf13/viper"
+)
+
+const (
+	// mysqlTimeout is the timeout of establishing a MySQL connection, matching the connections made with Vault.
+	mysqlTimeout = 90 * time.Second
+)
+
+// connectMySQL connects to MySQL. Static credentials from database.dsn or database.username are used if they are
+// set, otherwise the credentials are taken from Vault.
+func connectMySQL(ctx context.Context, v *viper.Viper) (*repositories.Database, error) {
+	switch {
+	case v.GetString("database.dsn") != "" || v.GetString("database.username") != "":
+		dsn, err := mysqlDSN(v)
+		if err != nil {
+			return nil, err
+		}
+
+		db, err := sqlx.Open("mysql", dsn)
+		if err != nil {
+			return nil, fmt.Errorf("error opening database: %w", err)
+		}
+
+		if err := db.PingContext(ctx); err != nil {
+			return nil, fmt.Errorf("error connecting to database: %w", err)
+		}
+
+		slog.Info("Database connection established with static credentials")
+
+		return repositories.NewDatabase(db), nil
+	case v.IsSet("vault.database.role"):
+		return connectMySQLVault(ctx, v)
+	default:
+		return nil, errors.New("no database configuration found, set database.dsn, database.username or vault")
+	}
+}
+
+// mysqlDSN returns the MySQL connection string from database.dsn, or built from database.host, database.schema,
+// database.username and the password. The times are always parsed, the models rely on it.
+func mysqlDSN(v *viper.Viper) (string, error) {
+	if dsn := v.GetString("database.dsn"); dsn != "" {
+		cfg, err := mysql.ParseDSN(dsn)
+		if err != nil {
+			return "", fmt.Errorf("error parsing database.dsn: %w", err)
+		}
+
+		cfg.ParseTime = true
+		return cfg.FormatDSN(), nil
+	}
+
+	password, err := databasePassword(v)
+	if err != nil {
+		return "", err
+	}
+
+	cfg := mysql.NewConfig()
+	cfg.User = v.GetString("database.username")
+	cfg.Passwd = password
+	cfg.Net = "tcp"
+	cfg.Addr = v.GetString("database.host")
+	cfg.DBName = v.GetString("database.schema")
+	cfg.Timeout = mysqlTimeout
+	cfg.MultiStatements = true
+	cfg.ParseTime = true
+
+	return cfg.FormatDSN(), nil
+}
+
+// connectMySQLVault connects to MySQL with the database credentials from Vault.
+func connectMySQLVault(ctx context.Context, v *viper.Viper) (*repositories.Database, error) {
+	slog.Info("Vault configuration found, attempting to connect")
+
+	vc, err := utils.GetVaultClient(ctx, v)
+	if err != nil {
+		return nil, fmt.Errorf("error creating vault client: %w", err)
+	}
+
+	slog.Debug("Vault client created")
+
+	vs, err := vc.Path(v.GetString("vault.database.role"), vaulty.WithPrefix(v.GetString("vault.database.path"))).GetSecret(ctx)
+	if errors.Is(err, vaulty.ErrSecretNotFound) {
+		return nil, fmt.Errorf("secrets not found in vault: %s", v.GetString("vault.database.path"))
+	} else if err != nil {
+		return nil, fmt.Errorf("error getting secrets from vault: %w", err)
+	}
+
+	dbConnector, err := repositories.NewDatabaseConnector(
+		repositories.WithContext(ctx),
+		repositories.WithVaultClient(vc),
+		repositories.WithCurrentSecrets(vs),
+		repositories.WithViper(v),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating database connector: %w", err)
+	}
+
+	db, err := dbConnector.ConnectDB()
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to database: %w", err)
+	}
+
+	slog.Info("Database connection generate from vault secrets")
+
+	return db, nil
+}
@@ -0,0 +1,224 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/jacobbrewer1/vaulty/repositories"
+	"github.com/jmoiron/sqlx"
+	"github.com/spf13/viper"
+)
+
+const (
+	// postgresDriverName is the name the PostgreSQL driver is registered with, it wraps the driver to accept the MySQL
+	// style queries of the models and repositories.
+	postgresDriverName = "puppet-reporter-postgres"
+)
+
+func init() {
+	sql.Register(postgresDriverName, &postgresDriver{parent: stdlib.GetDefaultDriver()})
+}
+
+// OpenPostgres connects to the PostgreSQL database with the given connection string.
+func OpenPostgres(dsn string) (*repositories.Database, error) {
+	db, err := sqlx.Open(postgresDriverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error opening postgres database: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("error connecting to postgres database: %w", err)
+	}
+
+	slog.Info("Connected to postgres database")
+
+	return repositories.NewDatabase(db), nil
+}
+
+// postgresDSN returns the PostgreSQL connection string from database.dsn, or built from database.host,
+// database.schema, database.username and the password.
+func postgresDSN(v *viper.Viper) (string, error) {
+	if dsn := v.GetString("database.dsn"); dsn != "" {
+		return dsn, nil
+	} else if v.GetString("database.username") == "" {
+		return "", errors.New("no database configuration found, set database.dsn or database.username")
+	}
+
+	password, err := databasePassword(v)
+	if err != nil {
+		return "", err
+	}
+
+	u := &url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(v.GetString("database.username"), password),
+		Host:   v.GetString("database.host"),
+		Path:   v.GetString("database.schema"),
+	}
+
+	return u.String(), nil
+}
+
+// rewritePostgres rewrites a MySQL style query for PostgreSQL. The ? placeholders are numbered and the backtick
+// quoted identifiers are double quoted. Quoted strings and identifiers are left as they are.
+func rewritePostgres(query string) string {
+	sb := new(strings.Builder)
+	sb.Grow(len(query) + 8)
+
+	n := 0
+	var quote rune
+	for _, c := range query {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '?':
+			n++
+			sb.WriteByte('$')
+			sb.WriteString(strconv.Itoa(n))
+			continue
+		case c == '`':
+			c = '"'
+		}
+		sb.WriteRune(c)
+	}
+
+	return sb.String()
+}
+
+// isInsert returns true if the query is an insert that does not return anything.
+func isInsert(query string) bool {
+	q := strings.ToUpper(strings.TrimSpace(query))
+	return strings.HasPrefix(q, "INSERT") && !strings.Contains(q, "RETURNING")
+}
+
+// postgresDriver wraps the PostgreSQL driver so the queries written for MySQL work unchanged.
+type postgresDriver struct {
+	parent driver.Driver
+}
+
+func (d *postgresDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.parent.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// The connection must be able to run queries directly, the inserts are rewritten to return their id.
+	if _, ok := conn.(driver.QueryerContext); !ok {
+		_ = conn.Close()
+		return nil, errors.New("postgres driver does not support queries without a prepared statement")
+	}
+
+	return &postgresConn{Conn: conn}, nil
+}
+
+// postgresConn rewrites the queries run on the connection.
+type postgresConn struct {
+	driver.Conn
+}
+
+func (c *postgresConn) Prepare(query string) (driver.Stmt, error) {
+	return c.Conn.Prepare(rewritePostgres(query))
+}
+
+func (c *postgresConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return p.PrepareContext(ctx, rewritePostgres(query))
+	}
+	return c.Prepare(query)
+}
+
+func (c *postgresConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return b.BeginTx(ctx, opts)
+	}
+
+	// Drivers without context support can only begin a transaction with the default options.
+	return c.Conn.Begin()
+}
+
+func (c *postgresConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *postgresConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *postgresConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.Conn.(driver.QueryerContext).QueryContext(ctx, rewritePostgres(query), args)
+}
+
+// ExecContext runs the query. PostgreSQL has no last insert id, so the inserts return the id of the first inserted
+// row instead, the same id MySQL reports for a multi row insert.
+func (c *postgresConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if !isInsert(query) {
+		if e, ok := c.Conn.(driver.ExecerContext); ok {
+			return e.ExecContext(ctx, rewritePostgres(query), args)
+		}
+		return nil, driver.ErrSkip
+	}
+
+	rows, err := c.QueryContext(ctx, query+" RETURNING id", args)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := new(postgresInsertResult)
+	dest := make([]driver.Value, len(rows.Columns()))
+	for {
+		err := rows.Next(dest)
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if res.rows == 0 {
+			id, ok := dest[0].(int64)
+			if !ok {
+				return nil, fmt.Errorf("unexpected inserted id type %T", dest[0])
+			}
+			res.id = id
+		}
+		res.rows++
+	}
+
+	return res, nil
+}
+
+// postgresInsertResult is the result of an insert run on PostgreSQL.
+type postgresInsertResult struct {
+	// id is the id of the first inserted row.
+	id int64
+
+	// rows is the number of inserted rows.
+	rows int64
+}
+
+func (r *postgresInsertResult) LastInsertId() (int64, error) {
+	return r.id, nil
+}
+
+func (r *postgresInsertResult) RowsAffected() (int64, error) {
+	return r.rows, nil
+}
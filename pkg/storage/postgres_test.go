@@ -0,0 +1,178 @@
+package storage
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewritePostgres(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "placeholders",
+			query: "SELECT id FROM report WHERE host = ? AND state = ?",
+			want:  "SELECT id FROM report WHERE host = $1 AND state = $2",
+		},
+		{
+			name:  "backtick identifiers",
+			query: "INSERT INTO report (`hash`, `host`) VALUES (?, ?)",
+			want:  `INSERT INTO report ("hash", "host") VALUES ($1, $2)`,
+		},
+		{
+			name:  "quoted strings are left alone",
+			query: "UPDATE report_queue SET error = 'why?', payload = '' WHERE id = ?",
+			want:  "UPDATE report_queue SET error = 'why?', payload = '' WHERE id = $1",
+		},
+		{
+			name:  "quoted identifiers are left alone",
+			query: `SELECT "odd?column" FROM report WHERE id = ?`,
+			want:  `SELECT "odd?column" FROM report WHERE id = $1`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, rewritePostgres(tt.query))
+		})
+	}
+}
+
+func TestIsInsert(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  bool
+	}{
+		{
+			name:  "insert",
+			query: "\n\tINSERT INTO report (`hash`) VALUES (?)",
+			want:  true,
+		},
+		{
+			name:  "insert returning",
+			query: "insert into report (hash) values (?) returning id",
+			want:  false,
+		},
+		{
+			name:  "update",
+			query: "UPDATE report SET host = ?",
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, isInsert(tt.query))
+		})
+	}
+}
+
+// fakeConn is a connection that records the queries run on it, the queries return the ids.
+type fakeConn struct {
+	queries []string
+	ids     []int64
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *fakeConn) Close() error                        { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.queries = append(c.queries, query)
+	return &fakeRows{ids: c.ids}, nil
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.queries = append(c.queries, query)
+	return driver.RowsAffected(1), nil
+}
+
+type fakeRows struct {
+	ids []int64
+}
+
+func (r *fakeRows) Columns() []string { return []string{"id"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.ids) == 0 {
+		return io.EOF
+	}
+	dest[0], r.ids = r.ids[0], r.ids[1:]
+	return nil
+}
+
+func TestPostgresConn_ExecContext(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		ids       []int64
+		wantQuery string
+		wantID    int64
+		wantRows  int64
+	}{
+		{
+			name:      "insert returns the first id",
+			query:     "INSERT INTO resource (`report_id`, `name`) VALUES (?, ?), (?, ?)",
+			ids:       []int64{7, 8},
+			wantQuery: `INSERT INTO resource ("report_id", "name") VALUES ($1, $2), ($3, $4) RETURNING id`,
+			wantID:    7,
+			wantRows:  2,
+		},
+		{
+			name:      "update is run as it is",
+			query:     "UPDATE report_queue SET status = ? WHERE id = ?",
+			wantQuery: "UPDATE report_queue SET status = $1 WHERE id = $2",
+			wantRows:  1,
+		},
+		{
+			name:      "insert returning is run as it is",
+			query:     "INSERT INTO report (hash) VALUES (?) RETURNING id",
+			wantQuery: "INSERT INTO report (hash) VALUES ($1) RETURNING id",
+			wantRows:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fc := &fakeConn{ids: tt.ids}
+			conn, err := (&postgresDriver{parent: fakeDriver{conn: fc}}).Open("")
+			require.NoError(t, err)
+
+			res, err := conn.(driver.ExecerContext).ExecContext(context.Background(), tt.query, nil)
+			require.NoError(t, err)
+			require.Equal(t, []string{tt.wantQuery}, fc.queries)
+
+			rows, err := res.RowsAffected()
+			require.NoError(t, err)
+			require.Equal(t, tt.wantRows, rows)
+
+			if tt.wantID != 0 {
+				id, err := res.LastInsertId()
+				require.NoError(t, err)
+				require.Equal(t, tt.wantID, id)
+			}
+		})
+	}
+}
+
+type fakeDriver struct {
+	conn *fakeConn
+}
+
+func (d fakeDriver) Open(string) (driver.Conn, error) { return d.conn, nil }
+
+func TestDriver_Like(t *testing.T) {
+	require.Equal(t, "t.host LIKE ?", DriverMySQL.Like("t.host"))
+	require.Equal(t, "t.host LIKE ?", DriverSQLite.Like("t.host"))
+	require.Equal(t, "t.host ILIKE ?", DriverPostgres.Like("t.host"))
+	require.Equal(t, "t.host ~ ?", DriverPostgres.Regexp("t.host"))
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sync"
+
+	"github.com/jacobbrewer1/vaulty/repositories"
+	"github.com/jmoiron/sqlx"
+	"modernc.org/sqlite"
+)
+
+const (
+	// SQLiteInMemory is the path of an in memory SQLite database, used by the tests.
+	SQLiteInMemory = ":memory:"
+
+	// sqliteDriverName is the database/sql driver name of the SQLite driver.
+	sqliteDriverName = "sqlite"
+)
+
+// sqliteRegexps caches the compiled regular expressions, the function is called for every row.
+var sqliteRegexps sync.Map
+
+func init() {
+	// SQLite parses the REGEXP operator but leaves the regexp function it calls to the application.
+	sqlite.MustRegisterDeterministicScalarFunction("regexp", 2, sqliteRegexp)
+}
+
+// OpenSQLite opens the SQLite database at the given path, creating it if it does not exist.
+func OpenSQLite(dbPath string) (*repositories.Database, error) {
+	// Foreign keys are off by default in SQLite, and the times are written in a format that sorts as text.
+	dsn := fmt.Sprintf(
+		"file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_time_format=sqlite",
+		dbPath,
+	)
+
+	db, err := sqlx.Open(sqliteDriverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error opening sqlite database: %w", err)
+	}
+
+	// SQLite only allows one writer, a single connection avoids busy errors between our own connections. It also
+	// keeps an in memory database alive, as each connection would otherwise get its own database.
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+	db.SetConnMaxLifetime(0)
+
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("error connecting to sqlite database: %w", err)
+	}
+
+	slog.Info("Connected to sqlite database", slog.String("path", dbPath))
+
+	return repositories.NewDatabase(db), nil
+}
+
+// sqliteRegexp implements regexp(pattern, value) for the REGEXP operator, matching NULL values never.
+func sqliteRegexp(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	if args[0] == nil || args[1] == nil {
+		return nil, nil
+	}
+
+	pattern := fmt.Sprint(args[0])
+	re, ok := sqliteRegexps.Load(pattern)
+	if !ok {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("compile regexp: %w", err)
+		}
+		re, _ = sqliteRegexps.LoadOrStore(pattern, compiled)
+	}
+
+	var value string
+	switch v := args[1].(type) {
+	case []byte:
+		value = string(v)
+	default:
+		value = fmt.Sprint(v)
+	}
+
+	return re.(*regexp.Regexp).MatchString(value), nil
+}
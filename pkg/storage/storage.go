@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jacobbrewer1/pagefilter"
+	"github.com/jacobbrewer1/vaulty/repositories"
+	"github.com/spf13/viper"
+)
+
+// Driver is the database the repositories are stored in.
+type Driver string
+
+const (
+	// DriverMySQL stores the repositories in MySQL or MariaDB, the credentials are taken from Vault.
+	DriverMySQL Driver = "mysql"
+
+	// DriverSQLite stores the repositories in a SQLite file.
+	DriverSQLite Driver = "sqlite"
+
+	// DriverPostgres stores the repositories in PostgreSQL.
+	DriverPostgres Driver = "postgres"
+)
+
+const (
+	// defaultSQLitePath is the SQLite database file used when database.sqlite.path is not set.
+	defaultSQLitePath = "puppet-reporter.db"
+)
+
+// Connect connects to the database configured by database.driver, defaulting to MySQL.
+func Connect(ctx context.Context, v *viper.Viper) (*repositories.Database, error) {
+	v.SetDefault("database.driver", string(DriverMySQL))
+	v.SetDefault("database.sqlite.path", defaultSQLitePath)
+
+	switch driver := Driver(v.GetString("database.driver")); driver {
+	case DriverMySQL:
+		return connectMySQL(ctx, v)
+	case DriverSQLite:
+		return OpenSQLite(v.GetString("database.sqlite.path"))
+	case DriverPostgres:
+		dsn, err := postgresDSN(v)
+		if err != nil {
+			return nil, err
+		}
+
+		return OpenPostgres(dsn)
+	default:
+		return nil, fmt.Errorf("unknown database driver %q", driver)
+	}
+}
+
+// databasePassword returns the database password from database.password, or read from database.password_file so
+// it can be mounted as a secret.
+func databasePassword(v *viper.Viper) (string, error) {
+	file := v.GetString("database.password_file")
+	if file == "" {
+		return v.GetString("database.password"), nil
+	}
+
+	bts, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("error reading database password file: %w", err)
+	}
+
+	return strings.TrimRight(string(bts), "\r\n"), nil
+}
+
+// DriverOf returns the driver of the database connection.
+func DriverOf(db *repositories.Database) Driver {
+	switch db.DriverName() {
+	case postgresDriverName:
+		return DriverPostgres
+	case sqliteDriverName:
+		return DriverSQLite
+	default:
+		return DriverMySQL
+	}
+}
+
+// Like returns a case-insensitive LIKE condition on the column for the driver. MySQL and SQLite compare with LIKE
+// case-insensitively already, PostgreSQL needs ILIKE.
+func (d Driver) Like(column string) string {
+	if d == DriverPostgres {
+		return column + " ILIKE ?"
+	}
+	return column + " LIKE ?"
+}
+
+// Regexp returns a condition matching the column against a regular expression for the driver. SQLite has no regular
+// expressions of its own, the driver registers the regexp function the REGEXP operator calls.
+func (d Driver) Regexp(column string) string {
+	if d == DriverPostgres {
+		return column + " ~ ?"
+	}
+	return column + " REGEXP ?"
+}
+
+// FirstPage sets the last id of the first page to 0 once the pivot has been found. The paginator compares the ids of
+// the rows with the last id of the page before, which is empty on the first page. MySQL compares the empty string as
+// 0, but SQLite orders every number before a string and PostgreSQL cannot compare it with a number at all, so the
+// first row of the page would be missing.
+func FirstPage(details *pagefilter.PaginatorDetails) {
+	if details.LastID == "" {
+		details.LastID = "0"
+	}
+}
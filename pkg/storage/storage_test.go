@@ -0,0 +1,17 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnect_UnknownDriver(t *testing.T) {
+	v := viper.New()
+	v.Set("database.driver", "oracle")
+
+	_, err := Connect(context.Background(), v)
+	require.EqualError(t, err, `unknown database driver "oracle"`)
+}
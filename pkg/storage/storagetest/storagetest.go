@@ -0,0 +1,99 @@
+// Package storagetest provides migrated databases of each supported driver for the repository tests.
+package storagetest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/jacobbrewer1/puppet-reporter/pkg/storage"
+	"github.com/jacobbrewer1/vaulty/repositories"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// EnvPostgresDSN is the environment variable with the connection URL of the PostgreSQL server to test against,
+	// for example the postgres service of the docker-compose file.
+	EnvPostgresDSN = "PUPPET_REPORTER_TEST_POSTGRES_DSN"
+)
+
+// Databases returns an empty, migrated database for each driver that can be tested. SQLite is always tested, in
+// memory, and PostgreSQL when EnvPostgresDSN is set.
+func Databases(t *testing.T) map[storage.Driver]*repositories.Database {
+	t.Helper()
+
+	dbs := map[storage.Driver]*repositories.Database{
+		storage.DriverSQLite: sqliteDatabase(t),
+	}
+
+	if db := postgresDatabase(t); db != nil {
+		dbs[storage.DriverPostgres] = db
+	}
+
+	return dbs
+}
+
+// sqliteDatabase returns a migrated in memory SQLite database.
+func sqliteDatabase(t *testing.T) *repositories.Database {
+	t.Helper()
+
+	db, err := storage.OpenSQLite(storage.SQLiteInMemory)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	migrate(t, db)
+
+	return db
+}
+
+// postgresDatabase returns a migrated PostgreSQL database, or nil if PostgreSQL is not configured.
+// Each test gets its own schema, which is dropped when the test finishes.
+func postgresDatabase(t *testing.T) *repositories.Database {
+	t.Helper()
+
+	dsn := os.Getenv(EnvPostgresDSN)
+	if dsn == "" {
+		return nil
+	}
+
+	admin, err := storage.OpenPostgres(dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = admin.Close() })
+
+	suffix := make([]byte, 8)
+	_, err = rand.Read(suffix)
+	require.NoError(t, err)
+
+	schema := "test_" + hex.EncodeToString(suffix)
+	_, err = admin.Exec("CREATE SCHEMA " + schema)
+	require.NoError(t, err)
+	t.Cleanup(func() { _, _ = admin.Exec("DROP SCHEMA " + schema + " CASCADE") })
+
+	u, err := url.Parse(dsn)
+	require.NoError(t, err)
+
+	q := u.Query()
+	q.Set("search_path", schema)
+	u.RawQuery = q.Encode()
+
+	db, err := storage.OpenPostgres(u.String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	migrate(t, db)
+
+	return db
+}
+
+// migrate applies every migration to the database.
+func migrate(t *testing.T, db *repositories.Database) {
+	t.Helper()
+
+	m, err := storage.NewMigrator(db)
+	require.NoError(t, err)
+
+	_, err = m.Up()
+	require.NoError(t, err)
+}
@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// EnvPrefix is the prefix of the environment variables that override the config. The rest of the name is the config
+// key in upper case with the dots replaced by underscores, for example PUPPET_REPORTER_DATABASE_DSN for database.dsn.
+var EnvPrefix = strings.ToUpper(appName)
+
+// LoadConfig reads the config file at the location and overrides it with the environment variables. The config file
+// is optional, so the service can be configured with the environment variables alone.
+func LoadConfig(location string) (*viper.Viper, error) {
+	v := viper.New()
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	v.SetConfigFile(location)
+	if err := v.ReadInConfig(); errors.Is(err, fs.ErrNotExist) {
+		slog.Info("Config file not found, using the environment only", slog.String("location", location))
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+
+	return v, nil
+}
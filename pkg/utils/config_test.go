@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	location := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(location, []byte(`{"database": {"driver": "mysql", "schema": "puppetreporter"}}`), 0o600))
+
+	t.Setenv("PUPPET_REPORTER_DATABASE_DRIVER", "sqlite")
+	t.Setenv("PUPPET_REPORTER_DATABASE_SQLITE_PATH", "/var/lib/puppet-reporter/reports.db")
+
+	v, err := LoadConfig(location)
+	require.NoError(t, err)
+	require.Equal(t, "sqlite", v.GetString("database.driver"))
+	require.Equal(t, "/var/lib/puppet-reporter/reports.db", v.GetString("database.sqlite.path"))
+	require.Equal(t, "puppetreporter", v.GetString("database.schema"))
+}
+
+func TestLoadConfig_NoFile(t *testing.T) {
+	t.Setenv("PUPPET_REPORTER_DATABASE_DSN", "reporter:secret@tcp(localhost:3306)/puppetreporter")
+
+	v, err := LoadConfig(filepath.Join(t.TempDir(), "config.json"))
+	require.NoError(t, err)
+	require.Equal(t, "reporter:secret@tcp(localhost:3306)/puppetreporter", v.GetString("database.dsn"))
+}
+
+func TestLoadConfig_Invalid(t *testing.T) {
+	location := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(location, []byte(`{`), 0o600))
+
+	_, err := LoadConfig(location)
+	require.ErrorContains(t, err, "error reading config file")
+}
@@ -0,0 +1,48 @@
+// Package version holds the version the binaries are built at, and the command printing it.
+package version
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"runtime"
+
+	"github.com/google/subcommands"
+)
+
+// Set at linking time, with -ldflags "-X github.com/jacobbrewer1/puppet-reporter/pkg/version.Commit=..."
+var (
+	Commit string
+	Date   string
+)
+
+// Cmd prints the version of the binary.
+type Cmd struct{}
+
+func (v *Cmd) Name() string {
+	return "version"
+}
+
+func (v *Cmd) Synopsis() string {
+	return "Print application version information and exit"
+}
+
+func (v *Cmd) Usage() string {
+	return `version:
+  Print application version information and exit.
+`
+}
+
+func (v *Cmd) SetFlags(f *flag.FlagSet) {}
+
+func (v *Cmd) Execute(_ context.Context, _ *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	fmt.Printf(
+		"Commit: %s\nRuntime: %s %s/%s\nDate: %s\n",
+		Commit,
+		runtime.Version(),
+		runtime.GOOS,
+		runtime.GOARCH,
+		Date,
+	)
+	return subcommands.ExitSuccess
+}